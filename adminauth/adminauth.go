@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package adminauth
+
+import (
+	"crypto/subtle"
+	"strings"
+)
+
+// Operator returns the name of the admin owning the api key. The admin keys are "name:key" pairs separated by
+// commas, as the adminkeys of the app config.
+func Operator(adminKeys string, apiKey string) (string, bool) {
+	if apiKey == "" {
+		return "", false
+	}
+	for _, item := range strings.Split(adminKeys, ",") {
+		pair := strings.SplitN(strings.TrimSpace(item), ":", 2)
+		if len(pair) != 2 || pair[1] == "" {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(pair[1]), []byte(apiKey)) == 1 {
+			return pair[0], true
+		}
+	}
+	return "", false
+}
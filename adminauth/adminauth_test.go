@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package adminauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperator(t *testing.T) {
+	keys := "alice:key-a, bob:key-b,broken,empty:"
+	cases := []struct {
+		apiKey   string
+		operator string
+		ok       bool
+	}{
+		{"key-a", "alice", true},
+		{"key-b", "bob", true},
+		{"", "", false},
+		{"broken", "", false},
+		{"key-c", "", false},
+	}
+	for _, c := range cases {
+		operator, ok := Operator(keys, c.apiKey)
+		assert.Equal(t, c.ok, ok, c.apiKey)
+		assert.Equal(t, c.operator, operator, c.apiKey)
+	}
+	_, ok := Operator("", "key-a")
+	assert.False(t, ok)
+}
@@ -36,9 +36,13 @@ func startDeploy(cfg *conf.DeployConfig) {
 	if err != nil {
 		panic(err)
 	}
-	err = db.Debug().AutoMigrate(&models.Chain{}, &models.WrapperTransaction{}, &models.ChainFee{}, &models.TokenBasic{}, &models.Token{}, &models.PriceMarket{},
+	err = db.Debug().AutoMigrate(&models.Chain{}, &models.WrapperTransaction{}, &models.ChainFee{}, &models.AssetFee{}, &models.TokenBasic{}, &models.Token{}, &models.PriceMarket{},
 		&models.TokenMap{}, &models.SrcTransaction{}, &models.SrcTransfer{}, &models.PolyTransaction{}, &models.DstTransaction{}, &models.DstTransfer{},
-		&models.SrcSwap{}, &models.DstSwap{})
+		&models.SrcSwap{}, &models.DstSwap{}, &models.TokenPriceHistory{}, &models.FeeReconciliation{}, &models.FeePolicy{}, &models.AuditLog{}, &models.TokenMapVerification{}, &models.TokenLiquidity{}, &models.ApiKey{}, &models.NFTProfile{}, &models.NFTProfileAttribute{}, &models.NFTOwnership{}, &models.NFTIndexCursor{})
+	if err != nil {
+		panic(err)
+	}
+	err = migrateNFTTables(db)
 	if err != nil {
 		panic(err)
 	}
@@ -47,9 +47,44 @@ var (
 
 	cmdFlag = cli.UintFlag{
 		Name:  "cmd",
-		Usage: "which command? 1:init poly bridge 2:dump status 3:update token information 4:update bridge 5:update transactions",
+		Usage: "which command? 1:init poly bridge 2:dump status 3:update token information 4:update bridge 5:update transactions 7:export fee reconciliation 8:sync token information 9:verify token maps",
 		Value: 2,
 	}
+
+	periodFlag = cli.StringFlag{
+		Name:  "period",
+		Usage: "fee reconciliation period, day or week",
+		Value: "day",
+	}
+
+	startTimeFlag = cli.Uint64Flag{
+		Name:  "start",
+		Usage: "fee reconciliation start time, 30 days before end time if not set",
+		Value: 0,
+	}
+
+	endTimeFlag = cli.Uint64Flag{
+		Name:  "end",
+		Usage: "fee reconciliation end time, now if not set",
+		Value: 0,
+	}
+
+	yesFlag = cli.BoolFlag{
+		Name:  "yes",
+		Usage: "apply the token sync plan without confirmation",
+	}
+
+	chainConfigFlag = cli.StringFlag{
+		Name:  "chainconfig",
+		Usage: "bridge config file `<path>` with the chain nodes and lock proxies, used to verify token maps",
+		Value: "./conf/config_mainnet.json",
+	}
+
+	outputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "fee reconciliation csv file `<path>`",
+		Value: "./fee_reconciliation.csv",
+	}
 )
 
 //getFlagName deal with short flag, and return the flag name whether flag name have short name
@@ -72,6 +107,12 @@ func setupApp() *cli.App {
 		configPathFlag,
 		logDirFlag,
 		cmdFlag,
+		periodFlag,
+		startTimeFlag,
+		endTimeFlag,
+		outputFlag,
+		yesFlag,
+		chainConfigFlag,
 	}
 	app.Commands = []cli.Command{}
 	app.Before = func(context *cli.Context) error {
@@ -128,6 +169,32 @@ func startServer(ctx *cli.Context) {
 		startTransactions(config)
 	} else if cmd == 6 {
 		merge()
+	} else if cmd == 7 {
+		configFile := ctx.GlobalString(getFlagName(configPathFlag))
+		config := conf.NewDeployConfig(configFile)
+		if config == nil {
+			fmt.Printf("startServer - read config failed!")
+			return
+		}
+		exportFeeReconciliation(config.DBConfig, ctx.GlobalString(getFlagName(periodFlag)), ctx.GlobalUint64(getFlagName(startTimeFlag)),
+			ctx.GlobalUint64(getFlagName(endTimeFlag)), ctx.GlobalString(getFlagName(outputFlag)))
+	} else if cmd == 8 {
+		configFile := ctx.GlobalString(getFlagName(configPathFlag))
+		config := conf.NewDeployConfig(configFile)
+		if config == nil {
+			fmt.Printf("startServer - read config failed!")
+			return
+		}
+		startSyncToken(config, ctx.GlobalBool(getFlagName(yesFlag)))
+		dumpStatus(config.DBConfig)
+	} else if cmd == 9 {
+		configFile := ctx.GlobalString(getFlagName(configPathFlag))
+		config := conf.NewDeployConfig(configFile)
+		if config == nil {
+			fmt.Printf("startServer - read config failed!")
+			return
+		}
+		startVerifyTokenMap(config.DBConfig, ctx.GlobalString(getFlagName(chainConfigFlag)))
 	}
 }
 
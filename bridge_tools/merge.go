@@ -2,11 +2,12 @@ package main
 
 import (
 	"encoding/json"
-	"github.com/cosmos/cosmos-sdk/types"
+	"fmt"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
+	"poly-bridge/chainaddr"
 	"poly-bridge/conf"
 	"poly-bridge/crosschaindao/explorerdao"
 	"poly-bridge/models"
@@ -41,13 +42,6 @@ func merge() {
 		panic(err)
 	}
 
-	{
-		config := types.GetConfig()
-		config.SetBech32PrefixForAccount("swth", "swthpub")
-		config.SetBech32PrefixForValidator("swthvaloper", "swthvaloperpub")
-		config.SetBech32PrefixForConsensusNode("swthvalcons", "swthvalconspub")
-	}
-
 	selectNum := 1000
 	count := 0
 	for true {
@@ -68,13 +62,13 @@ func merge() {
 				panic(err)
 			}
 			for _, transaction := range newSrcTransactions {
-				transaction.User = basedef.Address2Hash(transaction.ChainId, transaction.User)
+				transaction.User = hashOf(transaction.ChainId, transaction.User)
 				if transaction.SrcTransfer != nil {
 					if transaction.SrcTransfer.ChainId != basedef.COSMOS_CROSSCHAIN_ID {
-						transaction.SrcTransfer.From = basedef.Address2Hash(transaction.SrcTransfer.ChainId, transaction.SrcTransfer.From)
+						transaction.SrcTransfer.From = hashOf(transaction.SrcTransfer.ChainId, transaction.SrcTransfer.From)
 					}
-					transaction.SrcTransfer.To = basedef.Address2Hash(transaction.SrcTransfer.ChainId, transaction.SrcTransfer.To)
-					transaction.SrcTransfer.DstUser = basedef.Address2Hash(transaction.SrcTransfer.DstChainId, transaction.SrcTransfer.DstUser)
+					transaction.SrcTransfer.To = hashOf(transaction.SrcTransfer.ChainId, transaction.SrcTransfer.To)
+					transaction.SrcTransfer.DstUser = hashOf(transaction.SrcTransfer.DstChainId, transaction.SrcTransfer.DstUser)
 				}
 				if transaction.ChainId == basedef.ETHEREUM_CROSSCHAIN_ID {
 					transaction.Hash, transaction.Key = transaction.Key, transaction.Hash
@@ -138,8 +132,8 @@ func merge() {
 			}
 			for _, transaction := range newDstTransactions {
 				if transaction.DstTransfer != nil {
-					transaction.DstTransfer.From = basedef.Address2Hash(transaction.DstTransfer.ChainId, transaction.DstTransfer.From)
-					transaction.DstTransfer.To = basedef.Address2Hash(transaction.DstTransfer.ChainId, transaction.DstTransfer.To)
+					transaction.DstTransfer.From = hashOf(transaction.DstTransfer.ChainId, transaction.DstTransfer.From)
+					transaction.DstTransfer.To = hashOf(transaction.DstTransfer.ChainId, transaction.DstTransfer.To)
 				}
 			}
 			result = newswapdb.Save(newDstTransactions)
@@ -153,3 +147,12 @@ func merge() {
 		}
 	}
 }
+
+func hashOf(chainId uint64, address string) string {
+	hash, err := chainaddr.ToHash(chainId, address)
+	if err != nil {
+		fmt.Printf("address %s of chain %d is invalid: %v\n", address, chainId, err)
+		return address
+	}
+	return hash
+}
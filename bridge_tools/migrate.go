@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"gorm.io/gorm"
+	"poly-bridge/models"
+)
+
+// migrateNFTTables applies the changes of the NFT tables AutoMigrate does not make to the existing tables:
+// the token ids are widened to hold any uint256, and the ownership index includes the owner since an ERC1155
+// token has many owners.
+func migrateNFTTables(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, model := range []interface{}{&models.NFTProfile{}, &models.NFTProfileAttribute{}} {
+		if err := migrator.AlterColumn(model, "NftTokenId"); err != nil {
+			return err
+		}
+	}
+	var owners int64
+	err := db.Raw("select count(*) from information_schema.statistics where table_schema = database() and table_name = ? and index_name = ? and column_name = ?",
+		"nft_ownerships", "idx_nft_ownership", "owner").Scan(&owners).Error
+	if err != nil || owners > 0 {
+		return err
+	}
+	if migrator.HasIndex(&models.NFTOwnership{}, "idx_nft_ownership") {
+		if err := migrator.DropIndex(&models.NFTOwnership{}, "idx_nft_ownership"); err != nil {
+			return err
+		}
+	}
+	return migrator.CreateIndex(&models.NFTOwnership{}, "idx_nft_ownership")
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"os"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"time"
+)
+
+func exportFeeReconciliation(dbCfg *conf.DBConfig, period string, startTime uint64, endTime uint64, output string) {
+	if period != models.ReconciliationPeriodDay && period != models.ReconciliationPeriodWeek {
+		panic(fmt.Errorf("period: %s is not supported", period))
+	}
+	if endTime == 0 {
+		endTime = uint64(time.Now().Unix())
+	}
+	if startTime == 0 {
+		startTime = endTime - 30*24*60*60
+	}
+	Logger := logger.Default
+	if dbCfg.Debug == true {
+		Logger = Logger.LogMode(logger.Info)
+	}
+	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
+		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	if err != nil {
+		panic(err)
+	}
+	reconciliations := make([]*models.FeeReconciliation, 0)
+	res := db.Where("ind = 1 and time >= ? and time < ?", startTime, endTime).Find(&reconciliations)
+	if res.Error != nil {
+		panic(res.Error)
+	}
+	stats := models.AggregateFeeReconciliations(reconciliations, period)
+	file, err := os.Create(output)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"period", "src_chain_id", "dst_chain_id", "fee_token", "count", "fee_usd", "cost_usd", "margin_usd"})
+	for _, stat := range stats {
+		statRsp := models.MakeFeeReconciliationStatRsp(stat)
+		writer.Write([]string{
+			time.Unix(statRsp.Period, 0).UTC().Format("2006-01-02"),
+			fmt.Sprintf("%d", statRsp.SrcChainId),
+			fmt.Sprintf("%d", statRsp.DstChainId),
+			statRsp.FeeTokenBasicName,
+			fmt.Sprintf("%d", statRsp.Count),
+			statRsp.FeeUsd,
+			statRsp.CostUsd,
+			statRsp.Margin,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		panic(err)
+	}
+	fmt.Printf("export %d fee reconciliation stats to %s\n", len(stats), output)
+}
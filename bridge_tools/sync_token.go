@@ -0,0 +1,542 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"os"
+	"poly-bridge/bridge_tools/conf"
+	"poly-bridge/models"
+	"strings"
+)
+
+const (
+	syncAdd    = "add"
+	syncUpdate = "update"
+	syncRemove = "remove"
+)
+
+// syncAction is one change of the plan that brings the database in line with the config.
+type syncAction struct {
+	kind   string
+	table  string
+	key    string
+	detail string
+	apply  func(tx *gorm.DB) error
+}
+
+func (action *syncAction) String() string {
+	if action.detail == "" {
+		return fmt.Sprintf("%-6s %-12s %s", action.kind, action.table, action.key)
+	}
+	return fmt.Sprintf("%-6s %-12s %s %s", action.kind, action.table, action.key, action.detail)
+}
+
+// tokenSyncState is the token and chain configuration, either in the database or in the config file.
+type tokenSyncState struct {
+	chains       []*models.Chain
+	chainFees    []*models.ChainFee
+	tokenBasics  []*models.TokenBasic
+	priceMarkets []*models.PriceMarket
+	tokens       []*models.Token
+	tokenMaps    []*models.TokenMap
+}
+
+func startSyncToken(cfg *conf.DeployConfig, yes bool) {
+	dbCfg := cfg.DBConfig
+	Logger := logger.Default
+	if dbCfg.Debug == true {
+		Logger = Logger.LogMode(logger.Info)
+	}
+	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
+		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	if err != nil {
+		panic(err)
+	}
+	current, err := loadTokenSyncState(db)
+	if err != nil {
+		panic(err)
+	}
+	actions := diffTokenSyncState(current, configTokenSyncState(cfg))
+	if len(actions) == 0 {
+		fmt.Printf("token information is up to date\n")
+		return
+	}
+	fmt.Printf("sync plan:\n")
+	for _, action := range actions {
+		fmt.Printf("%s\n", action)
+	}
+	if !yes {
+		fmt.Printf("apply %d changes? [y/N]: ", len(actions))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Printf("sync is cancelled\n")
+			return
+		}
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, action := range actions {
+			if err := action.apply(tx); err != nil {
+				return fmt.Errorf("%s: %v", action, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%d changes are applied\n", len(actions))
+}
+
+func loadTokenSyncState(db *gorm.DB) (*tokenSyncState, error) {
+	state := &tokenSyncState{}
+	if res := db.Find(&state.chains); res.Error != nil {
+		return nil, res.Error
+	}
+	if res := db.Find(&state.chainFees); res.Error != nil {
+		return nil, res.Error
+	}
+	if res := db.Find(&state.tokenBasics); res.Error != nil {
+		return nil, res.Error
+	}
+	if res := db.Find(&state.priceMarkets); res.Error != nil {
+		return nil, res.Error
+	}
+	if res := db.Find(&state.tokens); res.Error != nil {
+		return nil, res.Error
+	}
+	if res := db.Find(&state.tokenMaps); res.Error != nil {
+		return nil, res.Error
+	}
+	return state, nil
+}
+
+// configTokenSyncState flattens the config the same way startUpdateToken saves it.
+func configTokenSyncState(cfg *conf.DeployConfig) *tokenSyncState {
+	state := &tokenSyncState{
+		chains: cfg.Chains,
+	}
+	for _, chainFee := range cfg.ChainFees {
+		tokenBasicName := chainFee.TokenBasicName
+		if tokenBasicName == "" && chainFee.TokenBasic != nil {
+			tokenBasicName = chainFee.TokenBasic.Name
+		}
+		state.chainFees = append(state.chainFees, &models.ChainFee{
+			ChainId:        chainFee.ChainId,
+			TokenBasicName: tokenBasicName,
+			MaxFee:         chainFee.MaxFee,
+			MinFee:         chainFee.MinFee,
+			ProxyFee:       chainFee.ProxyFee,
+		})
+	}
+	for _, tokenBasic := range cfg.TokenBasics {
+		state.tokenBasics = append(state.tokenBasics, tokenBasic)
+		for _, priceMarket := range tokenBasic.PriceMarkets {
+			priceMarket.TokenBasicName = tokenBasic.Name
+			state.priceMarkets = append(state.priceMarkets, priceMarket)
+		}
+		for _, token := range tokenBasic.Tokens {
+			token.Hash = strings.ToLower(token.Hash)
+			token.TokenBasicName = tokenBasic.Name
+			state.tokens = append(state.tokens, token)
+		}
+		for _, tokenSrc := range tokenBasic.Tokens {
+			for _, tokenDst := range tokenBasic.Tokens {
+				if tokenDst.ChainId != tokenSrc.ChainId {
+					state.tokenMaps = append(state.tokenMaps, &models.TokenMap{
+						SrcChainId:   tokenSrc.ChainId,
+						SrcTokenHash: tokenSrc.Hash,
+						DstChainId:   tokenDst.ChainId,
+						DstTokenHash: tokenDst.Hash,
+						Standard:     tokenBasic.Standard,
+						Property:     1,
+					})
+				}
+			}
+		}
+	}
+	for _, tokenMap := range cfg.TokenMaps {
+		tokenMap.SrcTokenHash = strings.ToLower(tokenMap.SrcTokenHash)
+		tokenMap.DstTokenHash = strings.ToLower(tokenMap.DstTokenHash)
+		state.tokenMaps = append(state.tokenMaps, tokenMap)
+	}
+	return state
+}
+
+// diffTokenSyncState returns the actions that turn current into expected. Listener heights, prices and fees
+// in current are kept, and the actions are ordered so that references are added before and removed after use.
+func diffTokenSyncState(current *tokenSyncState, expected *tokenSyncState) []*syncAction {
+	chainAdds, chainRemoves := diffChains(current.chains, expected.chains)
+	chainFeeAdds, chainFeeRemoves := diffChainFees(current.chainFees, expected.chainFees)
+	tokenBasicAdds, tokenBasicRemoves := diffTokenBasics(current.tokenBasics, expected.tokenBasics)
+	priceMarketAdds, priceMarketRemoves := diffPriceMarkets(current.priceMarkets, expected.priceMarkets)
+	tokenAdds, tokenRemoves := diffTokens(current.tokens, expected.tokens)
+	tokenMapAdds, tokenMapRemoves := diffTokenMaps(current.tokenMaps, expected.tokenMaps)
+	actions := make([]*syncAction, 0)
+	for _, removes := range [][]*syncAction{tokenMapRemoves, tokenRemoves, priceMarketRemoves, chainFeeRemoves, tokenBasicRemoves, chainRemoves} {
+		actions = append(actions, removes...)
+	}
+	for _, adds := range [][]*syncAction{chainAdds, tokenBasicAdds, chainFeeAdds, priceMarketAdds, tokenAdds, tokenMapAdds} {
+		actions = append(actions, adds...)
+	}
+	return actions
+}
+
+func diffChains(current []*models.Chain, expected []*models.Chain) ([]*syncAction, []*syncAction) {
+	key2Current := make(map[uint64]*models.Chain)
+	for _, chain := range current {
+		key2Current[*chain.ChainId] = chain
+	}
+	key2Expected := make(map[uint64]bool)
+	updates := make([]*syncAction, 0)
+	for _, chain := range expected {
+		chain := chain
+		key := fmt.Sprintf("%d", *chain.ChainId)
+		key2Expected[*chain.ChainId] = true
+		old, ok := key2Current[*chain.ChainId]
+		if !ok {
+			updates = append(updates, &syncAction{kind: syncAdd, table: "chain", key: key, apply: func(tx *gorm.DB) error {
+				return tx.Create(&models.Chain{ChainId: chain.ChainId, BackwardBlockNumber: chain.BackwardBlockNumber}).Error
+			}})
+			continue
+		}
+		if old.BackwardBlockNumber != chain.BackwardBlockNumber {
+			updates = append(updates, &syncAction{kind: syncUpdate, table: "chain", key: key,
+				detail: fmt.Sprintf("BackwardBlockNumber: %d -> %d", old.BackwardBlockNumber, chain.BackwardBlockNumber),
+				apply: func(tx *gorm.DB) error {
+					return tx.Model(old).Update("backward_block_number", chain.BackwardBlockNumber).Error
+				}})
+		}
+	}
+	removes := make([]*syncAction, 0)
+	for _, chain := range current {
+		chain := chain
+		if !key2Expected[*chain.ChainId] {
+			removes = append(removes, &syncAction{kind: syncRemove, table: "chain", key: fmt.Sprintf("%d", *chain.ChainId),
+				apply: func(tx *gorm.DB) error { return tx.Delete(chain).Error }})
+		}
+	}
+	return updates, removes
+}
+
+func diffChainFees(current []*models.ChainFee, expected []*models.ChainFee) ([]*syncAction, []*syncAction) {
+	key2Current := make(map[uint64]*models.ChainFee)
+	for _, chainFee := range current {
+		key2Current[chainFee.ChainId] = chainFee
+	}
+	key2Expected := make(map[uint64]bool)
+	updates := make([]*syncAction, 0)
+	for _, chainFee := range expected {
+		chainFee := chainFee
+		key := fmt.Sprintf("%d", chainFee.ChainId)
+		key2Expected[chainFee.ChainId] = true
+		old, ok := key2Current[chainFee.ChainId]
+		if !ok {
+			updates = append(updates, &syncAction{kind: syncAdd, table: "chain_fee", key: key, detail: chainFee.TokenBasicName,
+				apply: func(tx *gorm.DB) error {
+					newChainFee := &models.ChainFee{
+						ChainId:        chainFee.ChainId,
+						TokenBasicName: chainFee.TokenBasicName,
+						MaxFee:         chainFee.MaxFee,
+						MinFee:         chainFee.MinFee,
+						ProxyFee:       chainFee.ProxyFee,
+					}
+					if newChainFee.MaxFee == nil {
+						newChainFee.MaxFee = models.NewBigIntFromInt(0)
+					}
+					if newChainFee.MinFee == nil {
+						newChainFee.MinFee = models.NewBigIntFromInt(0)
+					}
+					if newChainFee.ProxyFee == nil {
+						newChainFee.ProxyFee = models.NewBigIntFromInt(0)
+					}
+					return tx.Create(newChainFee).Error
+				}})
+			continue
+		}
+		if old.TokenBasicName != chainFee.TokenBasicName {
+			updates = append(updates, &syncAction{kind: syncUpdate, table: "chain_fee", key: key,
+				detail: fmt.Sprintf("TokenBasicName: %s -> %s", old.TokenBasicName, chainFee.TokenBasicName),
+				apply: func(tx *gorm.DB) error {
+					return tx.Model(old).Update("token_basic_name", chainFee.TokenBasicName).Error
+				}})
+		}
+	}
+	removes := make([]*syncAction, 0)
+	for _, chainFee := range current {
+		chainFee := chainFee
+		if !key2Expected[chainFee.ChainId] {
+			removes = append(removes, &syncAction{kind: syncRemove, table: "chain_fee", key: fmt.Sprintf("%d", chainFee.ChainId),
+				apply: func(tx *gorm.DB) error { return tx.Delete(chainFee).Error }})
+		}
+	}
+	return updates, removes
+}
+
+func diffTokenBasics(current []*models.TokenBasic, expected []*models.TokenBasic) ([]*syncAction, []*syncAction) {
+	key2Current := make(map[string]*models.TokenBasic)
+	for _, tokenBasic := range current {
+		key2Current[tokenBasic.Name] = tokenBasic
+	}
+	key2Expected := make(map[string]bool)
+	updates := make([]*syncAction, 0)
+	for _, tokenBasic := range expected {
+		tokenBasic := tokenBasic
+		key2Expected[tokenBasic.Name] = true
+		old, ok := key2Current[tokenBasic.Name]
+		if !ok {
+			updates = append(updates, &syncAction{kind: syncAdd, table: "token_basic", key: tokenBasic.Name,
+				apply: func(tx *gorm.DB) error {
+					return tx.Create(&models.TokenBasic{
+						Name:            tokenBasic.Name,
+						Precision:       tokenBasic.Precision,
+						Property:        tokenBasic.Property,
+						Standard:        tokenBasic.Standard,
+						Meta:            tokenBasic.Meta,
+						MetaFetcherType: tokenBasic.MetaFetcherType,
+					}).Error
+				}})
+			continue
+		}
+		changes := make(map[string]interface{})
+		details := make([]string, 0)
+		if old.Precision != tokenBasic.Precision {
+			changes["precision"] = tokenBasic.Precision
+			details = append(details, fmt.Sprintf("Precision: %d -> %d", old.Precision, tokenBasic.Precision))
+		}
+		if old.Property != tokenBasic.Property {
+			changes["property"] = tokenBasic.Property
+			details = append(details, fmt.Sprintf("Property: %d -> %d", old.Property, tokenBasic.Property))
+		}
+		if old.Standard != tokenBasic.Standard {
+			changes["standard"] = tokenBasic.Standard
+			details = append(details, fmt.Sprintf("Standard: %d -> %d", old.Standard, tokenBasic.Standard))
+		}
+		if old.Meta != tokenBasic.Meta {
+			changes["meta"] = tokenBasic.Meta
+			details = append(details, fmt.Sprintf("Meta: %s -> %s", old.Meta, tokenBasic.Meta))
+		}
+		if old.MetaFetcherType != tokenBasic.MetaFetcherType {
+			changes["meta_fetcher_type"] = tokenBasic.MetaFetcherType
+			details = append(details, fmt.Sprintf("MetaFetcherType: %d -> %d", old.MetaFetcherType, tokenBasic.MetaFetcherType))
+		}
+		if len(changes) > 0 {
+			updates = append(updates, &syncAction{kind: syncUpdate, table: "token_basic", key: tokenBasic.Name,
+				detail: strings.Join(details, ", "),
+				apply: func(tx *gorm.DB) error {
+					return tx.Model(old).Updates(changes).Error
+				}})
+		}
+	}
+	removes := make([]*syncAction, 0)
+	for _, tokenBasic := range current {
+		tokenBasic := tokenBasic
+		if !key2Expected[tokenBasic.Name] {
+			removes = append(removes, &syncAction{kind: syncRemove, table: "token_basic", key: tokenBasic.Name,
+				apply: func(tx *gorm.DB) error { return tx.Delete(tokenBasic).Error }})
+		}
+	}
+	return updates, removes
+}
+
+func diffPriceMarkets(current []*models.PriceMarket, expected []*models.PriceMarket) ([]*syncAction, []*syncAction) {
+	marketKey := func(priceMarket *models.PriceMarket) string {
+		return priceMarket.TokenBasicName + "/" + priceMarket.MarketName
+	}
+	key2Current := make(map[string]*models.PriceMarket)
+	for _, priceMarket := range current {
+		key2Current[marketKey(priceMarket)] = priceMarket
+	}
+	key2Expected := make(map[string]bool)
+	updates := make([]*syncAction, 0)
+	for _, priceMarket := range expected {
+		priceMarket := priceMarket
+		key := marketKey(priceMarket)
+		key2Expected[key] = true
+		old, ok := key2Current[key]
+		if !ok {
+			updates = append(updates, &syncAction{kind: syncAdd, table: "price_market", key: key, detail: priceMarket.Name,
+				apply: func(tx *gorm.DB) error {
+					return tx.Create(&models.PriceMarket{
+						TokenBasicName: priceMarket.TokenBasicName,
+						MarketName:     priceMarket.MarketName,
+						Name:           priceMarket.Name,
+					}).Error
+				}})
+			continue
+		}
+		if old.Name != priceMarket.Name {
+			updates = append(updates, &syncAction{kind: syncUpdate, table: "price_market", key: key,
+				detail: fmt.Sprintf("Name: %s -> %s", old.Name, priceMarket.Name),
+				apply: func(tx *gorm.DB) error {
+					return tx.Model(old).Update("name", priceMarket.Name).Error
+				}})
+		}
+	}
+	removes := make([]*syncAction, 0)
+	for _, priceMarket := range current {
+		priceMarket := priceMarket
+		if !key2Expected[marketKey(priceMarket)] {
+			removes = append(removes, &syncAction{kind: syncRemove, table: "price_market", key: marketKey(priceMarket),
+				apply: func(tx *gorm.DB) error { return tx.Delete(priceMarket).Error }})
+		}
+	}
+	return updates, removes
+}
+
+func diffTokens(current []*models.Token, expected []*models.Token) ([]*syncAction, []*syncAction) {
+	tokenKey := func(token *models.Token) string {
+		return fmt.Sprintf("%d:%s", token.ChainId, strings.ToLower(token.Hash))
+	}
+	key2Current := make(map[string]*models.Token)
+	for _, token := range current {
+		key2Current[tokenKey(token)] = token
+	}
+	key2Expected := make(map[string]bool)
+	updates := make([]*syncAction, 0)
+	for _, token := range expected {
+		token := token
+		key := tokenKey(token)
+		key2Expected[key] = true
+		old, ok := key2Current[key]
+		if !ok {
+			updates = append(updates, &syncAction{kind: syncAdd, table: "token", key: key, detail: token.Name,
+				apply: func(tx *gorm.DB) error {
+					return tx.Create(&models.Token{
+						Hash:           token.Hash,
+						ChainId:        token.ChainId,
+						Name:           token.Name,
+						Precision:      token.Precision,
+						TokenBasicName: token.TokenBasicName,
+						Property:       token.Property,
+						Standard:       token.Standard,
+					}).Error
+				}})
+			continue
+		}
+		changes := make(map[string]interface{})
+		details := make([]string, 0)
+		if old.Name != token.Name {
+			changes["name"] = token.Name
+			details = append(details, fmt.Sprintf("Name: %s -> %s", old.Name, token.Name))
+		}
+		if old.Precision != token.Precision {
+			changes["precision"] = token.Precision
+			details = append(details, fmt.Sprintf("Precision: %d -> %d", old.Precision, token.Precision))
+		}
+		if old.TokenBasicName != token.TokenBasicName {
+			changes["token_basic_name"] = token.TokenBasicName
+			details = append(details, fmt.Sprintf("TokenBasicName: %s -> %s", old.TokenBasicName, token.TokenBasicName))
+		}
+		if old.Property != token.Property {
+			changes["property"] = token.Property
+			details = append(details, fmt.Sprintf("Property: %d -> %d", old.Property, token.Property))
+		}
+		if old.Standard != token.Standard {
+			changes["standard"] = token.Standard
+			details = append(details, fmt.Sprintf("Standard: %d -> %d", old.Standard, token.Standard))
+		}
+		if len(changes) > 0 {
+			updates = append(updates, &syncAction{kind: syncUpdate, table: "token", key: key,
+				detail: strings.Join(details, ", "),
+				apply: func(tx *gorm.DB) error {
+					return tx.Model(old).Updates(changes).Error
+				}})
+		}
+	}
+	removes := make([]*syncAction, 0)
+	for _, token := range current {
+		token := token
+		if !key2Expected[tokenKey(token)] {
+			removes = append(removes, &syncAction{kind: syncRemove, table: "token", key: tokenKey(token),
+				apply: func(tx *gorm.DB) error { return tx.Delete(token).Error }})
+		}
+	}
+	return updates, removes
+}
+
+func diffTokenMaps(current []*models.TokenMap, expected []*models.TokenMap) ([]*syncAction, []*syncAction) {
+	tokenMapKey := func(tokenMap *models.TokenMap) string {
+		return fmt.Sprintf("%d:%s->%d:%s", tokenMap.SrcChainId, strings.ToLower(tokenMap.SrcTokenHash),
+			tokenMap.DstChainId, strings.ToLower(tokenMap.DstTokenHash))
+	}
+	key2Current := make(map[string]*models.TokenMap)
+	for _, tokenMap := range current {
+		key2Current[tokenMapKey(tokenMap)] = tokenMap
+	}
+	// later maps of the config override the ones derived from the token basics
+	key2Expected := make(map[string]*models.TokenMap)
+	keys := make([]string, 0)
+	for _, tokenMap := range expected {
+		key := tokenMapKey(tokenMap)
+		if _, ok := key2Expected[key]; !ok {
+			keys = append(keys, key)
+		}
+		key2Expected[key] = tokenMap
+	}
+	updates := make([]*syncAction, 0)
+	for _, key := range keys {
+		tokenMap := key2Expected[key]
+		old, ok := key2Current[key]
+		if !ok {
+			updates = append(updates, &syncAction{kind: syncAdd, table: "token_map", key: key,
+				apply: func(tx *gorm.DB) error {
+					return tx.Create(&models.TokenMap{
+						SrcChainId:   tokenMap.SrcChainId,
+						SrcTokenHash: tokenMap.SrcTokenHash,
+						DstChainId:   tokenMap.DstChainId,
+						DstTokenHash: tokenMap.DstTokenHash,
+						Standard:     tokenMap.Standard,
+						Property:     tokenMap.Property,
+					}).Error
+				}})
+			continue
+		}
+		changes := make(map[string]interface{})
+		details := make([]string, 0)
+		if old.Property != tokenMap.Property {
+			changes["property"] = tokenMap.Property
+			details = append(details, fmt.Sprintf("Property: %d -> %d", old.Property, tokenMap.Property))
+		}
+		if old.Standard != tokenMap.Standard {
+			changes["standard"] = tokenMap.Standard
+			details = append(details, fmt.Sprintf("Standard: %d -> %d", old.Standard, tokenMap.Standard))
+		}
+		if len(changes) > 0 {
+			updates = append(updates, &syncAction{kind: syncUpdate, table: "token_map", key: key,
+				detail: strings.Join(details, ", "),
+				apply: func(tx *gorm.DB) error {
+					return tx.Model(old).Updates(changes).Error
+				}})
+		}
+	}
+	removes := make([]*syncAction, 0)
+	for _, tokenMap := range current {
+		tokenMap := tokenMap
+		if _, ok := key2Expected[tokenMapKey(tokenMap)]; !ok {
+			removes = append(removes, &syncAction{kind: syncRemove, table: "token_map", key: tokenMapKey(tokenMap),
+				apply: func(tx *gorm.DB) error { return tx.Delete(tokenMap).Error }})
+		}
+	}
+	return updates, removes
+}
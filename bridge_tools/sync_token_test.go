@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/bridge_tools/conf"
+	"poly-bridge/models"
+)
+
+func TestDiffTokenSyncState(t *testing.T) {
+	chainId2, chainId6, chainId7 := uint64(2), uint64(6), uint64(7)
+	current := &tokenSyncState{
+		chains: []*models.Chain{
+			{ChainId: &chainId2, Height: 100, BackwardBlockNumber: 12},
+			{ChainId: &chainId7, Height: 200, BackwardBlockNumber: 21},
+		},
+		chainFees: []*models.ChainFee{
+			{ChainId: 2, TokenBasicName: "ETH", ProxyFee: models.NewBigIntFromInt(10)},
+		},
+		tokenBasics: []*models.TokenBasic{
+			{Name: "ETH", Precision: 18, Property: 1, Price: 2000},
+			{Name: "OLD", Precision: 8, Property: 1},
+		},
+		tokens: []*models.Token{
+			{ChainId: 2, Hash: "0000000000000000000000000000000000000000", Name: "ETH", Precision: 18, TokenBasicName: "ETH"},
+			{ChainId: 2, Hash: "1111111111111111111111111111111111111111", Name: "OLD", Precision: 8, TokenBasicName: "OLD"},
+		},
+	}
+	cfg := &conf.DeployConfig{
+		Chains: []*models.Chain{
+			{ChainId: &chainId2, BackwardBlockNumber: 15},
+			{ChainId: &chainId6, BackwardBlockNumber: 21},
+			{ChainId: &chainId7, BackwardBlockNumber: 21},
+		},
+		ChainFees: []*models.ChainFee{
+			{ChainId: 2, TokenBasic: &models.TokenBasic{Name: "ETH"}},
+			{ChainId: 6, TokenBasic: &models.TokenBasic{Name: "BNB"}},
+		},
+		TokenBasics: []*models.TokenBasic{
+			{
+				Name: "ETH", Precision: 18, Property: 1,
+				Tokens: []*models.Token{
+					{ChainId: 2, Hash: "0000000000000000000000000000000000000000", Name: "ETH", Precision: 18},
+					{ChainId: 6, Hash: "B9478391EEC218DEFA96F7B9A7938CF44E7A2FD5", Name: "pETH", Precision: 18},
+				},
+			},
+		},
+	}
+
+	actions := diffTokenSyncState(current, configTokenSyncState(cfg))
+	plan := make([]string, 0)
+	for _, action := range actions {
+		plan = append(plan, action.kind+" "+action.table+" "+action.key)
+	}
+	assert.Equal(t, []string{
+		"remove token 2:1111111111111111111111111111111111111111",
+		"remove token_basic OLD",
+		"update chain 2",
+		"add chain 6",
+		"add chain_fee 6",
+		"add token 6:b9478391eec218defa96f7b9a7938cf44e7a2fd5",
+		"add token_map 2:0000000000000000000000000000000000000000->6:b9478391eec218defa96f7b9a7938cf44e7a2fd5",
+		"add token_map 6:b9478391eec218defa96f7b9a7938cf44e7a2fd5->2:0000000000000000000000000000000000000000",
+	}, plan)
+
+	current.chains[0].BackwardBlockNumber = 15
+	assert.Empty(t, diffTokenSyncState(&tokenSyncState{
+		chains:      current.chains,
+		chainFees:   current.chainFees[:1],
+		tokenBasics: current.tokenBasics[:1],
+		tokens:      current.tokens[:1],
+	}, &tokenSyncState{
+		chains:      []*models.Chain{current.chains[0], current.chains[1]},
+		chainFees:   []*models.ChainFee{{ChainId: 2, TokenBasicName: "ETH"}},
+		tokenBasics: []*models.TokenBasic{{Name: "ETH", Precision: 18, Property: 1}},
+		tokens:      []*models.Token{{ChainId: 2, Hash: "0000000000000000000000000000000000000000", Name: "ETH", Precision: 18, TokenBasicName: "ETH"}},
+	}))
+}
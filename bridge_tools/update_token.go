@@ -38,14 +38,20 @@ func startUpdateToken(cfg *conf.DeployConfig) {
 	if err != nil {
 		panic(err)
 	}
-	err = db.AutoMigrate(&models.Chain{}, &models.WrapperTransaction{}, &models.ChainFee{}, &models.TokenBasic{}, &models.Token{}, &models.PriceMarket{},
-		&models.TokenMap{}, &models.SrcTransaction{}, &models.SrcTransfer{}, &models.PolyTransaction{}, &models.DstTransaction{}, &models.DstTransfer{})
+	err = db.AutoMigrate(&models.Chain{}, &models.WrapperTransaction{}, &models.ChainFee{}, &models.AssetFee{}, &models.TokenBasic{}, &models.Token{}, &models.PriceMarket{},
+		&models.TokenMap{}, &models.SrcTransaction{}, &models.SrcTransfer{}, &models.PolyTransaction{}, &models.DstTransaction{}, &models.DstTransfer{},
+		&models.TokenPriceHistory{}, &models.FeeReconciliation{}, &models.FeePolicy{}, &models.AuditLog{}, &models.TokenMapVerification{}, &models.TokenLiquidity{}, &models.ApiKey{}, &models.NFTProfile{}, &models.NFTProfileAttribute{}, &models.NFTOwnership{}, &models.NFTIndexCursor{})
+	if err != nil {
+		panic(err)
+	}
+	err = migrateNFTTables(db)
 	if err != nil {
 		panic(err)
 	}
 	//
 	db.Where("1 = 1").Delete(&models.Chain{})
 	db.Where("1 = 1").Delete(&models.ChainFee{})
+	db.Where("1 = 1").Delete(&models.AssetFee{})
 	db.Where("1 = 1").Delete(&models.PriceMarket{})
 	db.Where("1 = 1").Delete(&models.TokenMap{})
 	db.Where("1 = 1").Delete(&models.Token{})
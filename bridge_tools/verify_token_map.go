@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/tokenmapverify"
+)
+
+func startVerifyTokenMap(dbCfg *conf.DBConfig, chainConfigFile string) {
+	chainConfig := conf.NewConfig(chainConfigFile)
+	if chainConfig == nil {
+		panic(fmt.Errorf("read chain config %s failed", chainConfigFile))
+	}
+	Logger := logger.Default
+	if dbCfg.Debug == true {
+		Logger = Logger.LogMode(logger.Info)
+	}
+	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
+		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	if err != nil {
+		panic(err)
+	}
+	err = db.AutoMigrate(&models.TokenMapVerification{})
+	if err != nil {
+		panic(err)
+	}
+	verifier := tokenmapverify.NewVerifier(chainConfig.ChainListenConfig, tokenmapverify.NewEthereumSdks(chainConfig.ChainListenConfig))
+	verifications, err := verifier.VerifyAndSave(db)
+	if err != nil {
+		panic(err)
+	}
+	mismatch, failed, unverified := 0, 0, 0
+	for _, verification := range verifications {
+		switch verification.Status {
+		case models.TokenMapVerifyMismatch:
+			mismatch++
+			fmt.Printf("mismatch: %d:%s->%d:%s, lock proxy bound: %s\n", verification.SrcChainId, verification.SrcTokenHash,
+				verification.DstChainId, verification.DstTokenHash, verification.BoundHash)
+		case models.TokenMapVerifyFailed:
+			failed++
+			fmt.Printf("failed: %d:%s->%d:%s, err: %s\n", verification.SrcChainId, verification.SrcTokenHash,
+				verification.DstChainId, verification.DstTokenHash, verification.Error)
+		case models.TokenMapVerifyUnverified:
+			unverified++
+		}
+	}
+	fmt.Printf("verified %d token maps, %d mismatch, %d failed, %d unverified\n", len(verifications)-unverified, mismatch, failed, unverified)
+}
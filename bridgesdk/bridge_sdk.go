@@ -18,108 +18,164 @@
 package bridgesdk
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
-	"strings"
+	"poly-bridge/models"
 )
 
-var (
-	STATE_NOTPAY   = -1
-	STATE_NOTCHECK = 0
-	STATE_HASPAY   = 1
-)
+// BridgeSdk is the client of the /v1 api of a bridge node, whose url is such as http://host/v1/.
+type BridgeSdk struct {
+	*client
+}
 
-type PolySwapResp struct {
-	Version string
-	URL     string
+func NewBridgeSdk(url string) *BridgeSdk {
+	return &BridgeSdk{
+		client: newClient(url),
+	}
 }
 
-type CheckFeeReq struct {
-	Hash    string `json:"Hash"`
-	ChainId uint64 `json:"ChainId"`
+// SetApiKey sets the api key of the X-Api-Key header, which the admin routes require and the rate limit counts by.
+func (sdk *BridgeSdk) SetApiKey(apiKey string) {
+	sdk.apiKey = apiKey
 }
 
-type CheckFeesReq struct {
-	Checks []*CheckFeeReq `json:"Checks"`
+func (sdk *BridgeSdk) Info() (bool, error) {
+	return sdk.info()
 }
 
-type CheckFeeRsp struct {
-	ChainId     uint64 `json:"ChainId"`
-	Hash        string `json:"Hash"`
-	PayState    int    `json:"PayState"`
-	Amount      string `json:"Amount"`
-	MinProxyFee string `json:"MinProxyFee"`
-	Error       string `json:"Error"`
+func (sdk *BridgeSdk) Token(req *models.TokenReq) (*models.TokenRsp, error) {
+	rsp := new(models.TokenRsp)
+	if err := sdk.request(http.MethodPost, "token/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
 }
 
-type CheckFeesRsp struct {
-	CheckFees []*CheckFeeRsp `json:"CheckFees"`
+func (sdk *BridgeSdk) Tokens(req *models.TokensReq) (*models.TokensRsp, error) {
+	rsp := new(models.TokensRsp)
+	if err := sdk.request(http.MethodPost, "tokens/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
 }
 
-type BridgeSdk struct {
-	url string
+func (sdk *BridgeSdk) TokenBasics(req *models.TokenBasicReq) (*models.TokenBasicsRsp, error) {
+	rsp := new(models.TokenBasicsRsp)
+	if err := sdk.request(http.MethodPost, "tokenbasics/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
 }
 
-func NewBridgeSdk(url string) *BridgeSdk {
-	return &BridgeSdk{
-		url: url,
+func (sdk *BridgeSdk) TokenMap(req *models.TokenMapReq) (*models.TokenMapsRsp, error) {
+	rsp := new(models.TokenMapsRsp)
+	if err := sdk.request(http.MethodPost, "tokenmap/", nil, req, rsp); err != nil {
+		return nil, err
 	}
+	return rsp, nil
 }
 
-func (sdk *BridgeSdk) CheckFee(checks []*CheckFeeReq) ([]*CheckFeeRsp, error) {
-	checkFeesReq := &CheckFeesReq{Checks: checks}
-	requestJson, err := json.Marshal(checkFeesReq)
-	if err != nil {
+func (sdk *BridgeSdk) TokenMapReverse(req *models.TokenMapReq) (*models.TokenMapsRsp, error) {
+	rsp := new(models.TokenMapsRsp)
+	if err := sdk.request(http.MethodPost, "tokenmapreverse/", nil, req, rsp); err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", sdk.url+"checkfee", strings.NewReader(string(requestJson)))
-	if err != nil {
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) GetFee(req *models.GetFeeReq) (*models.GetFeeRsp, error) {
+	rsp := new(models.GetFeeRsp)
+	if err := sdk.request(http.MethodPost, "getfee/", nil, req, rsp); err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accepts", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	return rsp, nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
+func (sdk *BridgeSdk) GetFees(req *models.GetFeesReq) (*models.GetFeesRsp, error) {
+	rsp := new(models.GetFeesRsp)
+	if err := sdk.request(http.MethodPost, "getfees/", nil, req, rsp); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("response status code: %d", resp.StatusCode)
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) CheckFee(checks []*models.CheckFeeReq) ([]*models.CheckFeeRsp, error) {
+	rsp := new(models.CheckFeesRsp)
+	if err := sdk.request(http.MethodPost, "checkfee/", nil, &models.CheckFeesReq{Checks: checks}, rsp); err != nil {
+		return nil, err
 	}
-	respBody, _ := ioutil.ReadAll(resp.Body)
-	checkFeesRsp := new(CheckFeesRsp)
-	err = json.Unmarshal(respBody, checkFeesRsp)
-	if err != nil {
+	return rsp.CheckFees, nil
+}
+
+// CheckSwapFee checks the fees of the swaps of O3, whose hashes are the transactions on O3.
+func (sdk *BridgeSdk) CheckSwapFee(checks []*models.CheckFeeReq) ([]*models.CheckFeeRsp, error) {
+	rsp := new(models.CheckFeesRsp)
+	if err := sdk.request(http.MethodPost, "checkswapfee/", nil, &models.CheckFeesReq{Checks: checks}, rsp); err != nil {
 		return nil, err
 	}
-	return checkFeesRsp.CheckFees, nil
+	return rsp.CheckFees, nil
 }
 
-func (sdk *BridgeSdk) Info() (bool, error) {
-	req, err := http.NewRequest("GET", sdk.url, nil)
-	if err != nil {
-		return false, err
+func (sdk *BridgeSdk) PreCheck(req *models.PreCheckReq) (*models.PreCheckRsp, error) {
+	rsp := new(models.PreCheckRsp)
+	if err := sdk.request(http.MethodPost, "precheck/", nil, req, rsp); err != nil {
+		return nil, err
 	}
-	req.Header.Set("Accepts", "application/json")
+	return rsp, nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, err
+func (sdk *BridgeSdk) Transactions(req *models.WrapperTransactionsReq) (*models.WrapperTransactionsRsp, error) {
+	rsp := new(models.WrapperTransactionsRsp)
+	if err := sdk.request(http.MethodPost, "transactions/", nil, req, rsp); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return false, fmt.Errorf("response status code: %d", resp.StatusCode)
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) TransactionsOfAddress(req *models.TransactionsOfAddressReq) (*models.TransactionsOfAddressRsp, error) {
+	rsp := new(models.TransactionsOfAddressRsp)
+	if err := sdk.request(http.MethodPost, "transactionsofaddress/", nil, req, rsp); err != nil {
+		return nil, err
 	}
-	respBody, _ := ioutil.ReadAll(resp.Body)
-	polySwapResp := new(PolySwapResp)
-	err = json.Unmarshal(respBody, polySwapResp)
-	if err != nil {
-		return false, err
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) TransactionOfHash(req *models.TransactionOfHashReq) (*models.TransactionRsp, error) {
+	rsp := new(models.TransactionRsp)
+	if err := sdk.request(http.MethodPost, "transactionofhash/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// TransactionOfCurve gets the transaction of the O3 swap whose destination transaction hash is req.Hash.
+func (sdk *BridgeSdk) TransactionOfCurve(req *models.TransactionOfHashReq) (*models.TransactionRsp, error) {
+	rsp := new(models.TransactionRsp)
+	if err := sdk.request(http.MethodPost, "transactionofcurve/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) TransactionsOfState(req *models.TransactionsOfStateReq) (*models.WrapperTransactionsRsp, error) {
+	rsp := new(models.WrapperTransactionsRsp)
+	if err := sdk.request(http.MethodPost, "transactionsofstate/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) Liquidity(req *models.LiquidityReq) (*models.LiquidityRsp, error) {
+	rsp := new(models.LiquidityRsp)
+	if err := sdk.request(http.MethodPost, "liquidity/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) LiquidityHistory(req *models.LiquidityHistoryReq) (*models.LiquidityHistoryRsp, error) {
+	rsp := new(models.LiquidityHistoryRsp)
+	if err := sdk.request(http.MethodPost, "liquidityhistory/", nil, req, rsp); err != nil {
+		return nil, err
 	}
-	return true, nil
+	return rsp, nil
 }
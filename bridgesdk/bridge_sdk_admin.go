@@ -0,0 +1,260 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bridgesdk
+
+import (
+	"net/http"
+	"poly-bridge/models"
+)
+
+// The admin routes require the api key of an admin, see SetApiKey.
+
+// AdminTokenBasics gets the token basics with their tokens and price markets.
+func (sdk *BridgeSdk) AdminTokenBasics() ([]*models.TokenBasic, error) {
+	rsp := make([]*models.TokenBasic, 0)
+	if err := sdk.request(http.MethodGet, "admin/tokenbasics/", nil, nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) AddTokenBasic(req *models.TokenBasic) (*models.TokenBasic, error) {
+	rsp := new(models.TokenBasic)
+	if err := sdk.request(http.MethodPost, "admin/tokenbasic/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) UpdateTokenBasic(req *models.TokenBasic) (*models.TokenBasic, error) {
+	rsp := new(models.TokenBasic)
+	if err := sdk.request(http.MethodPut, "admin/tokenbasic/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) RemoveTokenBasic(req *models.AdminTokenBasicReq) (*models.AdminTokenBasicReq, error) {
+	rsp := new(models.AdminTokenBasicReq)
+	if err := sdk.request(http.MethodDelete, "admin/tokenbasic/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) AddToken(req *models.Token) (*models.Token, error) {
+	rsp := new(models.Token)
+	if err := sdk.request(http.MethodPost, "admin/token/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) UpdateToken(req *models.Token) (*models.Token, error) {
+	rsp := new(models.Token)
+	if err := sdk.request(http.MethodPut, "admin/token/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) RemoveToken(req *models.AdminTokenReq) (*models.AdminTokenReq, error) {
+	rsp := new(models.AdminTokenReq)
+	if err := sdk.request(http.MethodDelete, "admin/token/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) TokenMaps() ([]*models.TokenMap, error) {
+	rsp := make([]*models.TokenMap, 0)
+	if err := sdk.request(http.MethodGet, "admin/tokenmaps/", nil, nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) AddTokenMaps(req *models.AdminTokenMapsReq) ([]*models.TokenMap, error) {
+	rsp := make([]*models.TokenMap, 0)
+	if err := sdk.request(http.MethodPost, "admin/tokenmaps/", nil, req, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) UpdateTokenMap(req *models.TokenMap) (*models.TokenMap, error) {
+	rsp := new(models.TokenMap)
+	if err := sdk.request(http.MethodPut, "admin/tokenmap/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) RemoveTokenMap(req *models.AdminTokenMapReq) (*models.AdminTokenMapReq, error) {
+	rsp := new(models.AdminTokenMapReq)
+	if err := sdk.request(http.MethodDelete, "admin/tokenmap/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) AddPriceMarket(req *models.PriceMarket) (*models.PriceMarket, error) {
+	rsp := new(models.PriceMarket)
+	if err := sdk.request(http.MethodPost, "admin/pricemarket/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) UpdatePriceMarket(req *models.PriceMarket) (*models.PriceMarket, error) {
+	rsp := new(models.PriceMarket)
+	if err := sdk.request(http.MethodPut, "admin/pricemarket/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) RemovePriceMarket(req *models.AdminPriceMarketReq) (*models.AdminPriceMarketReq, error) {
+	rsp := new(models.AdminPriceMarketReq)
+	if err := sdk.request(http.MethodDelete, "admin/pricemarket/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) ChainFees() ([]*models.ChainFee, error) {
+	rsp := make([]*models.ChainFee, 0)
+	if err := sdk.request(http.MethodGet, "admin/chainfees/", nil, nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) AddChainFee(req *models.ChainFee) (*models.ChainFee, error) {
+	rsp := new(models.ChainFee)
+	if err := sdk.request(http.MethodPost, "admin/chainfee/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) UpdateChainFee(req *models.ChainFee) (*models.ChainFee, error) {
+	rsp := new(models.ChainFee)
+	if err := sdk.request(http.MethodPut, "admin/chainfee/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) RemoveChainFee(req *models.AdminChainFeeReq) (*models.AdminChainFeeReq, error) {
+	rsp := new(models.AdminChainFeeReq)
+	if err := sdk.request(http.MethodDelete, "admin/chainfee/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) FeePolicies() ([]*models.FeePolicy, error) {
+	rsp := make([]*models.FeePolicy, 0)
+	if err := sdk.request(http.MethodGet, "admin/feepolicies/", nil, nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// AddFeePolicy saves the policy as the next version of its name.
+func (sdk *BridgeSdk) AddFeePolicy(req *models.FeePolicy) (*models.FeePolicy, error) {
+	rsp := new(models.FeePolicy)
+	if err := sdk.request(http.MethodPost, "admin/feepolicy/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) SetProperty(req *models.AdminPropertyReq) (*models.AdminPropertyReq, error) {
+	rsp := new(models.AdminPropertyReq)
+	if err := sdk.request(http.MethodPut, "admin/property/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) AuditLogs(req *models.AuditLogsReq) (*models.AuditLogsRsp, error) {
+	rsp := new(models.AuditLogsRsp)
+	if err := sdk.request(http.MethodPost, "admin/auditlogs/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) FeeReconciliation(req *models.FeeReconciliationReq) (*models.FeeReconciliationRsp, error) {
+	rsp := new(models.FeeReconciliationRsp)
+	if err := sdk.request(http.MethodPost, "admin/feereconciliation/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// VerifyTokenMaps checks the enabled token maps against the lock proxies and returns the mismatches.
+func (sdk *BridgeSdk) VerifyTokenMaps() (*models.TokenMapVerificationsRsp, error) {
+	rsp := new(models.TokenMapVerificationsRsp)
+	if err := sdk.request(http.MethodPost, "admin/verifytokenmaps/", nil, nil, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) TokenMapVerifications(req *models.TokenMapVerificationsReq) (*models.TokenMapVerificationsRsp, error) {
+	rsp := new(models.TokenMapVerificationsRsp)
+	if err := sdk.request(http.MethodPost, "admin/tokenmapverifications/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) ApiKeys() ([]*models.ApiKey, error) {
+	rsp := make([]*models.ApiKey, 0)
+	if err := sdk.request(http.MethodGet, "admin/apikeys/", nil, nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) AddApiKey(req *models.ApiKey) (*models.ApiKey, error) {
+	rsp := new(models.ApiKey)
+	if err := sdk.request(http.MethodPost, "admin/apikey/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) UpdateApiKey(req *models.AdminUpdateApiKeyReq) (*models.ApiKey, error) {
+	rsp := new(models.ApiKey)
+	if err := sdk.request(http.MethodPut, "admin/apikey/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *BridgeSdk) RemoveApiKey(req *models.AdminApiKeyReq) (*models.AdminApiKeyReq, error) {
+	rsp := new(models.AdminApiKeyReq)
+	if err := sdk.request(http.MethodDelete, "admin/apikey/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
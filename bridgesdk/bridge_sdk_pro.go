@@ -20,6 +20,7 @@ package bridgesdk
 import (
 	"fmt"
 	"github.com/astaxie/beego/logs"
+	"poly-bridge/models"
 	"runtime/debug"
 	"sync"
 	"time"
@@ -112,20 +113,410 @@ func (pro *BridgeSdkPro) GetLatest() *BridgeInfo {
 	return nil
 }
 
-func (pro *BridgeSdkPro) CheckFee(checks []*CheckFeeReq) ([]*CheckFeeRsp, error) {
+// SetApiKey sets the api key of the X-Api-Key header on all the nodes.
+func (pro *BridgeSdkPro) SetApiKey(apiKey string) {
+	pro.mutex.Lock()
+	defer pro.mutex.Unlock()
+	for _, info := range pro.infos {
+		info.sdk.SetApiKey(apiKey)
+	}
+}
+
+// call calls the online nodes in turn until one of them responds, a node which fails is offline until the next
+// selection. The requests refused by a node are not retried on the other nodes.
+func (pro *BridgeSdkPro) call(name string, fn func(sdk *BridgeSdk) error) error {
+	return pro.failover(name, false, fn)
+}
+
+// write calls the admin writes, which are retried on the other nodes only if they did not reach the node.
+func (pro *BridgeSdkPro) write(name string, fn func(sdk *BridgeSdk) error) error {
+	return pro.failover(name, true, fn)
+}
+
+func (pro *BridgeSdkPro) failover(name string, write bool, fn func(sdk *BridgeSdk) error) error {
 	info := pro.GetLatest()
 	if info == nil {
-		return nil, fmt.Errorf("all node is not working")
+		return fmt.Errorf("all node is not working")
 	}
 	for info != nil {
-		rsp, err := info.sdk.CheckFee(checks)
-		if err != nil {
-			logs.Error("check fee err: %v, url: %s", err, info.sdk.url)
-			info.online = false
-			info = pro.GetLatest()
-		} else {
-			return rsp, nil
+		err := fn(info.sdk)
+		if err == nil {
+			return nil
 		}
+		if !retryable(err, write) {
+			return err
+		}
+		logs.Error("%s err: %v, url: %s", name, err, info.sdk.url)
+		info.online = false
+		info = pro.GetLatest()
 	}
-	return nil, fmt.Errorf("all node is not working")
+	return fmt.Errorf("all node is not working")
+}
+
+func (pro *BridgeSdkPro) Token(req *models.TokenReq) (rsp *models.TokenRsp, err error) {
+	err = pro.call("token", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.Token(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) Tokens(req *models.TokensReq) (rsp *models.TokensRsp, err error) {
+	err = pro.call("tokens", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.Tokens(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) TokenBasics(req *models.TokenBasicReq) (rsp *models.TokenBasicsRsp, err error) {
+	err = pro.call("token basics", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.TokenBasics(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) TokenMap(req *models.TokenMapReq) (rsp *models.TokenMapsRsp, err error) {
+	err = pro.call("token map", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.TokenMap(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) TokenMapReverse(req *models.TokenMapReq) (rsp *models.TokenMapsRsp, err error) {
+	err = pro.call("token map reverse", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.TokenMapReverse(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) GetFee(req *models.GetFeeReq) (rsp *models.GetFeeRsp, err error) {
+	err = pro.call("get fee", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.GetFee(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) GetFees(req *models.GetFeesReq) (rsp *models.GetFeesRsp, err error) {
+	err = pro.call("get fees", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.GetFees(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) CheckFee(checks []*models.CheckFeeReq) (rsp []*models.CheckFeeRsp, err error) {
+	err = pro.call("check fee", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.CheckFee(checks)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) CheckSwapFee(checks []*models.CheckFeeReq) (rsp []*models.CheckFeeRsp, err error) {
+	err = pro.call("check swap fee", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.CheckSwapFee(checks)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) PreCheck(req *models.PreCheckReq) (rsp *models.PreCheckRsp, err error) {
+	err = pro.call("pre check", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.PreCheck(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) Transactions(req *models.WrapperTransactionsReq) (rsp *models.WrapperTransactionsRsp, err error) {
+	err = pro.call("transactions", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.Transactions(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) TransactionsOfAddress(req *models.TransactionsOfAddressReq) (rsp *models.TransactionsOfAddressRsp, err error) {
+	err = pro.call("transactions of address", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.TransactionsOfAddress(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) TransactionOfHash(req *models.TransactionOfHashReq) (rsp *models.TransactionRsp, err error) {
+	err = pro.call("transaction of hash", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.TransactionOfHash(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) TransactionOfCurve(req *models.TransactionOfHashReq) (rsp *models.TransactionRsp, err error) {
+	err = pro.call("transaction of curve", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.TransactionOfCurve(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) TransactionsOfState(req *models.TransactionsOfStateReq) (rsp *models.WrapperTransactionsRsp, err error) {
+	err = pro.call("transactions of state", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.TransactionsOfState(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) Liquidity(req *models.LiquidityReq) (rsp *models.LiquidityRsp, err error) {
+	err = pro.call("liquidity", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.Liquidity(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) LiquidityHistory(req *models.LiquidityHistoryReq) (rsp *models.LiquidityHistoryRsp, err error) {
+	err = pro.call("liquidity history", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.LiquidityHistory(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) AdminTokenBasics() (rsp []*models.TokenBasic, err error) {
+	err = pro.call("admin token basics", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.AdminTokenBasics()
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) AddTokenBasic(req *models.TokenBasic) (rsp *models.TokenBasic, err error) {
+	err = pro.write("add token basic", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.AddTokenBasic(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) UpdateTokenBasic(req *models.TokenBasic) (rsp *models.TokenBasic, err error) {
+	err = pro.write("update token basic", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.UpdateTokenBasic(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) RemoveTokenBasic(req *models.AdminTokenBasicReq) (rsp *models.AdminTokenBasicReq, err error) {
+	err = pro.write("remove token basic", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.RemoveTokenBasic(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) AddToken(req *models.Token) (rsp *models.Token, err error) {
+	err = pro.write("add token", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.AddToken(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) UpdateToken(req *models.Token) (rsp *models.Token, err error) {
+	err = pro.write("update token", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.UpdateToken(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) RemoveToken(req *models.AdminTokenReq) (rsp *models.AdminTokenReq, err error) {
+	err = pro.write("remove token", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.RemoveToken(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) TokenMaps() (rsp []*models.TokenMap, err error) {
+	err = pro.call("token maps", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.TokenMaps()
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) AddTokenMaps(req *models.AdminTokenMapsReq) (rsp []*models.TokenMap, err error) {
+	err = pro.write("add token maps", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.AddTokenMaps(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) UpdateTokenMap(req *models.TokenMap) (rsp *models.TokenMap, err error) {
+	err = pro.write("update token map", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.UpdateTokenMap(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) RemoveTokenMap(req *models.AdminTokenMapReq) (rsp *models.AdminTokenMapReq, err error) {
+	err = pro.write("remove token map", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.RemoveTokenMap(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) AddPriceMarket(req *models.PriceMarket) (rsp *models.PriceMarket, err error) {
+	err = pro.write("add price market", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.AddPriceMarket(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) UpdatePriceMarket(req *models.PriceMarket) (rsp *models.PriceMarket, err error) {
+	err = pro.write("update price market", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.UpdatePriceMarket(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) RemovePriceMarket(req *models.AdminPriceMarketReq) (rsp *models.AdminPriceMarketReq, err error) {
+	err = pro.write("remove price market", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.RemovePriceMarket(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) ChainFees() (rsp []*models.ChainFee, err error) {
+	err = pro.call("chain fees", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.ChainFees()
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) AddChainFee(req *models.ChainFee) (rsp *models.ChainFee, err error) {
+	err = pro.write("add chain fee", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.AddChainFee(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) UpdateChainFee(req *models.ChainFee) (rsp *models.ChainFee, err error) {
+	err = pro.write("update chain fee", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.UpdateChainFee(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) RemoveChainFee(req *models.AdminChainFeeReq) (rsp *models.AdminChainFeeReq, err error) {
+	err = pro.write("remove chain fee", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.RemoveChainFee(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) FeePolicies() (rsp []*models.FeePolicy, err error) {
+	err = pro.call("fee policies", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.FeePolicies()
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) AddFeePolicy(req *models.FeePolicy) (rsp *models.FeePolicy, err error) {
+	err = pro.write("add fee policy", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.AddFeePolicy(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) SetProperty(req *models.AdminPropertyReq) (rsp *models.AdminPropertyReq, err error) {
+	err = pro.write("set property", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.SetProperty(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) AuditLogs(req *models.AuditLogsReq) (rsp *models.AuditLogsRsp, err error) {
+	err = pro.call("audit logs", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.AuditLogs(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) FeeReconciliation(req *models.FeeReconciliationReq) (rsp *models.FeeReconciliationRsp, err error) {
+	err = pro.call("fee reconciliation", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.FeeReconciliation(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) VerifyTokenMaps() (rsp *models.TokenMapVerificationsRsp, err error) {
+	err = pro.write("verify token maps", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.VerifyTokenMaps()
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) TokenMapVerifications(req *models.TokenMapVerificationsReq) (rsp *models.TokenMapVerificationsRsp, err error) {
+	err = pro.call("token map verifications", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.TokenMapVerifications(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) ApiKeys() (rsp []*models.ApiKey, err error) {
+	err = pro.call("api keys", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.ApiKeys()
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) AddApiKey(req *models.ApiKey) (rsp *models.ApiKey, err error) {
+	err = pro.write("add api key", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.AddApiKey(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) UpdateApiKey(req *models.AdminUpdateApiKeyReq) (rsp *models.ApiKey, err error) {
+	err = pro.write("update api key", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.UpdateApiKey(req)
+		return err
+	})
+	return
+}
+
+func (pro *BridgeSdkPro) RemoveApiKey(req *models.AdminApiKeyReq) (rsp *models.AdminApiKeyReq, err error) {
+	err = pro.write("remove api key", func(sdk *BridgeSdk) error {
+		rsp, err = sdk.RemoveApiKey(req)
+		return err
+	})
+	return
 }
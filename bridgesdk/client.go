@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bridgesdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"poly-bridge/models"
+	"time"
+)
+
+var (
+	STATE_NOTPAY   = -1
+	STATE_NOTCHECK = 0
+	STATE_HASPAY   = 1
+)
+
+type PolySwapResp struct {
+	Version string
+	URL     string
+}
+
+// ResponseError is the error response of a request which the node refused. The request is not retried on the
+// other nodes unless the node failed with a server error.
+type ResponseError struct {
+	StatusCode int
+	Message    string
+}
+
+func (err *ResponseError) Error() string {
+	return fmt.Sprintf("response status code: %d, %s", err.StatusCode, err.Message)
+}
+
+// client requests the json api of a node, url is the prefix of the routes such as http://host/v1/.
+type client struct {
+	url    string
+	apiKey string
+	http   *http.Client
+}
+
+func newClient(url string) *client {
+	return &client{
+		url:  url,
+		http: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *client) request(method string, path string, query url.Values, req interface{}, rsp interface{}) error {
+	data, _, err := c.requestRaw(method, path, query, req)
+	if err != nil {
+		return err
+	}
+	if rsp == nil {
+		return nil
+	}
+	return json.Unmarshal(data, rsp)
+}
+
+func (c *client) requestRaw(method string, path string, query url.Values, req interface{}) ([]byte, string, error) {
+	var body *bytes.Reader
+	if req != nil {
+		requestJson, err := json.Marshal(req)
+		if err != nil {
+			return nil, "", err
+		}
+		body = bytes.NewReader(requestJson)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+	u := c.url + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	httpReq, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, "", err
+	}
+	httpReq.Header.Set("Accepts", "application/json")
+	if req != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("X-Api-Key", c.apiKey)
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", &ResponseError{StatusCode: resp.StatusCode, Message: errorMessage(respBody)}
+	}
+	return respBody, resp.Header.Get("Content-Type"), nil
+}
+
+// errorMessage reads the message of models.ErrorRsp, some routes respond a json string instead.
+func errorMessage(body []byte) string {
+	errorRsp := new(models.ErrorRsp)
+	if err := json.Unmarshal(body, errorRsp); err == nil && errorRsp.Message != "" {
+		return errorRsp.Message
+	}
+	var message string
+	if err := json.Unmarshal(body, &message); err == nil {
+		return message
+	}
+	return string(body)
+}
+
+func (c *client) info() (bool, error) {
+	if err := c.request(http.MethodGet, "", nil, nil, new(PolySwapResp)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// retryable reports whether the request may succeed on another node, the requests refused by the node fail
+// on every node. A write is retried only if it did not reach the node, as a write which timed out or failed on
+// the node may have been applied.
+func retryable(err error, write bool) bool {
+	if write {
+		return !sent(err)
+	}
+	if rspErr, ok := err.(*ResponseError); ok {
+		return rspErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// sent reports whether the request may have reached the node, only a request which failed to connect did not.
+func sent(err error) bool {
+	var opErr *net.OpError
+	return !errors.As(err, &opErr) || opErr.Op != "dial"
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bridgesdk
+
+import (
+	"net/http"
+	"net/url"
+	"poly-bridge/models"
+	nft "poly-bridge/nft_http/controllers"
+	"strconv"
+)
+
+// NFTSdk is the client of the /nft/v1 api of an NFT node, whose url is such as http://host/nft/v1/.
+type NFTSdk struct {
+	*client
+}
+
+func NewNFTSdk(url string) *NFTSdk {
+	return &NFTSdk{
+		client: newClient(url),
+	}
+}
+
+// SetApiKey sets the api key of the X-Api-Key header, which ReloadAssets requires.
+func (sdk *NFTSdk) SetApiKey(apiKey string) {
+	sdk.apiKey = apiKey
+}
+
+func (sdk *NFTSdk) Info() (bool, error) {
+	return sdk.info()
+}
+
+// Home gets the home page of the chain, which shows the tokens of the NFT assets.
+func (sdk *NFTSdk) Home(req *nft.HomeReq) (*nft.HomeRsp, error) {
+	rsp := new(nft.HomeRsp)
+	if err := sdk.request(http.MethodPost, "assetshow/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *NFTSdk) Asset(req *nft.AssetReq) (*nft.AssetMap, error) {
+	rsp := new(nft.AssetMap)
+	if err := sdk.request(http.MethodPost, "asset/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *NFTSdk) Assets(req *nft.AssetsReq) (*nft.AssetsRsp, error) {
+	rsp := new(nft.AssetsRsp)
+	if err := sdk.request(http.MethodPost, "assets/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// ReloadAssets reloads the NFT assets and fee tokens of the node, it requires the api key of an admin.
+func (sdk *NFTSdk) ReloadAssets() (*nft.AssetsReloadRsp, error) {
+	rsp := new(nft.AssetsReloadRsp)
+	if err := sdk.request(http.MethodPost, "assets/reload/", nil, nil, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *NFTSdk) Items(req *nft.ItemsOfAddressReq) (*nft.ItemsOfAddressRsp, error) {
+	rsp := new(nft.ItemsOfAddressRsp)
+	if err := sdk.request(http.MethodPost, "items/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// CheckFee checks the fees paid by the NFT wrapper transactions.
+func (sdk *NFTSdk) CheckFee(checks []*models.CheckFeeReq) ([]*models.CheckFeeRsp, error) {
+	rsp := new(models.CheckFeesRsp)
+	if err := sdk.request(http.MethodPost, "checkfee/", nil, &models.CheckFeesReq{Checks: checks}, rsp); err != nil {
+		return nil, err
+	}
+	return rsp.CheckFees, nil
+}
+
+// Media gets the cached image of the token and its content type, or the thumbnail of the image.
+func (sdk *NFTSdk) Media(asset string, tokenId string, thumbnail bool) ([]byte, string, error) {
+	query := url.Values{}
+	query.Set("asset", asset)
+	query.Set("tokenid", tokenId)
+	query.Set("thumb", strconv.FormatBool(thumbnail))
+	return sdk.requestRaw(http.MethodGet, "media/", query, nil)
+}
+
+func (sdk *NFTSdk) GetFee(req *nft.GetFeeReq) (*models.GetFeeRsp, error) {
+	rsp := new(models.GetFeeRsp)
+	if err := sdk.request(http.MethodPost, "getfee/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *NFTSdk) Collections() (*nft.CollectionsRsp, error) {
+	rsp := new(nft.CollectionsRsp)
+	if err := sdk.request(http.MethodGet, "collections/", nil, nil, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// CollectionActivity gets the statistics and the transactions of the collection whose name is asset.
+func (sdk *NFTSdk) CollectionActivity(asset string, pageSize int, pageNo int) (*nft.CollectionActivityRsp, error) {
+	query := url.Values{}
+	query.Set("pagesize", strconv.Itoa(pageSize))
+	query.Set("pageno", strconv.Itoa(pageNo))
+	rsp := new(nft.CollectionActivityRsp)
+	if err := sdk.request(http.MethodGet, "collection/"+url.PathEscape(asset)+"/activity/", query, nil, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *NFTSdk) ExplorerTransactions(req *nft.TransactionBriefsReq) (*nft.TransactionBriefsRsp, error) {
+	rsp := new(nft.TransactionBriefsRsp)
+	if err := sdk.request(http.MethodPost, "exp_transactions/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *NFTSdk) ExplorerTransactionsOfAddress(req *nft.TransactionBriefsOfAddressReq) (*nft.TransactionBriefsRsp, error) {
+	rsp := new(nft.TransactionBriefsRsp)
+	if err := sdk.request(http.MethodPost, "exp_transactionsofaddress/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// ExplorerTransactionDetail gets the detail of the transaction, it is nil if the transaction does not exist.
+func (sdk *NFTSdk) ExplorerTransactionDetail(req *nft.TransactionDetailReq) (*nft.TransactionDetailRsp, error) {
+	var rsp *nft.TransactionDetailRsp
+	if err := sdk.request(http.MethodPost, "exp_transactionofhash/", nil, req, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *NFTSdk) TransactionsOfAddress(req *models.TransactionsOfAddressReq) (*nft.TransactionsOfAddressRsp, error) {
+	rsp := new(nft.TransactionsOfAddressRsp)
+	if err := sdk.request(http.MethodPost, "transactionsofaddress/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (sdk *NFTSdk) TransactionOfHash(req *models.TransactionOfHashReq) (*nft.TransactionRsp, error) {
+	rsp := new(nft.TransactionRsp)
+	if err := sdk.request(http.MethodPost, "transactionofhash/", nil, req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bridgesdk
+
+import (
+	"fmt"
+	"github.com/astaxie/beego/logs"
+	"poly-bridge/models"
+	nft "poly-bridge/nft_http/controllers"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+type NFTInfo struct {
+	sdk    *NFTSdk
+	online bool
+}
+
+func NewNFTInfo(url string) *NFTInfo {
+	sdk := NewNFTSdk(url)
+	return &NFTInfo{
+		sdk:    sdk,
+		online: true,
+	}
+}
+
+type NFTSdkPro struct {
+	infos         map[string]*NFTInfo
+	selectionSlot uint64
+	mutex         sync.Mutex
+}
+
+func NewNFTSdkPro(urls []string, slot uint64) *NFTSdkPro {
+	infos := make(map[string]*NFTInfo, len(urls))
+	for _, url := range urls {
+		infos[url] = NewNFTInfo(url)
+	}
+	pro := &NFTSdkPro{infos: infos, selectionSlot: slot}
+	pro.selection()
+	go pro.NodeSelection()
+	return pro
+}
+
+func (pro *NFTSdkPro) NodeSelection() {
+	for {
+		pro.nodeSelection()
+	}
+}
+
+func (pro *NFTSdkPro) nodeSelection() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("node selection, recover info: %s", string(debug.Stack()))
+		}
+	}()
+	logs.Debug("node selection of nft sdk......")
+	ticker := time.NewTicker(time.Second * time.Duration(pro.selectionSlot))
+	for {
+		select {
+		case <-ticker.C:
+			pro.selection()
+		}
+	}
+}
+
+func (pro *NFTSdkPro) selection() {
+	pro.mutex.Lock()
+	defer func() {
+		pro.mutex.Unlock()
+	}()
+	logs.Debug("select node of nft sdk......")
+	for url, info := range pro.infos {
+		if info == nil {
+			info = NewNFTInfo(url)
+			pro.infos[url] = info
+		}
+		if info == nil {
+			continue
+		}
+		online, err := info.sdk.Info()
+		if err != nil {
+			logs.Error("get server info err: %v, url: %s", err, url)
+		}
+		info.online = online
+	}
+}
+
+func (pro *NFTSdkPro) GetLatest() *NFTInfo {
+	pro.mutex.Lock()
+	defer func() {
+		pro.mutex.Unlock()
+	}()
+	for _, info := range pro.infos {
+		if info != nil && info.online {
+			return info
+		}
+	}
+	return nil
+}
+
+// SetApiKey sets the api key of the X-Api-Key header on all the nodes.
+func (pro *NFTSdkPro) SetApiKey(apiKey string) {
+	pro.mutex.Lock()
+	defer pro.mutex.Unlock()
+	for _, info := range pro.infos {
+		info.sdk.SetApiKey(apiKey)
+	}
+}
+
+// call calls the online nodes in turn until one of them responds, a node which fails is offline until the next
+// selection. The requests refused by a node are not retried on the other nodes.
+func (pro *NFTSdkPro) call(name string, fn func(sdk *NFTSdk) error) error {
+	info := pro.GetLatest()
+	if info == nil {
+		return fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		err := fn(info.sdk)
+		if err == nil {
+			return nil
+		}
+		if !retryable(err, false) {
+			return err
+		}
+		logs.Error("%s err: %v, url: %s", name, err, info.sdk.url)
+		info.online = false
+		info = pro.GetLatest()
+	}
+	return fmt.Errorf("all node is not working")
+}
+
+func (pro *NFTSdkPro) Home(req *nft.HomeReq) (rsp *nft.HomeRsp, err error) {
+	err = pro.call("home", func(sdk *NFTSdk) error {
+		rsp, err = sdk.Home(req)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) Asset(req *nft.AssetReq) (rsp *nft.AssetMap, err error) {
+	err = pro.call("asset", func(sdk *NFTSdk) error {
+		rsp, err = sdk.Asset(req)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) Assets(req *nft.AssetsReq) (rsp *nft.AssetsRsp, err error) {
+	err = pro.call("assets", func(sdk *NFTSdk) error {
+		rsp, err = sdk.Assets(req)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) ReloadAssets() (rsp *nft.AssetsReloadRsp, err error) {
+	err = pro.call("reload assets", func(sdk *NFTSdk) error {
+		rsp, err = sdk.ReloadAssets()
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) Items(req *nft.ItemsOfAddressReq) (rsp *nft.ItemsOfAddressRsp, err error) {
+	err = pro.call("items", func(sdk *NFTSdk) error {
+		rsp, err = sdk.Items(req)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) CheckFee(checks []*models.CheckFeeReq) (rsp []*models.CheckFeeRsp, err error) {
+	err = pro.call("check fee", func(sdk *NFTSdk) error {
+		rsp, err = sdk.CheckFee(checks)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) Media(asset string, tokenId string, thumbnail bool) (data []byte, contentType string, err error) {
+	err = pro.call("media", func(sdk *NFTSdk) error {
+		data, contentType, err = sdk.Media(asset, tokenId, thumbnail)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) GetFee(req *nft.GetFeeReq) (rsp *models.GetFeeRsp, err error) {
+	err = pro.call("get fee", func(sdk *NFTSdk) error {
+		rsp, err = sdk.GetFee(req)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) Collections() (rsp *nft.CollectionsRsp, err error) {
+	err = pro.call("collections", func(sdk *NFTSdk) error {
+		rsp, err = sdk.Collections()
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) CollectionActivity(asset string, pageSize int, pageNo int) (rsp *nft.CollectionActivityRsp, err error) {
+	err = pro.call("collection activity", func(sdk *NFTSdk) error {
+		rsp, err = sdk.CollectionActivity(asset, pageSize, pageNo)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) ExplorerTransactions(req *nft.TransactionBriefsReq) (rsp *nft.TransactionBriefsRsp, err error) {
+	err = pro.call("explorer transactions", func(sdk *NFTSdk) error {
+		rsp, err = sdk.ExplorerTransactions(req)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) ExplorerTransactionsOfAddress(req *nft.TransactionBriefsOfAddressReq) (rsp *nft.TransactionBriefsRsp, err error) {
+	err = pro.call("explorer transactions of address", func(sdk *NFTSdk) error {
+		rsp, err = sdk.ExplorerTransactionsOfAddress(req)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) ExplorerTransactionDetail(req *nft.TransactionDetailReq) (rsp *nft.TransactionDetailRsp, err error) {
+	err = pro.call("explorer transaction detail", func(sdk *NFTSdk) error {
+		rsp, err = sdk.ExplorerTransactionDetail(req)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) TransactionsOfAddress(req *models.TransactionsOfAddressReq) (rsp *nft.TransactionsOfAddressRsp, err error) {
+	err = pro.call("transactions of address", func(sdk *NFTSdk) error {
+		rsp, err = sdk.TransactionsOfAddress(req)
+		return err
+	})
+	return
+}
+
+func (pro *NFTSdkPro) TransactionOfHash(req *models.TransactionOfHashReq) (rsp *nft.TransactionRsp, err error) {
+	err = pro.call("transaction of hash", func(sdk *NFTSdk) error {
+		rsp, err = sdk.TransactionOfHash(req)
+		return err
+	})
+	return
+}
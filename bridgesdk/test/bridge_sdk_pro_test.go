@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"poly-bridge/bridgesdk"
+	"poly-bridge/models"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newBridgeNode serves the fees and the token basics with the status, and counts the calls of them.
+func newBridgeNode(feeStatus int, feeCalls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/":
+			_ = json.NewEncoder(w).Encode(&models.PolyBridgeResp{Version: "v1"})
+		case "/v1/getfee/":
+			atomic.AddInt32(feeCalls, 1)
+			req := new(models.GetFeeReq)
+			_ = json.NewDecoder(r.Body).Decode(req)
+			w.WriteHeader(feeStatus)
+			if feeStatus != http.StatusOK {
+				_ = json.NewEncoder(w).Encode(models.MakeErrorRsp("no fee"))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(&models.GetFeeRsp{SrcChainId: req.SrcChainId, Hash: req.Hash, DstChainId: req.DstChainId})
+		case "/v1/checkswapfee/":
+			req := new(models.CheckFeesReq)
+			_ = json.NewDecoder(r.Body).Decode(req)
+			checkFees := make([]*models.CheckFee, 0)
+			for _, check := range req.Checks {
+				checkFees = append(checkFees, &models.CheckFee{ChainId: check.ChainId, Hash: check.Hash, PayState: bridgesdk.STATE_HASPAY,
+					Amount: new(big.Float), MinProxyFee: new(big.Float)})
+			}
+			_ = json.NewEncoder(w).Encode(models.MakeCheckFeesRsp(checkFees))
+		case "/v1/admin/tokenbasic/":
+			atomic.AddInt32(feeCalls, 1)
+			req := new(models.TokenBasic)
+			_ = json.NewDecoder(r.Body).Decode(req)
+			w.WriteHeader(feeStatus)
+			if feeStatus != http.StatusOK {
+				_ = json.NewEncoder(w).Encode(models.MakeErrorRsp("token basic is not saved"))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(req)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestBridgeSdkPro_Failover(t *testing.T) {
+	var failedCalls, calls int32
+	failed := newBridgeNode(http.StatusInternalServerError, &failedCalls)
+	defer failed.Close()
+	node := newBridgeNode(http.StatusOK, &calls)
+	defer node.Close()
+
+	sdk := bridgesdk.NewBridgeSdkPro([]string{failed.URL + "/v1/", node.URL + "/v1/"}, 60)
+	for i := 0; i < 3; i++ {
+		rsp, err := sdk.GetFee(&models.GetFeeReq{SrcChainId: 2, Hash: "abcd", DstChainId: 6})
+		assert.NoError(t, err)
+		assert.Equal(t, "abcd", rsp.Hash)
+		assert.Equal(t, uint64(6), rsp.DstChainId)
+	}
+	assert.Equal(t, int32(3), calls)
+	assert.True(t, failedCalls <= 1)
+}
+
+func TestBridgeSdkPro_ResponseError(t *testing.T) {
+	var calls int32
+	node1 := newBridgeNode(http.StatusBadRequest, &calls)
+	defer node1.Close()
+	node2 := newBridgeNode(http.StatusBadRequest, &calls)
+	defer node2.Close()
+
+	sdk := bridgesdk.NewBridgeSdkPro([]string{node1.URL + "/v1/", node2.URL + "/v1/"}, 60)
+	_, err := sdk.GetFee(&models.GetFeeReq{SrcChainId: 2, Hash: "abcd", DstChainId: 6})
+	assert.Equal(t, &bridgesdk.ResponseError{StatusCode: http.StatusBadRequest, Message: "no fee"}, err)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestBridgeSdkPro_CheckSwapFee(t *testing.T) {
+	var calls int32
+	node := newBridgeNode(http.StatusOK, &calls)
+	defer node.Close()
+
+	sdk := bridgesdk.NewBridgeSdkPro([]string{node.URL + "/v1/"}, 60)
+	rsp, err := sdk.CheckSwapFee([]*models.CheckFeeReq{{ChainId: 80, Hash: "abcd"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rsp))
+	assert.Equal(t, "abcd", rsp[0].Hash)
+	assert.Equal(t, bridgesdk.STATE_HASPAY, rsp[0].PayState)
+}
+
+func TestBridgeSdkPro_WriteNotRetried(t *testing.T) {
+	var calls int32
+	node1 := newBridgeNode(http.StatusInternalServerError, &calls)
+	defer node1.Close()
+	node2 := newBridgeNode(http.StatusInternalServerError, &calls)
+	defer node2.Close()
+
+	sdk := bridgesdk.NewBridgeSdkPro([]string{node1.URL + "/v1/", node2.URL + "/v1/"}, 60)
+	_, err := sdk.AddTokenBasic(&models.TokenBasic{Name: "USDT"})
+	assert.Equal(t, &bridgesdk.ResponseError{StatusCode: http.StatusInternalServerError, Message: "token basic is not saved"}, err)
+	assert.Equal(t, int32(1), calls, "a write which failed on the node may have been applied")
+
+	// the reads fail over on the server errors
+	_, err = sdk.GetFee(&models.GetFeeReq{SrcChainId: 2, Hash: "abcd", DstChainId: 6})
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), calls)
+}
+
+func TestBridgeSdkPro_WriteFailover(t *testing.T) {
+	var stoppedCalls, calls int32
+	stopped := newBridgeNode(http.StatusOK, &stoppedCalls)
+	node := newBridgeNode(http.StatusOK, &calls)
+	defer node.Close()
+
+	sdk := bridgesdk.NewBridgeSdkPro([]string{stopped.URL + "/v1/", node.URL + "/v1/"}, 60)
+	stopped.Close()
+	for i := 0; i < 3; i++ {
+		rsp, err := sdk.AddTokenBasic(&models.TokenBasic{Name: "USDT"})
+		assert.NoError(t, err, "a write which did not connect the node is sent to the next one")
+		assert.Equal(t, "USDT", rsp.Name)
+	}
+	assert.Equal(t, int32(0), stoppedCalls)
+	assert.Equal(t, int32(3), calls)
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// generationTTL is how long a generation read from the store is used before it is read again,
+// so the other instances see an invalidation within it.
+const generationTTL = time.Second
+
+// Store is the backend of the cache. Get misses on any error of the backend, so that the caller
+// can always fall back to the database.
+type Store interface {
+	Get(key string) ([]byte, bool)
+	// Set stores the value, a ttl of 0 means the value does not expire.
+	Set(key string, value []byte, ttl time.Duration)
+	// Incr increases the counter of key by one and returns the new value, counters are never evicted.
+	Incr(key string) (int64, error)
+}
+
+// Cache keeps the responses in namespaces. Every namespace has a generation which is a part of the keys,
+// so that the whole namespace is invalidated at once by increasing the generation, on all the instances
+// sharing the store. The generations are kept locally for generationTTL.
+type Cache struct {
+	store         Store
+	ttl           time.Duration
+	generationTTL time.Duration
+	mutex         sync.Mutex
+	generations   map[string]*generation
+}
+
+type generation struct {
+	value  int64
+	expire time.Time
+}
+
+func New(store Store, ttl time.Duration) *Cache {
+	return &Cache{
+		store:         store,
+		ttl:           ttl,
+		generationTTL: generationTTL,
+		generations:   make(map[string]*generation),
+	}
+}
+
+func (cache *Cache) Get(namespace string, key string) ([]byte, bool) {
+	return cache.store.Get(cache.key(namespace, key))
+}
+
+func (cache *Cache) Set(namespace string, key string, value []byte) {
+	cache.store.Set(cache.key(namespace, key), value, cache.ttl)
+}
+
+// Invalidate drops all the values of the namespaces.
+func (cache *Cache) Invalidate(namespaces ...string) error {
+	for _, namespace := range namespaces {
+		value, err := cache.store.Incr(generationKey(namespace))
+		if err != nil {
+			return err
+		}
+		cache.mutex.Lock()
+		cache.generations[namespace] = &generation{value: value, expire: time.Now().Add(cache.generationTTL)}
+		cache.mutex.Unlock()
+	}
+	return nil
+}
+
+func (cache *Cache) key(namespace string, key string) string {
+	return fmt.Sprintf("%s:%d:%s", namespace, cache.generation(namespace), key)
+}
+
+// generation returns the local generation of the namespace, it is read from the store when it expires.
+func (cache *Cache) generation(namespace string) int64 {
+	now := time.Now()
+	cache.mutex.Lock()
+	current, ok := cache.generations[namespace]
+	cache.mutex.Unlock()
+	if ok && now.Before(current.expire) {
+		return current.value
+	}
+	value := int64(0)
+	if data, ok := cache.store.Get(generationKey(namespace)); ok {
+		value, _ = strconv.ParseInt(string(data), 10, 64)
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if latest, ok := cache.generations[namespace]; ok && latest.value > value {
+		value = latest.value
+	}
+	cache.generations[namespace] = &generation{value: value, expire: now.Add(cache.generationTTL)}
+	return value
+}
+
+func generationKey(namespace string) string {
+	return "generation:" + namespace
+}
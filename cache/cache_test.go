@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store, err := NewMemoryStore(2)
+	assert.Nil(t, err)
+
+	store.Set("a", []byte("1"), 0)
+	store.Set("b", []byte("2"), time.Millisecond)
+	value, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(value))
+	time.Sleep(time.Millisecond * 5)
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+
+	store.Set("c", []byte("3"), 0)
+	store.Set("d", []byte("4"), 0)
+	_, ok = store.Get("a")
+	assert.False(t, ok)
+
+	counter, err := store.Incr("e")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), counter)
+	store.Set("f", []byte("5"), 0)
+	store.Set("g", []byte("6"), 0)
+	value, ok = store.Get("e")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(value))
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	store, err := NewMemoryStore(100)
+	assert.Nil(t, err)
+	cache := New(store, time.Minute)
+
+	cache.Set("token", "tokens_2", []byte("a"))
+	cache.Set("fee", "fee_2_x_6", []byte("b"))
+	value, ok := cache.Get("token", "tokens_2")
+	assert.True(t, ok)
+	assert.Equal(t, "a", string(value))
+
+	assert.Nil(t, cache.Invalidate("token"))
+	_, ok = cache.Get("token", "tokens_2")
+	assert.False(t, ok)
+	_, ok = cache.Get("fee", "fee_2_x_6")
+	assert.True(t, ok)
+
+	cache.Set("token", "tokens_2", []byte("c"))
+	value, ok = cache.Get("token", "tokens_2")
+	assert.True(t, ok)
+	assert.Equal(t, "c", string(value))
+}
+
+func TestCacheGeneration(t *testing.T) {
+	store, err := NewMemoryStore(100)
+	assert.Nil(t, err)
+	a := New(store, time.Minute)
+	b := New(store, time.Minute)
+	b.generationTTL = time.Millisecond * 20
+
+	a.Set("token", "tokens_2", []byte("a"))
+	_, ok := b.Get("token", "tokens_2")
+	assert.True(t, ok)
+
+	assert.Nil(t, a.Invalidate("token"))
+	_, ok = a.Get("token", "tokens_2")
+	assert.False(t, ok)
+	_, ok = b.Get("token", "tokens_2")
+	assert.True(t, ok)
+	time.Sleep(time.Millisecond * 30)
+	_, ok = b.Get("token", "tokens_2")
+	assert.False(t, ok)
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+type memoryEntry struct {
+	value  []byte
+	expire time.Time
+}
+
+// MemoryStore is an in-process LRU store, it is used when there is no redis or as its stand-in.
+type MemoryStore struct {
+	mutex    sync.Mutex
+	lru      *simplelru.LRU
+	counters map[string]int64
+}
+
+func NewMemoryStore(size int) (*MemoryStore, error) {
+	lru, err := simplelru.NewLRU(size, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryStore{
+		lru:      lru,
+		counters: make(map[string]int64),
+	}, nil
+}
+
+func (store *MemoryStore) Get(key string) ([]byte, bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if counter, ok := store.counters[key]; ok {
+		return []byte(strconv.FormatInt(counter, 10)), true
+	}
+	data, ok := store.lru.Get(key)
+	if !ok {
+		return nil, false
+	}
+	entry := data.(*memoryEntry)
+	if !entry.expire.IsZero() && time.Now().After(entry.expire) {
+		store.lru.Remove(key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (store *MemoryStore) Set(key string, value []byte, ttl time.Duration) {
+	entry := &memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expire = time.Now().Add(ttl)
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.lru.Add(key, entry)
+}
+
+func (store *MemoryStore) Incr(key string) (int64, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.counters[key]++
+	return store.counters[key], nil
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+)
+
+// redisMaxIdle is the number of idle connections kept for reuse, more connections are dialed under load.
+const redisMaxIdle = 16
+
+// RedisStore shares the cache between the instances of the api. It talks the redis protocol on pooled
+// connections, which is enough for the few commands the cache needs. When a command fails, its connection
+// and the idle ones are dropped, and new ones are dialed on the next commands.
+type RedisStore struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+	idle     chan *redisConn
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func NewRedisStore(addr string, password string, db int, timeout time.Duration) *RedisStore {
+	return &RedisStore{
+		addr:     addr,
+		password: password,
+		db:       db,
+		timeout:  timeout,
+		idle:     make(chan *redisConn, redisMaxIdle),
+	}
+}
+
+func (store *RedisStore) Get(key string) ([]byte, bool) {
+	reply, err := store.do("GET", key)
+	if err != nil {
+		logs.Error("redis get %s err: %v", key, err)
+		return nil, false
+	}
+	value, ok := reply.([]byte)
+	return value, ok
+}
+
+func (store *RedisStore) Set(key string, value []byte, ttl time.Duration) {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(int64(ttl/time.Millisecond), 10))
+	}
+	if _, err := store.do(args...); err != nil {
+		logs.Error("redis set %s err: %v", key, err)
+	}
+}
+
+func (store *RedisStore) Incr(key string) (int64, error) {
+	reply, err := store.do("INCR", key)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected reply of incr: %v", reply)
+	}
+	return value, nil
+}
+
+// IncrExpire increases the counter of key by one and returns the new value, the counter expires ttl after it is
+// created by the first increase.
+func (store *RedisStore) IncrExpire(key string, ttl time.Duration) (int64, error) {
+	value, err := store.Incr(key)
+	if err != nil {
+		return 0, err
+	}
+	if value == 1 {
+		if _, err := store.do("PEXPIRE", key, strconv.FormatInt(int64(ttl/time.Millisecond), 10)); err != nil {
+			return 0, err
+		}
+	}
+	return value, nil
+}
+
+func (store *RedisStore) do(args ...string) (interface{}, error) {
+	var conn *redisConn
+	select {
+	case conn = <-store.idle:
+	default:
+		var err error
+		if conn, err = store.connect(); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := conn.command(store.timeout, args...)
+	if err != nil {
+		conn.conn.Close()
+		store.closeIdle()
+		return nil, err
+	}
+	select {
+	case store.idle <- conn:
+	default:
+		conn.conn.Close()
+	}
+	return reply, nil
+}
+
+// closeIdle drops the idle connections after a command fails, they are likely broken the same way.
+func (store *RedisStore) closeIdle() {
+	for {
+		select {
+		case conn := <-store.idle:
+			conn.conn.Close()
+		default:
+			return
+		}
+	}
+}
+
+func (store *RedisStore) connect() (*redisConn, error) {
+	netConn, err := net.DialTimeout("tcp", store.addr, store.timeout)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+	if store.password != "" {
+		if _, err := conn.command(store.timeout, "AUTH", store.password); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	if store.db != 0 {
+		if _, err := conn.command(store.timeout, "SELECT", strconv.Itoa(store.db)); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (conn *redisConn) command(timeout time.Duration, args ...string) (interface{}, error) {
+	if timeout > 0 {
+		conn.conn.SetDeadline(time.Now().Add(timeout))
+	}
+	if _, err := conn.conn.Write(encodeCommand(args...)); err != nil {
+		return nil, err
+	}
+	return readReply(conn.reader)
+}
+
+func encodeCommand(args ...string) []byte {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	return buf
+}
+
+// readReply reads a simple string, error, integer or bulk string reply, a nil bulk string is returned as nil.
+func readReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("invalid reply: %q", line)
+	}
+	line = line[:len(line)-2]
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	default:
+		return nil, fmt.Errorf("unsupported reply: %q", line)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cache
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serveRedis answers GET, SET, INCR and PEXPIRE from a memory store, standing in for a redis server.
+// The counters do not expire.
+func serveRedis(listener net.Listener, store *MemoryStore) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+				args := make([]string, 0, n)
+				for i := 0; i < n; i++ {
+					header, _ := reader.ReadString('\n')
+					size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+					arg := make([]byte, size+2)
+					io.ReadFull(reader, arg)
+					args = append(args, string(arg[:size]))
+				}
+				switch args[0] {
+				case "GET":
+					value, ok := store.Get(args[1])
+					if !ok {
+						conn.Write([]byte("$-1\r\n"))
+					} else {
+						conn.Write([]byte("$" + strconv.Itoa(len(value)) + "\r\n" + string(value) + "\r\n"))
+					}
+				case "SET":
+					ttl := time.Duration(0)
+					if len(args) == 5 {
+						ms, _ := strconv.ParseInt(args[4], 10, 64)
+						ttl = time.Duration(ms) * time.Millisecond
+					}
+					store.Set(args[1], []byte(args[2]), ttl)
+					conn.Write([]byte("+OK\r\n"))
+				case "INCR":
+					counter, _ := store.Incr(args[1])
+					conn.Write([]byte(":" + strconv.FormatInt(counter, 10) + "\r\n"))
+				case "PEXPIRE":
+					conn.Write([]byte(":1\r\n"))
+				default:
+					conn.Write([]byte("-ERR unknown command\r\n"))
+				}
+			}
+		}(conn)
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer listener.Close()
+	memory, _ := NewMemoryStore(100)
+	go serveRedis(listener, memory)
+
+	store := NewRedisStore(listener.Addr().String(), "", 0, time.Second)
+	_, ok := store.Get("a")
+	assert.False(t, ok)
+	store.Set("a", []byte("hello\r\nworld"), time.Minute)
+	value, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "hello\r\nworld", string(value))
+
+	for i := int64(1); i <= 2; i++ {
+		counter, err := store.IncrExpire("quota:a", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, i, counter)
+	}
+
+	cache := New(store, time.Minute)
+	cache.Set("token", "tokens_2", []byte("a"))
+	assert.Nil(t, cache.Invalidate("token"))
+	_, ok = cache.Get("token", "tokens_2")
+	assert.False(t, ok)
+}
+
+func TestRedisStorePool(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer listener.Close()
+	memory, _ := NewMemoryStore(100)
+	go serveRedis(listener, memory)
+
+	store := NewRedisStore(listener.Addr().String(), "", 0, time.Second)
+	done := make(chan bool)
+	for i := 0; i < 32; i++ {
+		go func(i int) {
+			key := strconv.Itoa(i)
+			store.Set(key, []byte(key), time.Minute)
+			value, ok := store.Get(key)
+			done <- ok && string(value) == key
+		}(i)
+	}
+	for i := 0; i < 32; i++ {
+		assert.True(t, <-done)
+	}
+	assert.True(t, len(store.idle) > 0)
+
+	idle := make([]*redisConn, 0)
+	for len(store.idle) > 0 {
+		conn := <-store.idle
+		conn.conn.Close()
+		idle = append(idle, conn)
+	}
+	for _, conn := range idle {
+		store.idle <- conn
+	}
+	_, ok := store.Get("1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, len(store.idle))
+	value, ok := store.Get("1")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(value))
+}
+
+func TestReadReply(t *testing.T) {
+	var testdata = []struct {
+		reply  string
+		expect interface{}
+		err    bool
+	}{
+		{reply: "+OK\r\n", expect: "OK"},
+		{reply: ":42\r\n", expect: int64(42)},
+		{reply: "$3\r\nabc\r\n", expect: []byte("abc")},
+		{reply: "$-1\r\n", expect: nil},
+		{reply: "-ERR wrong type\r\n", err: true},
+		{reply: "*1\r\n", err: true},
+	}
+	for _, v := range testdata {
+		reply, err := readReply(bufio.NewReader(strings.NewReader(v.reply)))
+		if v.err {
+			assert.NotNil(t, err)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, v.expect, reply)
+	}
+	assert.Equal(t, "*2\r\n$3\r\nGET\r\n$1\r\na\r\n", string(encodeCommand("GET", "a")))
+}
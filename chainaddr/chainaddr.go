@@ -0,0 +1,199 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package chainaddr validates, normalizes and converts the addresses of the supported chains.
+// A hash is the hex form of an address saved in db, and an address is the form shown to users.
+package chainaddr
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/joeqian10/neo-gogogo/helper"
+	ontcommon "github.com/ontio/ontology/common"
+	"github.com/tendermint/tendermint/libs/bech32"
+	"poly-bridge/basedef"
+	"strings"
+	"sync"
+)
+
+var (
+	prefixLock     sync.RWMutex
+	bech32Prefixes = map[uint64]string{
+		basedef.SWITCHEO_CROSSCHAIN_ID: "swth",
+	}
+)
+
+// SetBech32Prefix makes the addresses of the chain bech32 encoded with prefix.
+func SetBech32Prefix(chainId uint64, prefix string) {
+	prefixLock.Lock()
+	defer prefixLock.Unlock()
+	bech32Prefixes[chainId] = prefix
+}
+
+func bech32Prefix(chainId uint64) (string, bool) {
+	prefixLock.RLock()
+	defer prefixLock.RUnlock()
+	prefix, ok := bech32Prefixes[chainId]
+	return prefix, ok
+}
+
+func isEthereum(chainId uint64) bool {
+	return chainId == basedef.ETHEREUM_CROSSCHAIN_ID || chainId == basedef.BSC_CROSSCHAIN_ID || chainId == basedef.HECO_CROSSCHAIN_ID
+}
+
+// Validate checks address is an address of the chain.
+func Validate(chainId uint64, address string) error {
+	_, err := ToHash(chainId, address)
+	return err
+}
+
+// Normalize returns the canonical form of address: EIP55 checksummed hex for ethereum like chains,
+// base58 for neo and ontology, and bech32 for cosmos chains.
+func Normalize(chainId uint64, address string) (string, error) {
+	hash, err := ToHash(chainId, address)
+	if err != nil {
+		return "", err
+	}
+	if isEthereum(chainId) {
+		return common.HexToAddress(hash).Hex(), nil
+	}
+	return FromHash(chainId, hash)
+}
+
+// ToHash converts address to the hash saved in db, like basedef.Address2Hash but returns an error for invalid address.
+// Addresses of the chains without a known format are returned as they are.
+func ToHash(chainId uint64, address string) (string, error) {
+	if address == "" {
+		return "", fmt.Errorf("address of chain %d is empty", chainId)
+	}
+	if isEthereum(chainId) {
+		if !common.IsHexAddress(address) {
+			return "", fmt.Errorf("%s is not a hex address", address)
+		}
+		addr := common.HexToAddress(address)
+		raw := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+		if raw != strings.ToLower(raw) && raw != strings.ToUpper(raw) && addr.Hex()[2:] != raw {
+			return "", fmt.Errorf("%s has an invalid checksum", address)
+		}
+		return strings.ToLower(addr.Hex()[2:]), nil
+	}
+	if chainId == basedef.NEO_CROSSCHAIN_ID {
+		scriptHash, err := helper.AddressToScriptHash(address)
+		if err != nil {
+			return "", fmt.Errorf("%s is not a neo address: %v", address, err)
+		}
+		return hex.EncodeToString(scriptHash.Bytes()), nil
+	}
+	if chainId == basedef.ONT_CROSSCHAIN_ID {
+		addr, err := ontcommon.AddressFromBase58(address)
+		if err != nil {
+			return "", fmt.Errorf("%s is not an ontology address: %v", address, err)
+		}
+		return basedef.HexStringReverse(addr.ToHexString()), nil
+	}
+	if prefix, ok := bech32Prefix(chainId); ok {
+		hrp, data, err := bech32.DecodeAndConvert(address)
+		if err != nil {
+			return "", fmt.Errorf("%s is not a bech32 address: %v", address, err)
+		}
+		if hrp != prefix {
+			return "", fmt.Errorf("prefix of %s is not %s", address, prefix)
+		}
+		if len(data) != 20 {
+			return "", fmt.Errorf("%s is not 20 bytes", address)
+		}
+		return hex.EncodeToString(data), nil
+	}
+	return address, nil
+}
+
+// FromHash converts the hash saved in db to address, like basedef.Hash2Address but returns an error for invalid hash.
+// Ethereum like addresses are lower case hex without 0x, the same as their hashes.
+func FromHash(chainId uint64, hash string) (string, error) {
+	if isEthereum(chainId) {
+		if !common.IsHexAddress(hash) {
+			return "", fmt.Errorf("%s is not a hex address", hash)
+		}
+		return strings.ToLower(common.HexToAddress(hash).Hex()[2:]), nil
+	}
+	if chainId != basedef.NEO_CROSSCHAIN_ID && chainId != basedef.ONT_CROSSCHAIN_ID {
+		if _, ok := bech32Prefix(chainId); !ok {
+			return hash, nil
+		}
+	}
+	data, err := hex.DecodeString(hash)
+	if err != nil || len(data) != 20 {
+		return "", fmt.Errorf("%s is not a 20 bytes hex", hash)
+	}
+	if chainId == basedef.NEO_CROSSCHAIN_ID {
+		scriptHash, err := helper.UInt160FromBytes(data)
+		if err != nil {
+			return "", err
+		}
+		return helper.ScriptHashToAddress(scriptHash), nil
+	}
+	if chainId == basedef.ONT_CROSSCHAIN_ID {
+		addr, err := ontcommon.AddressParseFromBytes(data)
+		if err != nil {
+			return "", err
+		}
+		return addr.ToBase58(), nil
+	}
+	prefix, _ := bech32Prefix(chainId)
+	return bech32.ConvertAndEncode(prefix, data)
+}
+
+func supportedChains() []uint64 {
+	chainIds := []uint64{basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID, basedef.HECO_CROSSCHAIN_ID, basedef.NEO_CROSSCHAIN_ID, basedef.ONT_CROSSCHAIN_ID}
+	prefixLock.RLock()
+	defer prefixLock.RUnlock()
+	for chainId := range bech32Prefixes {
+		chainIds = append(chainIds, chainId)
+	}
+	return chainIds
+}
+
+// Hashes returns every form address may be saved as in db, address is parsed by the format of every supported chain
+// when chainId is 0. A 20 bytes hex is also matched reversed, since neo and ontology hashes are shown reversed.
+func Hashes(chainId uint64, address string) []string {
+	address = strings.TrimSpace(address)
+	hashes := make([]string, 0)
+	exist := make(map[string]bool)
+	add := func(hash string) {
+		if hash != "" && !exist[hash] {
+			exist[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+	add(address)
+	chainIds := []uint64{chainId}
+	if chainId == 0 {
+		chainIds = supportedChains()
+	}
+	for _, item := range chainIds {
+		if hash, err := ToHash(item, address); err == nil {
+			add(hash)
+		}
+	}
+	raw := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X"))
+	if data, err := hex.DecodeString(raw); err == nil && len(data) == 20 {
+		add(raw)
+		add(hex.EncodeToString(basedef.HexReverse(data)))
+	}
+	return hashes
+}
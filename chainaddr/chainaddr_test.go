@@ -0,0 +1,70 @@
+package chainaddr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+)
+
+func TestConvert(t *testing.T) {
+	var testdata = []struct {
+		chainId uint64
+		address string
+	}{
+		{chainId: basedef.NEO_CROSSCHAIN_ID, address: "AQzRMe3zyGS8W177xLJfewRRQZY2kddMun"},
+		{chainId: basedef.ONT_CROSSCHAIN_ID, address: "AFmseVrdL9f9oyCzZefL9tG6UbvhUMqNMV"},
+		{chainId: basedef.SWITCHEO_CROSSCHAIN_ID, address: "swth1pxjs4dzpt3s5pcqqjfgxh0gpjhqmtl7d6yvdgz"},
+	}
+	for _, item := range testdata {
+		hash, err := ToHash(item.chainId, item.address)
+		if !assert.NoError(t, err, item.address) {
+			continue
+		}
+		address, err := FromHash(item.chainId, hash)
+		assert.NoError(t, err)
+		assert.Equal(t, item.address, address)
+		if item.chainId != basedef.SWITCHEO_CROSSCHAIN_ID {
+			// basedef needs the bech32 prefix of the cosmos sdk config to be set
+			assert.Equal(t, basedef.Address2Hash(item.chainId, item.address), hash)
+			assert.Equal(t, basedef.Hash2Address(item.chainId, hash), address)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var testdata = []struct {
+		chainId uint64
+		address string
+		valid   bool
+	}{
+		{chainId: basedef.ETHEREUM_CROSSCHAIN_ID, address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", valid: true},
+		{chainId: basedef.ETHEREUM_CROSSCHAIN_ID, address: "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", valid: true},
+		{chainId: basedef.ETHEREUM_CROSSCHAIN_ID, address: "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", valid: true},
+		{chainId: basedef.ETHEREUM_CROSSCHAIN_ID, address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", valid: false},
+		{chainId: basedef.BSC_CROSSCHAIN_ID, address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", valid: false},
+		{chainId: basedef.NEO_CROSSCHAIN_ID, address: "AQzRMe3zyGS8W177xLJfewRRQZY2kddMuN", valid: false},
+		{chainId: basedef.ONT_CROSSCHAIN_ID, address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", valid: false},
+		{chainId: basedef.SWITCHEO_CROSSCHAIN_ID, address: "cosmos1pxjs4dzpt3s5pcqqjfgxh0gpjhqmtl7d96xmmm", valid: false},
+		{chainId: basedef.HECO_CROSSCHAIN_ID, address: "", valid: false},
+	}
+	for _, item := range testdata {
+		err := Validate(item.chainId, item.address)
+		assert.Equal(t, item.valid, err == nil, item.address)
+	}
+	address, err := Normalize(basedef.ETHEREUM_CROSSCHAIN_ID, "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", address)
+}
+
+func TestHashes(t *testing.T) {
+	assert.Equal(t, []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", basedef.HexStringReverse("5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")},
+		Hashes(0, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+	assert.Equal(t, []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", basedef.HexStringReverse("5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")},
+		Hashes(basedef.BSC_CROSSCHAIN_ID, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+	ontHash, _ := ToHash(basedef.ONT_CROSSCHAIN_ID, "AFmseVrdL9f9oyCzZefL9tG6UbvhUMqNMV")
+	assert.Equal(t, []string{"AFmseVrdL9f9oyCzZefL9tG6UbvhUMqNMV", ontHash}, Hashes(0, "AFmseVrdL9f9oyCzZefL9tG6UbvhUMqNMV"))
+	// neo and ontology addresses share the same base58 format
+	assert.Equal(t, []string{"AFmseVrdL9f9oyCzZefL9tG6UbvhUMqNMV", ontHash}, Hashes(basedef.NEO_CROSSCHAIN_ID, "AFmseVrdL9f9oyCzZefL9tG6UbvhUMqNMV"))
+	assert.Equal(t, []string{"swth1pxjs4dzpt3s5pcqqjfgxh0gpjhqmtl7d6yvdgz"}, Hashes(basedef.ETHEREUM_CROSSCHAIN_ID, "swth1pxjs4dzpt3s5pcqqjfgxh0gpjhqmtl7d6yvdgz"))
+}
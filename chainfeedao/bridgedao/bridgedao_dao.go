@@ -51,7 +51,7 @@ func NewBridgeDao(dbCfg *conf.DBConfig) *BridgeDao {
 
 func (dao *BridgeDao) GetFees() ([]*models.ChainFee, error) {
 	fees := make([]*models.ChainFee, 0)
-	res := dao.db.Find(&fees)
+	res := dao.db.Preload("AssetFees").Find(&fees)
 	if res.Error != nil {
 		return nil, res.Error
 	}
@@ -62,7 +62,7 @@ func (dao *BridgeDao) GetFees() ([]*models.ChainFee, error) {
 }
 func (dao *BridgeDao) SaveFees(fees []*models.ChainFee) error {
 	if fees != nil && len(fees) > 0 {
-		res := dao.db.Save(fees)
+		res := dao.db.Session(&gorm.Session{FullSaveAssociations: true}).Save(fees)
 		if res.Error != nil {
 			return res.Error
 		}
@@ -60,3 +60,7 @@ func (this *EthereumFee) GetChainId() uint64 {
 func (this *EthereumFee) Name() string {
 	return this.ethCfg.ChainName
 }
+
+func (this *EthereumFee) GetConfig() *conf.FeeListenConfig {
+	return this.ethCfg
+}
@@ -62,6 +62,7 @@ type ChainFee interface {
 	GetFee() (*big.Int, *big.Int, *big.Int, error)
 	GetChainId() uint64
 	Name() string
+	GetConfig() *conf.FeeListenConfig
 }
 
 func NewChainFee(cfg *conf.FeeListenConfig, feeUpdateSlot int64) ChainFee {
@@ -205,6 +206,7 @@ func (fl *FeeListen) updateChainFees(chainFees []*models.ChainFee) error {
 		fee.ProxyFee = models.NewBigInt(proxyFee)
 		fee.Time = time.Now().Unix()
 		fee.Ind = 1
+		fl.updateAssetFees(fee, query.GetConfig())
 	}
 	for _, fee := range chainFees {
 		if fee.Ind == 0 {
@@ -214,6 +216,52 @@ func (fl *FeeListen) updateChainFees(chainFees []*models.ChainFee) error {
 	return nil
 }
 
+func (fl *FeeListen) updateAssetFees(fee *models.ChainFee, cfg *conf.FeeListenConfig) {
+	for _, assetFee := range fee.AssetFees {
+		assetFee.Ind = 0
+	}
+	if cfg.GasLimit <= 0 {
+		if cfg.NFTGasLimit > 0 || len(cfg.AssetGasLimits) > 0 {
+			logs.Error("asset fees of chain %d need a gas limit", fee.ChainId)
+		}
+		return
+	}
+	if cfg.NFTGasLimit > 0 {
+		fl.updateAssetFee(fee, models.TokenTypeErc721, "", cfg.NFTGasLimit, cfg.GasLimit)
+		fl.updateAssetFee(fee, models.TokenTypeErc1155, "", cfg.NFTGasLimit, cfg.GasLimit)
+	}
+	for _, assetGasLimit := range cfg.AssetGasLimits {
+		fl.updateAssetFee(fee, assetGasLimit.Standard, strings.ToLower(assetGasLimit.Hash), assetGasLimit.GasLimit, cfg.GasLimit)
+	}
+}
+
+func (fl *FeeListen) updateAssetFee(fee *models.ChainFee, standard uint8, hash string, gasLimit int64, baseGasLimit int64) {
+	var assetFee *models.AssetFee
+	for _, item := range fee.AssetFees {
+		if item.Standard == standard && item.Hash == hash {
+			assetFee = item
+			break
+		}
+	}
+	if assetFee == nil {
+		assetFee = &models.AssetFee{
+			ChainId:  fee.ChainId,
+			Standard: standard,
+			Hash:     hash,
+		}
+		fee.AssetFees = append(fee.AssetFees, assetFee)
+	}
+	scale := func(value *models.BigInt) *models.BigInt {
+		x := new(big.Int).Mul(&value.Int, big.NewInt(gasLimit))
+		return models.NewBigInt(new(big.Int).Div(x, big.NewInt(baseGasLimit)))
+	}
+	assetFee.MinFee = scale(fee.MinFee)
+	assetFee.MaxFee = scale(fee.MaxFee)
+	assetFee.ProxyFee = scale(fee.ProxyFee)
+	assetFee.Time = fee.Time
+	assetFee.Ind = 1
+}
+
 func (fl *FeeListen) GetChainFees() string {
 	fees := make([]string, 0)
 	for _, fee := range fl.fees {
@@ -18,12 +18,19 @@
 package neofee
 
 import (
+	"github.com/shopspring/decimal"
 	"math/big"
 	"poly-bridge/basedef"
 	"poly-bridge/chainsdk"
 	"poly-bridge/conf"
 )
 
+var (
+	// neo gas precision and the free system fee of every invocation
+	gasPrecision = decimal.NewFromInt(100000000)
+	freeGas      = decimal.NewFromInt(10)
+)
+
 type NeoFee struct {
 	neoCfg *conf.FeeListenConfig
 	neoSdk *chainsdk.NeoSdkPro
@@ -40,14 +47,71 @@ func NewNeoFee(neoCfg *conf.FeeListenConfig, feeUpdateSlot int64) *NeoFee {
 }
 
 func (this *NeoFee) GetFee() (*big.Int, *big.Int, *big.Int, error) {
-	gasPrice := new(big.Int).SetUint64(1)
-	gasPrice = new(big.Int).Mul(gasPrice, big.NewInt(basedef.FEE_PRECISION))
-	gasPrice = new(big.Int).Mul(gasPrice, big.NewInt(this.neoCfg.GasLimit))
-	proxyFee := new(big.Int).Mul(gasPrice, new(big.Int).SetInt64(this.neoCfg.ProxyFee))
+	systemFee, err := this.getSystemFee()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	networkFee, err := this.getNetworkFee()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	gasFee := new(big.Int).Add(systemFee, networkFee)
+	gasFee = new(big.Int).Mul(gasFee, big.NewInt(basedef.FEE_PRECISION))
+	proxyFee := new(big.Int).Mul(gasFee, new(big.Int).SetInt64(this.neoCfg.ProxyFee))
 	proxyFee = new(big.Int).Div(proxyFee, new(big.Int).SetInt64(100))
-	minFee := new(big.Int).Mul(gasPrice, new(big.Int).SetInt64(this.neoCfg.MinFee))
+	minFee := new(big.Int).Mul(gasFee, new(big.Int).SetInt64(this.neoCfg.MinFee))
 	minFee = new(big.Int).Div(minFee, new(big.Int).SetInt64(100))
-	return minFee, gasPrice, proxyFee, nil
+	return minFee, gasFee, proxyFee, nil
+}
+
+// getSystemFee returns the system fee of the unlock in the smallest unit of GAS. The gas consumed by invoking the
+// estimate script is used when it is configured, it is charged in whole GAS beyond the free 10 GAS.
+func (this *NeoFee) getSystemFee() (*big.Int, error) {
+	if this.neoCfg.EstimateScript == "" {
+		return big.NewInt(this.neoCfg.GasLimit), nil
+	}
+	result, err := this.neoSdk.InvokeScript(this.neoCfg.EstimateScript)
+	if err != nil {
+		return nil, err
+	}
+	gasConsumed, err := decimal.NewFromString(result.GasConsumed)
+	if err != nil {
+		return nil, err
+	}
+	systemFee := gasConsumed.Sub(freeGas).Ceil()
+	if systemFee.Sign() < 0 {
+		systemFee = decimal.Zero
+	}
+	return systemFee.Mul(gasPrecision).BigInt(), nil
+}
+
+// getNetworkFee returns the average network fee of the invocation transactions in the latest block.
+func (this *NeoFee) getNetworkFee() (*big.Int, error) {
+	height, err := this.neoSdk.GetBlockCount()
+	if err != nil {
+		return nil, err
+	}
+	block, err := this.neoSdk.GetBlockByIndex(height - 1)
+	if err != nil {
+		return nil, err
+	}
+	total := decimal.Zero
+	count := int64(0)
+	for _, tx := range block.Tx {
+		if tx.Type != "InvocationTransaction" {
+			continue
+		}
+		netFee, err := decimal.NewFromString(tx.NetFee)
+		if err != nil {
+			continue
+		}
+		total = total.Add(netFee)
+		count++
+	}
+	if count == 0 {
+		return big.NewInt(0), nil
+	}
+	return total.Mul(gasPrecision).Div(decimal.NewFromInt(count)).Ceil().BigInt(), nil
 }
 
 func (this *NeoFee) GetChainId() uint64 {
@@ -57,3 +121,7 @@ func (this *NeoFee) GetChainId() uint64 {
 func (this *NeoFee) Name() string {
 	return this.neoCfg.ChainName
 }
+
+func (this *NeoFee) GetConfig() *conf.FeeListenConfig {
+	return this.neoCfg
+}
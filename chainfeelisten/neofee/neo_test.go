@@ -0,0 +1,75 @@
+package neofee
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"poly-bridge/conf"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newNeoNode(t *testing.T, gasConsumed string, netFees []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := struct {
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatal(err)
+		}
+		var result interface{}
+		switch req.Method {
+		case "getblockcount":
+			result = 100
+		case "getblock":
+			txs := []map[string]interface{}{{"txid": "0x01", "type": "MinerTransaction", "net_fee": "0", "sys_fee": "0"}}
+			for _, netFee := range netFees {
+				txs = append(txs, map[string]interface{}{"txid": "0x02", "type": "InvocationTransaction", "net_fee": netFee, "sys_fee": "0"})
+			}
+			result = map[string]interface{}{"index": 99, "tx": txs}
+		case "invokescript":
+			result = map[string]interface{}{"script": req.Params[0], "state": "HALT", "gas_consumed": gasConsumed, "stack": []interface{}{}}
+		default:
+			t.Fatalf("unexpected method: %s", req.Method)
+		}
+		data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+		w.Write(data)
+	}))
+}
+
+func TestNeoFee(t *testing.T) {
+	var testdata = []struct {
+		script      string
+		gasConsumed string
+		netFees     []string
+		expect      int64
+	}{
+		{script: "", gasConsumed: "0", netFees: nil, expect: 300000},
+		{script: "", gasConsumed: "0", netFees: []string{"0.001", "0.002"}, expect: 300000 + 150000},
+		{script: "00c1", gasConsumed: "4.512", netFees: nil, expect: 0},
+		{script: "00c1", gasConsumed: "12.3", netFees: []string{"0.001"}, expect: 300000000 + 100000},
+	}
+
+	for _, v := range testdata {
+		node := newNeoNode(t, v.gasConsumed, v.netFees)
+		cfg := &conf.FeeListenConfig{
+			ChainId:        4,
+			ChainName:      "NEO",
+			Nodes:          []*conf.Restful{{Url: node.URL}},
+			ProxyFee:       140,
+			MinFee:         40,
+			GasLimit:       300000,
+			EstimateScript: v.script,
+		}
+		minFee, maxFee, proxyFee, err := NewNeoFee(cfg, 60).GetFee()
+		node.Close()
+		assert.Nil(t, err)
+		assert.Equal(t, v.expect*100000000, maxFee.Int64())
+		assert.Equal(t, v.expect*100000000*140/100, proxyFee.Int64())
+		assert.Equal(t, v.expect*100000000*40/100, minFee.Int64())
+	}
+}
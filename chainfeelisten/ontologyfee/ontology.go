@@ -40,7 +40,11 @@ func NewOntologyFee(ontologyCfg *conf.FeeListenConfig, feeUpdateSlot int64) *Ont
 }
 
 func (this *OntologyFee) GetFee() (*big.Int, *big.Int, *big.Int, error) {
-	gasPrice := new(big.Int).SetUint64(1)
+	price, err := this.ontologySdk.GetGasPrice()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	gasPrice := new(big.Int).SetUint64(price)
 	gasPrice = new(big.Int).Mul(gasPrice, big.NewInt(basedef.FEE_PRECISION))
 	gasPrice = new(big.Int).Mul(gasPrice, big.NewInt(this.ontologyCfg.GasLimit))
 	proxyFee := new(big.Int).Mul(gasPrice, new(big.Int).SetInt64(this.ontologyCfg.ProxyFee))
@@ -57,3 +61,7 @@ func (this *OntologyFee) GetChainId() uint64 {
 func (this *OntologyFee) Name() string {
 	return this.ontologyCfg.ChainName
 }
+
+func (this *OntologyFee) GetConfig() *conf.FeeListenConfig {
+	return this.ontologyCfg
+}
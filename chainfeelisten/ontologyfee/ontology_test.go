@@ -0,0 +1,54 @@
+package ontologyfee
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"poly-bridge/conf"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newOntologyNode(t *testing.T, gasPrice uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := struct {
+			Method string `json:"method"`
+		}{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatal(err)
+		}
+		var result interface{}
+		switch req.Method {
+		case "getblockcount":
+			result = 100
+		case "getgasprice":
+			result = map[string]interface{}{"gasprice": gasPrice, "height": 99}
+		default:
+			t.Fatalf("unexpected method: %s", req.Method)
+		}
+		data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": "1", "desc": "SUCCESS", "error": 0, "result": result})
+		w.Write(data)
+	}))
+}
+
+func TestOntologyFee(t *testing.T) {
+	node := newOntologyNode(t, 2500)
+	defer node.Close()
+	cfg := &conf.FeeListenConfig{
+		ChainId:   3,
+		ChainName: "Ontology",
+		Nodes:     []*conf.Restful{{Url: node.URL}},
+		ProxyFee:  120,
+		MinFee:    20,
+		GasLimit:  80000,
+	}
+	minFee, maxFee, proxyFee, err := NewOntologyFee(cfg, 60).GetFee()
+	assert.Nil(t, err)
+	expect := int64(2500 * 80000 * 100000000)
+	assert.Equal(t, expect, maxFee.Int64())
+	assert.Equal(t, expect*120/100, proxyFee.Int64())
+	assert.Equal(t, expect*20/100, minFee.Int64())
+}
@@ -1,15 +1,20 @@
 package switcheofee
 
 import (
+	"encoding/hex"
+	"fmt"
+	"github.com/astaxie/beego/logs"
 	"math/big"
 	"poly-bridge/basedef"
 	"poly-bridge/chainsdk"
 	"poly-bridge/conf"
+	"sort"
 )
 
 type SwitcheoFee struct {
-	swthCfg *conf.FeeListenConfig
-	swthSdk *chainsdk.SwitcheoSdkPro
+	swthCfg  *conf.FeeListenConfig
+	swthSdk  *chainsdk.SwitcheoSdkPro
+	gasPrice uint64
 }
 
 func NewSwitcheoFee(swthCfg *conf.FeeListenConfig, feeUpdateSlot int64) *SwitcheoFee {
@@ -22,9 +27,17 @@ func NewSwitcheoFee(swthCfg *conf.FeeListenConfig, feeUpdateSlot int64) *Switche
 }
 
 func (this *SwitcheoFee) GetFee() (*big.Int, *big.Int, *big.Int, error) {
-	gasPrice := new(big.Int).SetUint64(0)
+	err := this.updateGasPrice()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	gasLimit, err := this.getGasLimit()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	gasPrice := new(big.Int).SetUint64(this.gasPrice)
 	gasPrice = new(big.Int).Mul(gasPrice, big.NewInt(basedef.FEE_PRECISION))
-	gasPrice = new(big.Int).Mul(gasPrice, big.NewInt(this.swthCfg.GasLimit))
+	gasPrice = new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
 	proxyFee := new(big.Int).Mul(gasPrice, new(big.Int).SetInt64(this.swthCfg.ProxyFee))
 	proxyFee = new(big.Int).Div(proxyFee, new(big.Int).SetInt64(100))
 	minFee := new(big.Int).Mul(gasPrice, new(big.Int).SetInt64(this.swthCfg.MinFee))
@@ -32,6 +45,56 @@ func (this *SwitcheoFee) GetFee() (*big.Int, *big.Int, *big.Int, error) {
 	return minFee, gasPrice, proxyFee, nil
 }
 
+// updateGasPrice sets the gas price to the median gas price paid by the transactions of the latest block,
+// the last gas price is kept when the block has no transaction.
+func (this *SwitcheoFee) updateGasPrice() error {
+	height, err := this.swthSdk.GetLatestHeight()
+	if err != nil {
+		return err
+	}
+	block, err := this.swthSdk.GetBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+	if block == nil || block.Block == nil {
+		return fmt.Errorf("block %d of chain %d is not found", height, this.swthCfg.ChainId)
+	}
+	gasPrices := make([]uint64, 0)
+	for _, tx := range block.Block.Data.Txs {
+		gasPrice, err := this.swthSdk.GetGas(tx)
+		if err != nil {
+			return err
+		}
+		if gasPrice > 0 {
+			gasPrices = append(gasPrices, gasPrice)
+		}
+	}
+	if len(gasPrices) == 0 {
+		if this.gasPrice == 0 {
+			return fmt.Errorf("no gas price of chain %d is found yet", this.swthCfg.ChainId)
+		}
+		logs.Debug("no transaction in block %d of chain %d, keep gas price %d", height, this.swthCfg.ChainId, this.gasPrice)
+		return nil
+	}
+	sort.Slice(gasPrices, func(i, j int) bool {
+		return gasPrices[i] < gasPrices[j]
+	})
+	this.gasPrice = gasPrices[len(gasPrices)/2]
+	return nil
+}
+
+// getGasLimit simulates the estimate tx when it is configured, and uses the gas limit of config otherwise.
+func (this *SwitcheoFee) getGasLimit() (uint64, error) {
+	if this.swthCfg.EstimateScript == "" {
+		return uint64(this.swthCfg.GasLimit), nil
+	}
+	tx, err := hex.DecodeString(this.swthCfg.EstimateScript)
+	if err != nil {
+		return 0, err
+	}
+	return this.swthSdk.Simulate(tx)
+}
+
 func (this *SwitcheoFee) GetChainId() uint64 {
 	return this.swthCfg.ChainId
 }
@@ -39,3 +102,7 @@ func (this *SwitcheoFee) GetChainId() uint64 {
 func (this *SwitcheoFee) Name() string {
 	return this.swthCfg.ChainName
 }
+
+func (this *SwitcheoFee) GetConfig() *conf.FeeListenConfig {
+	return this.swthCfg
+}
@@ -0,0 +1,86 @@
+package switcheofee
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"poly-bridge/chainsdk"
+	"poly-bridge/conf"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSwitcheoTx(t *testing.T, amount int64, gas uint64) []byte {
+	cdc := chainsdk.NewCDC()
+	fee := auth.NewStdFee(gas, types.NewCoins(types.NewInt64Coin("swth", amount)))
+	var tx types.Tx = auth.NewStdTx(nil, fee, nil, "")
+	data, err := cdc.MarshalBinaryLengthPrefixed(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newSwitcheoNode(t *testing.T, txs [][]byte, gasUsed uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := struct {
+			Id     interface{} `json:"id"`
+			Method string      `json:"method"`
+		}{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatal(err)
+		}
+		var result interface{}
+		switch req.Method {
+		case "status":
+			result = map[string]interface{}{"sync_info": map[string]interface{}{"latest_block_height": "100"}}
+		case "block":
+			result = map[string]interface{}{"block": map[string]interface{}{"data": map[string]interface{}{"txs": txs}}}
+		case "abci_query":
+			value, err := codec.Cdc.MarshalBinaryBare(types.SimulationResponse{GasInfo: types.GasInfo{GasWanted: gasUsed, GasUsed: gasUsed}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result = map[string]interface{}{"response": map[string]interface{}{"code": 0, "value": value}}
+		default:
+			t.Fatalf("unexpected method: %s", req.Method)
+		}
+		data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": result})
+		w.Write(data)
+	}))
+}
+
+func TestSwitcheoFee(t *testing.T) {
+	// the txs of the switcheo modules whose codecs are not registered can not be decoded and pay no gas price
+	undecodable := []byte{0x0a, 0x0b, 0x0c, 0x0d}
+	txs := [][]byte{newSwitcheoTx(t, 100000, 100000), undecodable, newSwitcheoTx(t, 300000, 100000), newSwitcheoTx(t, 200000, 100000)}
+	node := newSwitcheoNode(t, txs, 150000)
+	defer node.Close()
+	cfg := &conf.FeeListenConfig{
+		ChainId:   5,
+		ChainName: "Switcheo",
+		Nodes:     []*conf.Restful{{Url: node.URL}},
+		ProxyFee:  120,
+		MinFee:    20,
+		GasLimit:  100000,
+	}
+	fee := NewSwitcheoFee(cfg, 60)
+	_, maxFee, _, err := fee.GetFee()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2*100000*100000000), maxFee.Int64())
+
+	cfg.EstimateScript = hex.EncodeToString(newSwitcheoTx(t, 0, 0))
+	minFee, maxFee, proxyFee, err := fee.GetFee()
+	assert.Nil(t, err)
+	expect := int64(2 * 150000 * 100000000)
+	assert.Equal(t, expect, maxFee.Int64())
+	assert.Equal(t, expect*120/100, proxyFee.Int64())
+	assert.Equal(t, expect*20/100, minFee.Int64())
+}
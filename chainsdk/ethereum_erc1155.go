@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package chainsdk
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const erc1155Abi = `[
+	{"constant":true,"name":"uri","type":"function","stateMutability":"view",
+		"inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+	{"constant":true,"name":"balanceOf","type":"function","stateMutability":"view",
+		"inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+var erc1155ABI abi.ABI
+
+func init() {
+	var err error
+	if erc1155ABI, err = abi.JSON(strings.NewReader(erc1155Abi)); err != nil {
+		panic(err)
+	}
+}
+
+// ERC1155Url replaces the {id} of the ERC1155 metadata uri with the lower case hex token id padded to 64 characters.
+func ERC1155Url(uri string, tokenId *big.Int) string {
+	return strings.Replace(uri, "{id}", fmt.Sprintf("%064x", tokenId), -1)
+}
+
+func (s *EthereumSdk) GetERC1155TokenUri(asset common.Address, tokenId *big.Int) (string, error) {
+	contract := bind.NewBoundContract(asset, erc1155ABI, s.backend(), nil, nil)
+	var uri string
+	if err := contract.Call(nil, &uri, "uri", tokenId); err != nil {
+		return "", err
+	}
+	return ERC1155Url(uri, tokenId), nil
+}
+
+func (s *EthereumSdk) GetERC1155Balance(asset, owner common.Address, tokenId *big.Int) (*big.Int, error) {
+	contract := bind.NewBoundContract(asset, erc1155ABI, s.backend(), nil, nil)
+	balance := new(big.Int)
+	if err := contract.Call(nil, &balance, "balanceOf", owner, tokenId); err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+func (pro *EthereumSdkPro) GetERC1155Url(asset common.Address, tokenId *big.Int) (url string, err error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return "", fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		if url, err = info.sdk.GetERC1155TokenUri(asset, tokenId); err != nil {
+			info = pro.reset(info)
+		} else {
+			return
+		}
+	}
+	return
+}
+
+func (pro *EthereumSdkPro) ERC1155Balance(asset, owner common.Address, tokenId *big.Int) (balance *big.Int, err error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		if balance, err = info.sdk.GetERC1155Balance(asset, owner, tokenId); err != nil {
+			info = pro.reset(info)
+		} else {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package chainsdk
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"poly-bridge/go_abi/eccmp_abi"
+	nftlp "poly-bridge/go_abi/nft_lock_proxy_abi"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	polycm "github.com/polynetwork/poly/common"
+)
+
+var nftLockProxyABI abi.ABI
+
+func init() {
+	var err error
+	if nftLockProxyABI, err = abi.JSON(strings.NewReader(nftlp.PolyNFTLockProxyABI)); err != nil {
+		panic(err)
+	}
+}
+
+// unlockError is an invalid unlock or an unlock the proxy can not do, the other nodes return it as well.
+type unlockError struct {
+	error
+}
+
+// NFTUnlockArgs serializes the tx args of the NFT lock proxy unlock, as the source proxy does in the cross chain tx.
+func NFTUnlockArgs(toAsset, toAddress common.Address, tokenId *big.Int, tokenUri string) ([]byte, error) {
+	if tokenId.Sign() < 0 || tokenId.BitLen() > 255 {
+		return nil, fmt.Errorf("token id %s is out of uint255", tokenId.String())
+	}
+	id, err := polycm.Uint256ParseFromBytes(polycm.ToArrayReverse(common.LeftPadBytes(tokenId.Bytes(), 32)))
+	if err != nil {
+		return nil, err
+	}
+	sink := polycm.NewZeroCopySink(nil)
+	sink.WriteVarBytes(toAsset.Bytes())
+	sink.WriteVarBytes(toAddress.Bytes())
+	sink.WriteHash(id)
+	sink.WriteVarBytes([]byte(tokenUri))
+	return sink.Bytes(), nil
+}
+
+// EstimateNFTUnlockGas simulates the cross chain manager calling the unlock of the NFT lock proxy for a token
+// locked in it, the token is sent to toAddress. The unlock sets tokenUri to the token, so the gas depends on it.
+// It is the gas of the proxy only, without the header verification.
+func (s *EthereumSdk) EstimateNFTUnlockGas(proxy, toAsset, toAddress common.Address, tokenId *big.Int, tokenUri string, fromChainId uint64) (uint64, error) {
+	lockProxy, err := nftlp.NewPolyNFTLockProxyCaller(proxy, s.backend())
+	if err != nil {
+		return 0, err
+	}
+	fromProxy, err := lockProxy.ProxyHashMap(nil, fromChainId)
+	if err != nil {
+		return 0, err
+	}
+	if len(fromProxy) == 0 {
+		return 0, unlockError{fmt.Errorf("proxy %s is not bound to chain %d", proxy.Hex(), fromChainId)}
+	}
+	managerProxy, err := lockProxy.ManagerProxyContract(nil)
+	if err != nil {
+		return 0, err
+	}
+	ccmp, err := eccmp_abi.NewIEthCrossChainManagerProxyCaller(managerProxy, s.backend())
+	if err != nil {
+		return 0, err
+	}
+	manager, err := ccmp.GetEthCrossChainManager(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	args, err := NFTUnlockArgs(toAsset, toAddress, tokenId, tokenUri)
+	if err != nil {
+		return 0, unlockError{err}
+	}
+	data, err := nftLockProxyABI.Pack("unlock", args, fromProxy, fromChainId)
+	if err != nil {
+		return 0, unlockError{err}
+	}
+	return s.EstimateGas(ethereum.CallMsg{From: manager, To: &proxy, Data: data})
+}
+
+// EstimateNFTUnlockGas fails over the nodes which can not be reached, the reverts of the unlock and the unlocks the
+// proxy can not do are returned as they are.
+func (pro *EthereumSdkPro) EstimateNFTUnlockGas(proxy, toAsset, toAddress common.Address, tokenId *big.Int, tokenUri string, fromChainId uint64) (gas uint64, err error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return 0, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		gas, err = info.sdk.EstimateNFTUnlockGas(proxy, toAsset, toAddress, tokenId, tokenUri, fromChainId)
+		if _, ok := err.(unlockError); err == nil || ok || contractError(err) {
+			return
+		}
+		info = pro.reset(info)
+	}
+	return
+}
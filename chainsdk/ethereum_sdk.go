@@ -30,8 +30,13 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	"math/big"
 	"poly-bridge/chainsdk/usdt_abi"
+	"poly-bridge/go_abi/lock_proxy_abi"
+	nftmapping "poly-bridge/go_abi/nft_mapping_abi"
 )
 
+// erc721InterfaceId is the ERC165 interface id of ERC721.
+var erc721InterfaceId = [4]byte{0x80, 0xac, 0x58, 0xcd}
+
 type EthereumSdk struct {
 	rpcClient *rpc.Client
 	rawClient *ethclient.Client
@@ -177,3 +182,20 @@ func (ec *EthereumSdk) Erc20Info(hash string) (string, string, int64, string, er
 	}
 	return hash, name, decimal.Int64(), symbol, nil
 }
+
+// IsErc721 returns whether the contract of hash supports the ERC721 interface through ERC165.
+func (ec *EthereumSdk) IsErc721(hash string) (bool, error) {
+	contract, err := nftmapping.NewERC165Caller(common.HexToAddress(hash), ec.rawClient)
+	if err != nil {
+		return false, err
+	}
+	return contract.SupportsInterface(&bind.CallOpts{}, erc721InterfaceId)
+}
+
+func (ec *EthereumSdk) GetBoundAssetHash(lockProxy string, assetHash string, toChainId uint64) ([]byte, error) {
+	lockProxyContract, err := lock_proxy_abi.NewLockProxyCaller(common.HexToAddress(lockProxy), ec.rawClient)
+	if err != nil {
+		return nil, err
+	}
+	return lockProxyContract.AssetHashMap(&bind.CallOpts{}, common.HexToAddress(assetHash), toChainId)
+}
@@ -22,14 +22,17 @@ import (
 	"math"
 	"math/big"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/astaxie/beego/logs"
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type EthereumInfo struct {
@@ -303,6 +306,9 @@ func (pro *EthereumSdkPro) Erc20Info(hash string) (string, string, int64, string
 	for info != nil {
 		hash, name, decimal, symbol, err := info.sdk.Erc20Info(hash)
 		if err != nil {
+			if contractError(err) {
+				return "", "", 0, "", err
+			}
 			info.latestHeight = 0
 			info = pro.GetLatest()
 		} else {
@@ -312,6 +318,60 @@ func (pro *EthereumSdkPro) Erc20Info(hash string) (string, string, int64, string
 	return "", "", 0, "", fmt.Errorf("all node is not working")
 }
 
+// IsErc721 returns whether the contract of hash supports the ERC721 interface.
+func (pro *EthereumSdkPro) IsErc721(hash string) (bool, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return false, fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		ok, err := info.sdk.IsErc721(hash)
+		if err == nil || contractError(err) {
+			return ok, err
+		}
+		info = pro.reset(info)
+	}
+	return false, fmt.Errorf("all node is not working")
+}
+
+func (pro *EthereumSdkPro) GetBoundAssetHash(lockProxy string, assetHash string, toChainId uint64) ([]byte, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		bound, err := info.sdk.GetBoundAssetHash(lockProxy, assetHash, toChainId)
+		if err != nil {
+			if contractError(err) {
+				return nil, err
+			}
+			info = pro.reset(info)
+		} else {
+			return bound, nil
+		}
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
+func (pro *EthereumSdkPro) GetBoundNFTAsset(lockProxy common.Address, asset common.Address, toChainId uint64) (common.Address, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return EmptyAddress, fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		bound, err := info.sdk.GetBoundNFTAsset(lockProxy, asset, toChainId)
+		if err != nil {
+			if contractError(err) {
+				return EmptyAddress, err
+			}
+			info = pro.reset(info)
+		} else {
+			return bound, nil
+		}
+	}
+	return EmptyAddress, fmt.Errorf("all node is not working")
+}
+
 func (pro *EthereumSdkPro) WaitTransactionConfirm(hash common.Hash) bool {
 	num := 0
 	for num < 300 {
@@ -347,6 +407,44 @@ func (pro *EthereumSdkPro) NFTBalance(asset, owner common.Address) (balance *big
 	return
 }
 
+// Balance returns the native balance of owner if asset is the native token, or the erc20 balance.
+func (pro *EthereumSdkPro) Balance(asset, owner common.Address) (balance *big.Int, err error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		if asset == EmptyAddress {
+			balance, err = info.sdk.GetNativeBalance(owner)
+		} else {
+			balance, err = info.sdk.GetERC20Balance(asset, owner)
+		}
+		if err != nil {
+			info = pro.reset(info)
+		} else {
+			return
+		}
+	}
+	return
+}
+
+func (pro *EthereumSdkPro) ERC20TotalSupply(asset common.Address) (supply *big.Int, err error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		if supply, err = info.sdk.GetERC20TotalSupply(asset); err != nil {
+			info = pro.reset(info)
+		} else {
+			return
+		}
+	}
+	return
+}
+
 func (pro *EthereumSdkPro) GetNFTOwner(asset common.Address, tokenId *big.Int) (owner common.Address, err error) {
 	info := pro.GetLatest()
 	if info == nil {
@@ -472,6 +570,21 @@ func (pro *EthereumSdkPro) GetNFTURLs(asset common.Address, tokenIds []*big.Int)
 	return
 }
 
+// contractError returns whether err is raised by the contract called rather than by the node, such as a revert, a
+// call to an address without code or an output which can not be unpacked. The other nodes fail the same way, so
+// the calls return it without failing over.
+func contractError(err error) bool {
+	if err == bind.ErrNoCode || strings.HasPrefix(err.Error(), "abi:") {
+		return true
+	}
+	if _, ok := err.(rpc.Error); ok {
+		msg := strings.ToLower(err.Error())
+		return strings.Contains(msg, "revert") || strings.Contains(msg, "invalid opcode") ||
+			strings.Contains(msg, "gas required exceeds allowance")
+	}
+	return false
+}
+
 func (pro *EthereumSdkPro) reset(info *EthereumInfo) *EthereumInfo {
 	info.latestHeight = 0
 	return pro.GetLatest()
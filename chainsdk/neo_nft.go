@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package chainsdk
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
+)
+
+// The NFT contracts on NEO are read with the method names of the ERC721 tokens. The asset is the token hash as
+// it is stored in the token table and the owner is the user hash of basedef.Address2Hash.
+
+func (pro *NeoSdkPro) NFTBalance(asset, owner string) (*big.Int, error) {
+	ownerHash, err := neoUserHash(owner)
+	if err != nil {
+		return nil, err
+	}
+	stack, err := pro.invokeNFT(asset, "balanceOf", sc.ContractParameter{Type: sc.Hash160, Value: ownerHash.Bytes()})
+	if err != nil {
+		return nil, err
+	}
+	return neoStackInteger(stack)
+}
+
+func (pro *NeoSdkPro) NFTOwner(asset string, tokenId *big.Int) (string, error) {
+	stack, err := pro.invokeNFT(asset, "ownerOf", sc.ContractParameter{Type: sc.Integer, Value: *tokenId})
+	if err != nil {
+		return "", err
+	}
+	owner, _ := stack.Value.(string)
+	return owner, nil
+}
+
+func (pro *NeoSdkPro) NFTTokenUri(asset string, tokenId *big.Int) (string, error) {
+	stack, err := pro.invokeNFT(asset, "tokenURI", sc.ContractParameter{Type: sc.Integer, Value: *tokenId})
+	if err != nil {
+		return "", err
+	}
+	value, _ := stack.Value.(string)
+	return string(helper.HexToBytes(value)), nil
+}
+
+func (pro *NeoSdkPro) NFTTokenOfOwnerByIndex(asset, owner string, index int) (*big.Int, error) {
+	ownerHash, err := neoUserHash(owner)
+	if err != nil {
+		return nil, err
+	}
+	stack, err := pro.invokeNFT(asset, "tokenOfOwnerByIndex",
+		sc.ContractParameter{Type: sc.Hash160, Value: ownerHash.Bytes()},
+		sc.ContractParameter{Type: sc.Integer, Value: *big.NewInt(int64(index))})
+	if err != nil {
+		return nil, err
+	}
+	return neoStackInteger(stack)
+}
+
+// invokeNFT invokes the method of the NFT contract on the nodes in turn, a faulted invocation is an error of the
+// contract and is not retried on the other nodes.
+func (pro *NeoSdkPro) invokeNFT(asset, method string, params ...sc.ContractParameter) (*models.InvokeStack, error) {
+	assetHash, err := helper.UInt160FromString(asset)
+	if err != nil {
+		return nil, err
+	}
+	sb := sc.NewScriptBuilder()
+	sb.MakeInvocationScript(assetHash.Bytes(), method, params)
+	script := helper.BytesToHex(sb.ToArray())
+
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		res := info.sdk.client.InvokeScript(script, helper.ZeroScriptHashString)
+		if res.HasError() {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+			continue
+		}
+		if res.Result.State == "FAULT" {
+			return nil, fmt.Errorf("invoke %s of %s fault", method, asset)
+		}
+		if len(res.Result.Stack) == 0 {
+			return nil, fmt.Errorf("invoke %s of %s, no stack result", method, asset)
+		}
+		return &res.Result.Stack[0], nil
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
+func neoUserHash(owner string) (helper.UInt160, error) {
+	data, err := hex.DecodeString(owner)
+	if err != nil {
+		return helper.UInt160{}, err
+	}
+	return helper.UInt160FromBytes(data)
+}
+
+// neoStackInteger reads an integer result, which is a little endian byte array unless its type is Integer.
+func neoStackInteger(stack *models.InvokeStack) (*big.Int, error) {
+	value, ok := stack.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("stack type %s is not an integer", stack.Type)
+	}
+	if stack.Type == "Integer" {
+		if number, ok := new(big.Int).SetString(value, 10); ok {
+			return number, nil
+		}
+		return nil, fmt.Errorf("invalid integer %s", value)
+	}
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(helper.ReverseBytes(data)), nil
+}
@@ -77,6 +77,17 @@ func (sdk *NeoSdk) SendRawTransaction(txHex string) (bool, error) {
 	return res.Result, nil
 }
 
+func (sdk *NeoSdk) InvokeScript(script string) (*models.InvokeResult, error) {
+	res := sdk.client.InvokeScript(script, "")
+	if res.HasError() {
+		return nil, fmt.Errorf("%s", res.ErrorResponse.Error.Message)
+	}
+	if res.Result.State == "FAULT" {
+		return nil, fmt.Errorf("invoke script fault")
+	}
+	return &res.Result, nil
+}
+
 func (sdk *NeoSdk) Nep5Info(hash string) (string, string, int64, error) {
 	scriptHash, err := helper.UInt160FromString(hash)
 	if err != nil {
@@ -167,6 +167,23 @@ func (pro *NeoSdkPro) Nep5Info(hash string) (string, string, int64, error) {
 	return "", "", 0, fmt.Errorf("all node is not working")
 }
 
+func (pro *NeoSdkPro) InvokeScript(script string) (*models.InvokeResult, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		result, err := info.sdk.InvokeScript(script)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
 func (pro *NeoSdkPro) GetTransactionHeight(hash string) (uint64, error) {
 	info := pro.GetLatest()
 	if info == nil {
@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package chainsdk
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	sdkcom "github.com/ontio/ontology-go-sdk/common"
+	ontcommon "github.com/ontio/ontology/common"
+)
+
+// The NFT contracts on Ontology are read with the method names of the ERC721 tokens. The asset is the token hash
+// as it is stored in the token table and the owner is the user hash of basedef.Address2Hash.
+
+func (pro *OntologySdkPro) NFTBalance(asset, owner string) (*big.Int, error) {
+	ownerAddr, err := ontUserAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	result, err := pro.invokeNFT(asset, "balanceOf", ownerAddr)
+	if err != nil {
+		return nil, err
+	}
+	return result.ToInteger()
+}
+
+func (pro *OntologySdkPro) NFTOwner(asset string, tokenId *big.Int) (string, error) {
+	result, err := pro.invokeNFT(asset, "ownerOf", tokenId)
+	if err != nil {
+		return "", err
+	}
+	owner, err := result.ToByteArray()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(owner), nil
+}
+
+func (pro *OntologySdkPro) NFTTokenUri(asset string, tokenId *big.Int) (string, error) {
+	result, err := pro.invokeNFT(asset, "tokenURI", tokenId)
+	if err != nil {
+		return "", err
+	}
+	return result.ToString()
+}
+
+func (pro *OntologySdkPro) NFTTokenOfOwnerByIndex(asset, owner string, index int) (*big.Int, error) {
+	ownerAddr, err := ontUserAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	result, err := pro.invokeNFT(asset, "tokenOfOwnerByIndex", ownerAddr, big.NewInt(int64(index)))
+	if err != nil {
+		return nil, err
+	}
+	return result.ToInteger()
+}
+
+// invokeNFT pre-executes the method of the NFT contract on the nodes in turn, a failed execution is an error of
+// the contract and is not retried on the other nodes.
+func (pro *OntologySdkPro) invokeNFT(asset, method string, args ...interface{}) (*sdkcom.ResultItem, error) {
+	contract, err := ontcommon.AddressFromHexString(asset)
+	if err != nil {
+		return nil, err
+	}
+	params := []interface{}{method, args}
+
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		res, err := info.sdk.NeoVM.PreExecInvokeNeoVMContract(contract, params)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+			continue
+		}
+		if res.State == 0 || res.Result == nil {
+			return nil, fmt.Errorf("invoke %s of %s failed", method, asset)
+		}
+		return res.Result, nil
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
+func ontUserAddress(owner string) (ontcommon.Address, error) {
+	data, err := hex.DecodeString(owner)
+	if err != nil {
+		return ontcommon.ADDRESS_EMPTY, err
+	}
+	return ontcommon.AddressParseFromBytes(data)
+}
@@ -18,18 +18,23 @@
 package chainsdk
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/astaxie/beego/logs"
 	"github.com/ontio/ontology-go-sdk"
 	"github.com/ontio/ontology-go-sdk/common"
 	"github.com/ontio/ontology/core/types"
+	"io/ioutil"
+	"net/http"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 )
 
 type OntologyInfo struct {
 	sdk          *ontology_go_sdk.OntologySdk
+	url          string
 	latestHeight uint64
 }
 
@@ -38,10 +43,50 @@ func NewOntologyInfo(url string) *OntologyInfo {
 	sdk.NewRpcClient().SetAddress(url)
 	return &OntologyInfo{
 		sdk:          sdk,
+		url:          url,
 		latestHeight: 0,
 	}
 }
 
+type OntologyGasPrice struct {
+	Error  int64
+	Desc   string
+	Result *struct {
+		GasPrice uint64 `json:"gasprice"`
+		Height   uint64 `json:"height"`
+	}
+}
+
+func (info *OntologyInfo) GetGasPrice() (uint64, error) {
+	requestJson := `{"jsonrpc": "2.0", "method": "getgasprice", "params": [], "id": 1}`
+	req, err := http.NewRequest("POST", info.url, strings.NewReader(requestJson))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accepts", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("response status code: %d", resp.StatusCode)
+	}
+	respBody, _ := ioutil.ReadAll(resp.Body)
+	gasPrice := new(OntologyGasPrice)
+	err = json.Unmarshal(respBody, gasPrice)
+	if err != nil {
+		return 0, err
+	}
+	if gasPrice.Error != 0 || gasPrice.Result == nil {
+		return 0, fmt.Errorf("get gas price err: %d, %s", gasPrice.Error, gasPrice.Desc)
+	}
+	return gasPrice.Result.GasPrice, nil
+}
+
 type OntologySdkPro struct {
 	infos         map[string]*OntologyInfo
 	selectionSlot uint64
@@ -134,6 +179,23 @@ func (pro *OntologySdkPro) GetSmartContractEventByBlock(height uint32) ([]*commo
 	return info.sdk.GetSmartContractEventByBlock(height)
 }
 
+func (pro *OntologySdkPro) GetGasPrice() (uint64, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return 0, fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		gasPrice, err := info.GetGasPrice()
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return gasPrice, nil
+		}
+	}
+	return 0, fmt.Errorf("all node is not working")
+}
+
 func (pro *OntologySdkPro) GetSdk() (*ontology_go_sdk.OntologySdk, error) {
 	info := pro.GetLatest()
 	if info == nil {
@@ -86,20 +86,48 @@ func (client *SwitcheoSDK) TxSearch(query string, prove bool, page, perPage int,
 }
 
 func (client *SwitcheoSDK) GetGas(tx []byte) uint64 {
-	decoder := auth.DefaultTxDecoder(client.cdc)
-	stdTx, err := decoder(tx)
+	gas, err := client.GetGasPrice(tx)
 	if err != nil {
 		logs.Error("cosmos client GetGas err: %s", err.Error())
 		return 0
 	}
+	return gas
+}
+
+// GetGasPrice returns the swth gas price paid by tx, it is 0 when tx is not a std tx or pays no swth.
+func (client *SwitcheoSDK) GetGasPrice(tx []byte) (uint64, error) {
+	decoder := auth.DefaultTxDecoder(client.cdc)
+	stdTx, err := decoder(tx)
+	if err != nil {
+		return 0, err
+	}
 	aa, ok := stdTx.(authtypes.StdTx)
 	if !ok {
-		logs.Error("This is not cosmos std tx!")
-		return 0
+		logs.Debug("This is not cosmos std tx!")
+		return 0, nil
+	}
+	if aa.Fee.Gas == 0 {
+		return 0, nil
 	}
 	amount := aa.Fee.Amount.AmountOf("swth").BigInt()
 	gas := big.NewInt(int64(aa.Fee.Gas))
-	return amount.Div(amount, gas).Uint64()
+	return amount.Div(amount, gas).Uint64(), nil
+}
+
+func (client *SwitcheoSDK) Simulate(tx []byte) (uint64, error) {
+	res, err := client.client.ABCIQuery("/app/simulate", tx)
+	if err != nil {
+		return 0, err
+	}
+	if !res.Response.IsOK() {
+		return 0, fmt.Errorf("simulate tx err: %s", res.Response.Log)
+	}
+	simRes := new(types.SimulationResponse)
+	err = codec.Cdc.UnmarshalBinaryBare(res.Response.Value, simRes)
+	if err != nil {
+		return 0, err
+	}
+	return simRes.GasUsed, nil
 }
 
 func (sdk *SwitcheoSDK) GetCurrentBlockHeight() (uint64, error) {
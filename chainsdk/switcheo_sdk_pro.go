@@ -135,4 +135,36 @@ func (pro *SwitcheoSdkPro) TxSearch(height uint64,query string, prove bool, page
 	}
 	return info.sdk.TxSearch(query,prove,page,perPage,orderBy)
 
-}
\ No newline at end of file
+}
+func (pro *SwitcheoSdkPro) Simulate(tx []byte) (uint64, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return 0, fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		gas, err := info.sdk.Simulate(tx)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return gas, nil
+		}
+	}
+	return 0, fmt.Errorf("all node is not working")
+}
+
+// GetGas returns the gas price paid by tx. A tx which can not be decoded, such as the orders and the other
+// messages of the switcheo modules whose codecs are not registered, pays no gas price. Decoding does not request
+// the node, so its errors do not fail over.
+func (pro *SwitcheoSdkPro) GetGas(tx []byte) (uint64, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return 0, fmt.Errorf("all node is not working")
+	}
+	gas, err := info.sdk.GetGasPrice(tx)
+	if err != nil {
+		logs.Debug("skip the gas price of switcheo tx: %v", err)
+		return 0, nil
+	}
+	return gas, nil
+}
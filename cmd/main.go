@@ -29,6 +29,7 @@ import (
 	"poly-bridge/conf"
 	"poly-bridge/crosschaineffect"
 	"poly-bridge/crosschainlisten"
+	"poly-bridge/liquiditylisten"
 	"runtime"
 	"strings"
 	"syscall"
@@ -114,6 +115,7 @@ func startServer(ctx *cli.Context) {
 	coinpricelisten.StartCoinPriceListen(config.Server, config.CoinPriceUpdateSlot, config.CoinPriceListenConfig, config.DBConfig)
 	chainfeelisten.StartFeeListen(config.Server, config.FeeUpdateSlot, config.FeeListenConfig, config.DBConfig)
 	crosschaineffect.StartCrossChainEffect(config.Server, config.EventEffectConfig, config.DBConfig)
+	liquiditylisten.StartLiquidityListen(config.Server, config.LiquidityListenConfig, config.ChainListenConfig, config.DBConfig)
 }
 
 func waitSignal() os.Signal {
@@ -138,6 +140,7 @@ func stopServer() {
 	coinpricelisten.StopCoinPriceListen()
 	chainfeelisten.StopFeeListen()
 	crosschaineffect.StopCrossChainEffect()
+	liquiditylisten.StopLiquidityListen()
 }
 
 func main() {
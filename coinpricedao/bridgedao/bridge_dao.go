@@ -55,6 +55,22 @@ func (dao *BridgeDao) SavePrices(tokens []*models.TokenBasic) error {
 		if res.Error != nil {
 			return res.Error
 		}
+		histories := make([]*models.TokenPriceHistory, 0)
+		for _, token := range tokens {
+			if token.Ind == 1 {
+				histories = append(histories, &models.TokenPriceHistory{
+					TokenBasicName: token.Name,
+					Price:          token.Price,
+					Time:           token.Time,
+				})
+			}
+		}
+		if len(histories) > 0 {
+			res = dao.db.Create(histories)
+			if res.Error != nil {
+				return res.Error
+			}
+		}
 	}
 	return nil
 }
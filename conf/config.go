@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"github.com/astaxie/beego/logs"
 	"poly-bridge/basedef"
+	"poly-bridge/models"
 )
 
 type DBConfig struct {
@@ -29,6 +30,17 @@ type DBConfig struct {
 	Password string
 	Scheme   string
 	Debug    bool
+	// ReadURLs are the read replicas of URL, read only queries are routed to them when present.
+	ReadURLs []string
+	// MaxOpenConns and MaxIdleConns size the connection pool of every instance, 0 means no limit.
+	MaxOpenConns int
+	MaxIdleConns int
+	// ConnMaxLifetime is the max lifetime of a pooled connection in seconds.
+	ConnMaxLifetime int64
+	// Timeout is the dial, read and write timeout in seconds.
+	Timeout int64
+	// MaxReplicaLag is the max replication lag in seconds before a replica is skipped.
+	MaxReplicaLag int64
 }
 
 type Restful struct {
@@ -37,18 +49,20 @@ type Restful struct {
 }
 
 type ChainListenConfig struct {
-	ChainName          string
-	ChainId            uint64
-	ListenSlot         uint64
-	Defer              uint64
-	Nodes              []*Restful
-	ExtendNodes        []*Restful
-	WrapperContract    string
-	CCMContract        string
-	ProxyContract      string
-	NFTWrapperContract string
-	NFTProxyContract   string
-	SwapContract       string
+	ChainName            string
+	ChainId              uint64
+	ListenSlot           uint64
+	Defer                uint64
+	Nodes                []*Restful
+	ExtendNodes          []*Restful
+	WrapperContract      string
+	CCMContract          string
+	ProxyContract        string
+	NFTWrapperContract   string
+	NFTProxyContract     string
+	NFT1155ProxyContract string // lock proxy of the ERC1155 tokens
+	SwapContract         string
+	AddressPrefix        string // bech32 prefix of the addresses on cosmos chains
 }
 
 func (cfg *ChainListenConfig) GetNodesUrl() []string {
@@ -104,13 +118,22 @@ func (cfg *CoinPriceListenConfig) GetNodesKey() []string {
 	return keys
 }
 
+type AssetGasLimit struct {
+	Hash     string
+	Standard uint8
+	GasLimit int64
+}
+
 type FeeListenConfig struct {
-	ChainId   uint64
-	ChainName string
-	Nodes     []*Restful
-	ProxyFee  int64
-	MinFee    int64
-	GasLimit  int64
+	ChainId        uint64
+	ChainName      string
+	Nodes          []*Restful
+	ProxyFee       int64
+	MinFee         int64
+	GasLimit       int64
+	NFTGasLimit    int64
+	AssetGasLimits []*AssetGasLimit
+	EstimateScript string // hex encoded unlock script (NEO) or tx (Switcheo) to estimate the gas with
 }
 
 func (cfg *FeeListenConfig) GetNodesUrl() []string {
@@ -129,11 +152,82 @@ func (cfg *FeeListenConfig) GetNodesKey() []string {
 	return keys
 }
 
+// GetStandardGasLimit returns the gas limit of unlocking a token of the given standard,
+// falling back to GasLimit when there is no dedicated setting.
+func (cfg *FeeListenConfig) GetStandardGasLimit(standard uint8) int64 {
+	if models.IsNFTStandard(standard) && cfg.NFTGasLimit > 0 {
+		return cfg.NFTGasLimit
+	}
+	return cfg.GasLimit
+}
+
+// EventEffectConfig keeps the price history at full resolution for PriceHistoryDays, 7 by default, and one price
+// an hour after it.
 type EventEffectConfig struct {
-	HowOld         int64
-	HowOld2        int64
-	ChainListening int64
-	EffectSlot     int64
+	HowOld           int64
+	HowOld2          int64
+	ChainListening   int64
+	EffectSlot       int64
+	PriceHistoryDays int64
+}
+
+type LiquidityToken struct {
+	ChainId uint64
+	Hash    string
+}
+
+// LiquidityListenConfig records the lock proxy balances every UpdateSlot minutes, the total supply is recorded
+// for the wrapped tokens which are minted and burned by the lock proxy. The records are kept for HistoryDays,
+// 30 by default.
+type LiquidityListenConfig struct {
+	UpdateSlot     int64
+	HistoryDays    int64
+	MintableTokens []*LiquidityToken
+}
+
+// NFTIndexerConfig indexes the NFT profiles of the locked and unlocked tokens every IndexSlot seconds,
+// and fetches the profiles older than RefreshSlot seconds again, BatchSize at a time.
+type NFTIndexerConfig struct {
+	IndexSlot   int64
+	RefreshSlot int64
+	BatchSize   int
+}
+
+// NFTMediaConfig caches the NFT images in Dir and serves them with their thumbnails from BaseUrl, which is the
+// public url of the media route, instead of the image hosts of the NFTs. Images larger than MaxSize bytes are refused.
+type NFTMediaConfig struct {
+	Dir           string
+	BaseUrl       string
+	ThumbnailSize int
+	MaxSize       int64
+}
+
+// NFTStatsConfig computes the statistics of the NFT collections every StatsSlot seconds.
+type NFTStatsConfig struct {
+	StatsSlot int64
+}
+
+// NFTCacheConfig is the freshness of the NFT home page and item caches in seconds. The cached items of the
+// tokens locked or unlocked are invalidated every InvalidateSlot seconds, and a stale home page is served while
+// it is refreshed in the background if StaleWhileRevalidate is set.
+type NFTCacheConfig struct {
+	HomeTTL              int64
+	ItemTTL              int64
+	InvalidateSlot       int64
+	StaleWhileRevalidate bool
+}
+
+// NFTAssetsConfig reloads the NFT assets and fee tokens from the token tables every ReloadSlot seconds.
+type NFTAssetsConfig struct {
+	ReloadSlot int64
+}
+
+// NFTFeeConfig estimates the gas of unlocking the tokens of each NFT collection by simulating the unlock of the
+// lock proxy, the estimates are cached for EstimateTTL seconds. VerifyGasLimit is the gas of the cross chain
+// manager verifying the header before the proxy unlocks.
+type NFTFeeConfig struct {
+	EstimateTTL    int64
+	VerifyGasLimit int64
 }
 
 type Config struct {
@@ -145,6 +239,13 @@ type Config struct {
 	FeeUpdateSlot         int64
 	FeeListenConfig       []*FeeListenConfig
 	EventEffectConfig     *EventEffectConfig
+	LiquidityListenConfig *LiquidityListenConfig
+	NFTIndexerConfig      *NFTIndexerConfig
+	NFTMediaConfig        *NFTMediaConfig
+	NFTStatsConfig        *NFTStatsConfig
+	NFTCacheConfig        *NFTCacheConfig
+	NFTAssetsConfig       *NFTAssetsConfig
+	NFTFeeConfig          *NFTFeeConfig
 	DBConfig              *DBConfig
 }
 
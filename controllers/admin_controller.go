@@ -0,0 +1,934 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/logs"
+	"gorm.io/gorm"
+	"poly-bridge/adminauth"
+	"poly-bridge/models"
+	"strings"
+	"time"
+)
+
+const (
+	auditTableTokenBasic  = "tokenbasic"
+	auditTableToken       = "token"
+	auditTableTokenMap    = "tokenmap"
+	auditTablePriceMarket = "pricemarket"
+	auditTableChainFee    = "chainfee"
+	auditTableApiKey      = "apikey"
+	auditTableFeePolicy   = "feepolicy"
+)
+
+// AdminController manages the tokens, token maps, price markets and chain fees, every change is audited.
+type AdminController struct {
+	beego.Controller
+	operator string
+	changed  bool
+}
+
+func (c *AdminController) Prepare() {
+	operator, ok := adminauth.Operator(beego.AppConfig.String("adminkeys"), c.Ctx.Input.Header("X-Api-Key"))
+	if !ok {
+		c.Data["json"] = models.MakeErrorRsp("api key is invalid!")
+		c.Ctx.ResponseWriter.WriteHeader(401)
+		c.ServeJSON()
+		c.StopRun()
+	}
+	c.operator = operator
+}
+
+// Finish reloads the fee snapshot and drops the cached responses after the tables are changed.
+func (c *AdminController) Finish() {
+	if c.changed {
+		feeSnapshots.Reload()
+		invalidateResponseCache()
+	}
+}
+
+func (c *AdminController) input(req interface{}) bool {
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, req); err != nil {
+		c.fail(fmt.Errorf("request parameter is invalid!"))
+		return false
+	}
+	return true
+}
+
+func (c *AdminController) fail(err error) {
+	c.Data["json"] = models.MakeErrorRsp(err.Error())
+	c.Ctx.ResponseWriter.WriteHeader(400)
+	c.ServeJSON()
+}
+
+func (c *AdminController) output(data interface{}) {
+	c.Data["json"] = data
+	c.ServeJSON()
+}
+
+func (c *AdminController) audit(tx *gorm.DB, action string, table string, key string, before interface{}, after interface{}) error {
+	auditLog := &models.AuditLog{
+		Operator: c.operator,
+		Action:   action,
+		Table:    table,
+		Key:      key,
+		Time:     time.Now().Unix(),
+	}
+	if before != nil {
+		data, _ := json.Marshal(before)
+		auditLog.Before = string(data)
+	}
+	if after != nil {
+		data, _ := json.Marshal(after)
+		auditLog.After = string(data)
+	}
+	logs.Info("admin %s %s %s %s", c.operator, action, table, key)
+	c.changed = true
+	return tx.Create(auditLog).Error
+}
+
+func tokenAuditKey(chainId uint64, hash string) string {
+	return fmt.Sprintf("%d:%s", chainId, hash)
+}
+
+func tokenMapAuditKey(tokenMap *models.TokenMap) string {
+	return fmt.Sprintf("%d:%s->%d:%s", tokenMap.SrcChainId, tokenMap.SrcTokenHash, tokenMap.DstChainId, tokenMap.DstTokenHash)
+}
+
+func (c *AdminController) TokenBasics() {
+	tokenBasics := make([]*models.TokenBasic, 0)
+	db.Preload("Tokens").Preload("PriceMarkets").Find(&tokenBasics)
+	c.output(tokenBasics)
+}
+
+func (c *AdminController) AddTokenBasic() {
+	var req models.TokenBasic
+	if !c.input(&req) {
+		return
+	}
+	if err := validateTokenBasic(&req); err != nil {
+		c.fail(err)
+		return
+	}
+	tokenBasic := &models.TokenBasic{
+		Name:            req.Name,
+		Precision:       req.Precision,
+		Property:        req.Property,
+		Standard:        req.Standard,
+		Meta:            req.Meta,
+		MetaFetcherType: req.MetaFetcherType,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Where("name = ?", tokenBasic.Name).Find(&models.TokenBasic{}); res.RowsAffected > 0 {
+			return fmt.Errorf("token basic %s exists", tokenBasic.Name)
+		}
+		if err := tx.Create(tokenBasic).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionAdd, auditTableTokenBasic, tokenBasic.Name, nil, tokenBasic)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(tokenBasic)
+}
+
+func (c *AdminController) UpdateTokenBasic() {
+	var req models.TokenBasic
+	if !c.input(&req) {
+		return
+	}
+	if err := validateTokenBasic(&req); err != nil {
+		c.fail(err)
+		return
+	}
+	tokenBasic := new(models.TokenBasic)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Where("name = ?", req.Name).First(tokenBasic); res.RowsAffected == 0 {
+			return fmt.Errorf("token basic %s does not exist", req.Name)
+		}
+		before := *tokenBasic
+		err := tx.Model(tokenBasic).Updates(map[string]interface{}{
+			"precision":         req.Precision,
+			"property":          req.Property,
+			"standard":          req.Standard,
+			"meta":              req.Meta,
+			"meta_fetcher_type": req.MetaFetcherType,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionUpdate, auditTableTokenBasic, tokenBasic.Name, &before, tokenBasic)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(tokenBasic)
+}
+
+func (c *AdminController) RemoveTokenBasic() {
+	var req models.AdminTokenBasicReq
+	if !c.input(&req) {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		tokenBasic := new(models.TokenBasic)
+		if res := tx.Where("name = ?", req.Name).Preload("PriceMarkets").First(tokenBasic); res.RowsAffected == 0 {
+			return fmt.Errorf("token basic %s does not exist", req.Name)
+		}
+		if res := tx.Where("token_basic_name = ?", req.Name).Find(&[]*models.Token{}); res.RowsAffected > 0 {
+			return fmt.Errorf("token basic %s still has tokens, disable it instead", req.Name)
+		}
+		if res := tx.Where("token_basic_name = ?", req.Name).Find(&[]*models.ChainFee{}); res.RowsAffected > 0 {
+			return fmt.Errorf("token basic %s is still used by chain fees", req.Name)
+		}
+		for _, priceMarket := range tokenBasic.PriceMarkets {
+			if err := tx.Delete(priceMarket).Error; err != nil {
+				return err
+			}
+			if err := c.audit(tx, models.AuditActionRemove, auditTablePriceMarket, priceMarket.TokenBasicName+"/"+priceMarket.MarketName, priceMarket, nil); err != nil {
+				return err
+			}
+		}
+		tokenBasic.PriceMarkets = nil
+		if err := tx.Delete(tokenBasic).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionRemove, auditTableTokenBasic, tokenBasic.Name, tokenBasic, nil)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(req)
+}
+
+func (c *AdminController) AddToken() {
+	var req models.Token
+	if !c.input(&req) {
+		return
+	}
+	token := &models.Token{
+		Hash:           strings.ToLower(req.Hash),
+		ChainId:        req.ChainId,
+		Name:           req.Name,
+		Precision:      req.Precision,
+		TokenBasicName: req.TokenBasicName,
+		Property:       req.Property,
+		Standard:       req.Standard,
+	}
+	if err := validateToken(token); err != nil {
+		c.fail(err)
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Where("chain_id = ? and hash = ?", token.ChainId, token.Hash).Find(&models.Token{}); res.RowsAffected > 0 {
+			return fmt.Errorf("token %s exists on chain %d", token.Hash, token.ChainId)
+		}
+		if err := tx.Create(token).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionAdd, auditTableToken, tokenAuditKey(token.ChainId, token.Hash), nil, token)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(token)
+}
+
+func (c *AdminController) UpdateToken() {
+	var req models.Token
+	if !c.input(&req) {
+		return
+	}
+	req.Hash = strings.ToLower(req.Hash)
+	token := new(models.Token)
+	if res := db.Where("chain_id = ? and hash = ?", req.ChainId, req.Hash).First(token); res.RowsAffected == 0 {
+		c.fail(fmt.Errorf("token %s does not exist on chain %d", req.Hash, req.ChainId))
+		return
+	}
+	if err := validateToken(&req); err != nil {
+		c.fail(err)
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		before := *token
+		err := tx.Model(token).Updates(map[string]interface{}{
+			"name":             req.Name,
+			"precision":        req.Precision,
+			"token_basic_name": req.TokenBasicName,
+			"property":         req.Property,
+			"standard":         req.Standard,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionUpdate, auditTableToken, tokenAuditKey(token.ChainId, token.Hash), &before, token)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(token)
+}
+
+func (c *AdminController) RemoveToken() {
+	var req models.AdminTokenReq
+	if !c.input(&req) {
+		return
+	}
+	req.Hash = strings.ToLower(req.Hash)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		token := new(models.Token)
+		if res := tx.Where("chain_id = ? and hash = ?", req.ChainId, req.Hash).First(token); res.RowsAffected == 0 {
+			return fmt.Errorf("token %s does not exist on chain %d", req.Hash, req.ChainId)
+		}
+		tokenMaps := make([]*models.TokenMap, 0)
+		tx.Where("(src_chain_id = ? and src_token_hash = ?) or (dst_chain_id = ? and dst_token_hash = ?)",
+			req.ChainId, req.Hash, req.ChainId, req.Hash).Find(&tokenMaps)
+		for _, tokenMap := range tokenMaps {
+			if err := tx.Delete(tokenMap).Error; err != nil {
+				return err
+			}
+			if err := c.audit(tx, models.AuditActionRemove, auditTableTokenMap, tokenMapAuditKey(tokenMap), tokenMap, nil); err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(token).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionRemove, auditTableToken, tokenAuditKey(token.ChainId, token.Hash), token, nil)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(req)
+}
+
+func (c *AdminController) TokenMaps() {
+	tokenMaps := make([]*models.TokenMap, 0)
+	db.Find(&tokenMaps)
+	c.output(tokenMaps)
+}
+
+func (c *AdminController) AddTokenMaps() {
+	var req models.AdminTokenMapsReq
+	if !c.input(&req) {
+		return
+	}
+	tokenMaps := make([]*models.TokenMap, 0)
+	for _, tokenMap := range req.TokenMaps {
+		tokenMaps = append(tokenMaps, &models.TokenMap{
+			SrcChainId:   tokenMap.SrcChainId,
+			SrcTokenHash: strings.ToLower(tokenMap.SrcTokenHash),
+			DstChainId:   tokenMap.DstChainId,
+			DstTokenHash: strings.ToLower(tokenMap.DstTokenHash),
+			Standard:     tokenMap.Standard,
+			Property:     tokenMap.Property,
+		})
+	}
+	if len(tokenMaps) == 0 {
+		c.fail(fmt.Errorf("no token map"))
+		return
+	}
+	if err := validateTokenMaps(tokenMaps); err != nil {
+		c.fail(err)
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, tokenMap := range tokenMaps {
+			res := tx.Where("src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and dst_token_hash = ?",
+				tokenMap.SrcChainId, tokenMap.SrcTokenHash, tokenMap.DstChainId, tokenMap.DstTokenHash).Find(&models.TokenMap{})
+			if res.RowsAffected > 0 {
+				return fmt.Errorf("token map %s exists", tokenMapAuditKey(tokenMap))
+			}
+			if err := tx.Create(tokenMap).Error; err != nil {
+				return err
+			}
+			if err := c.audit(tx, models.AuditActionAdd, auditTableTokenMap, tokenMapAuditKey(tokenMap), nil, tokenMap); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(tokenMaps)
+}
+
+func (c *AdminController) UpdateTokenMap() {
+	var req models.TokenMap
+	if !c.input(&req) {
+		return
+	}
+	tokenMap := new(models.TokenMap)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and dst_token_hash = ?",
+			req.SrcChainId, strings.ToLower(req.SrcTokenHash), req.DstChainId, strings.ToLower(req.DstTokenHash)).First(tokenMap)
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("token map %s does not exist", tokenMapAuditKey(&req))
+		}
+		before := *tokenMap
+		err := tx.Model(tokenMap).Updates(map[string]interface{}{
+			"standard": req.Standard,
+			"property": req.Property,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionUpdate, auditTableTokenMap, tokenMapAuditKey(tokenMap), &before, tokenMap)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(tokenMap)
+}
+
+// RemoveTokenMap removes the token map together with its reverse.
+func (c *AdminController) RemoveTokenMap() {
+	var req models.AdminTokenMapReq
+	if !c.input(&req) {
+		return
+	}
+	srcTokenHash, dstTokenHash := strings.ToLower(req.SrcTokenHash), strings.ToLower(req.DstTokenHash)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		tokenMaps := make([]*models.TokenMap, 0)
+		tx.Where("(src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and dst_token_hash = ?) or (src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and dst_token_hash = ?)",
+			req.SrcChainId, srcTokenHash, req.DstChainId, dstTokenHash, req.DstChainId, dstTokenHash, req.SrcChainId, srcTokenHash).Find(&tokenMaps)
+		if len(tokenMaps) == 0 {
+			return fmt.Errorf("token map %d:%s->%d:%s does not exist", req.SrcChainId, srcTokenHash, req.DstChainId, dstTokenHash)
+		}
+		for _, tokenMap := range tokenMaps {
+			if err := tx.Delete(tokenMap).Error; err != nil {
+				return err
+			}
+			if err := c.audit(tx, models.AuditActionRemove, auditTableTokenMap, tokenMapAuditKey(tokenMap), tokenMap, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(req)
+}
+
+func (c *AdminController) AddPriceMarket() {
+	var req models.PriceMarket
+	if !c.input(&req) {
+		return
+	}
+	if req.MarketName == "" || req.Name == "" {
+		c.fail(fmt.Errorf("market name or name is empty"))
+		return
+	}
+	priceMarket := &models.PriceMarket{
+		TokenBasicName: req.TokenBasicName,
+		MarketName:     req.MarketName,
+		Name:           req.Name,
+	}
+	key := priceMarket.TokenBasicName + "/" + priceMarket.MarketName
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Where("name = ?", priceMarket.TokenBasicName).Find(&models.TokenBasic{}); res.RowsAffected == 0 {
+			return fmt.Errorf("token basic %s does not exist", priceMarket.TokenBasicName)
+		}
+		if res := tx.Where("token_basic_name = ? and market_name = ?", priceMarket.TokenBasicName, priceMarket.MarketName).Find(&models.PriceMarket{}); res.RowsAffected > 0 {
+			return fmt.Errorf("price market %s exists", key)
+		}
+		if err := tx.Create(priceMarket).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionAdd, auditTablePriceMarket, key, nil, priceMarket)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(priceMarket)
+}
+
+func (c *AdminController) UpdatePriceMarket() {
+	var req models.PriceMarket
+	if !c.input(&req) {
+		return
+	}
+	if req.Name == "" {
+		c.fail(fmt.Errorf("name is empty"))
+		return
+	}
+	priceMarket := new(models.PriceMarket)
+	key := req.TokenBasicName + "/" + req.MarketName
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Where("token_basic_name = ? and market_name = ?", req.TokenBasicName, req.MarketName).First(priceMarket); res.RowsAffected == 0 {
+			return fmt.Errorf("price market %s does not exist", key)
+		}
+		before := *priceMarket
+		if err := tx.Model(priceMarket).Update("name", req.Name).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionUpdate, auditTablePriceMarket, key, &before, priceMarket)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(priceMarket)
+}
+
+func (c *AdminController) RemovePriceMarket() {
+	var req models.AdminPriceMarketReq
+	if !c.input(&req) {
+		return
+	}
+	key := req.TokenBasicName + "/" + req.MarketName
+	err := db.Transaction(func(tx *gorm.DB) error {
+		priceMarket := new(models.PriceMarket)
+		if res := tx.Where("token_basic_name = ? and market_name = ?", req.TokenBasicName, req.MarketName).First(priceMarket); res.RowsAffected == 0 {
+			return fmt.Errorf("price market %s does not exist", key)
+		}
+		if err := tx.Delete(priceMarket).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionRemove, auditTablePriceMarket, key, priceMarket, nil)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(req)
+}
+
+func (c *AdminController) ChainFees() {
+	chainFees := make([]*models.ChainFee, 0)
+	db.Preload("AssetFees").Find(&chainFees)
+	c.output(chainFees)
+}
+
+func (c *AdminController) AddChainFee() {
+	var req models.ChainFee
+	if !c.input(&req) {
+		return
+	}
+	chainFee := &models.ChainFee{
+		ChainId:        req.ChainId,
+		TokenBasicName: req.TokenBasicName,
+		MaxFee:         models.NewBigIntFromInt(0),
+		MinFee:         models.NewBigIntFromInt(0),
+		ProxyFee:       models.NewBigIntFromInt(0),
+	}
+	key := fmt.Sprintf("%d", chainFee.ChainId)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Where("name = ?", chainFee.TokenBasicName).Find(&models.TokenBasic{}); res.RowsAffected == 0 {
+			return fmt.Errorf("token basic %s does not exist", chainFee.TokenBasicName)
+		}
+		if res := tx.Where("chain_id = ?", chainFee.ChainId).Find(&models.ChainFee{}); res.RowsAffected > 0 {
+			return fmt.Errorf("chain fee of chain %d exists", chainFee.ChainId)
+		}
+		if err := tx.Create(chainFee).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionAdd, auditTableChainFee, key, nil, chainFee)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(chainFee)
+}
+
+func (c *AdminController) UpdateChainFee() {
+	var req models.ChainFee
+	if !c.input(&req) {
+		return
+	}
+	chainFee := new(models.ChainFee)
+	key := fmt.Sprintf("%d", req.ChainId)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Where("name = ?", req.TokenBasicName).Find(&models.TokenBasic{}); res.RowsAffected == 0 {
+			return fmt.Errorf("token basic %s does not exist", req.TokenBasicName)
+		}
+		if res := tx.Where("chain_id = ?", req.ChainId).First(chainFee); res.RowsAffected == 0 {
+			return fmt.Errorf("chain fee of chain %d does not exist", req.ChainId)
+		}
+		before := *chainFee
+		if err := tx.Model(chainFee).Update("token_basic_name", req.TokenBasicName).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionUpdate, auditTableChainFee, key, &before, chainFee)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(chainFee)
+}
+
+func (c *AdminController) RemoveChainFee() {
+	var req models.AdminChainFeeReq
+	if !c.input(&req) {
+		return
+	}
+	key := fmt.Sprintf("%d", req.ChainId)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		chainFee := new(models.ChainFee)
+		if res := tx.Where("chain_id = ?", req.ChainId).Preload("AssetFees").First(chainFee); res.RowsAffected == 0 {
+			return fmt.Errorf("chain fee of chain %d does not exist", req.ChainId)
+		}
+		if err := tx.Where("chain_id = ?", req.ChainId).Delete(&models.AssetFee{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(chainFee).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionRemove, auditTableChainFee, key, chainFee, nil)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(req)
+}
+
+func (c *AdminController) FeePolicies() {
+	feePolicies := make([]*models.FeePolicy, 0)
+	db.Order("name asc, version desc").Find(&feePolicies)
+	c.output(feePolicies)
+}
+
+// AddFeePolicy saves the policy as the next version of its name, the previous versions are kept.
+func (c *AdminController) AddFeePolicy() {
+	var req models.FeePolicy
+	if !c.input(&req) {
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.fail(err)
+		return
+	}
+	feePolicy := req
+	feePolicy.Id = 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		latest := new(models.FeePolicy)
+		if res := tx.Where("name = ?", feePolicy.Name).Order("version desc").First(latest); res.RowsAffected > 0 {
+			feePolicy.Version = latest.Version + 1
+		} else {
+			feePolicy.Version = 1
+		}
+		if err := tx.Create(&feePolicy).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionAdd, auditTableFeePolicy, fmt.Sprintf("%s:%d", feePolicy.Name, feePolicy.Version), nil, &feePolicy)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(&feePolicy)
+}
+
+// SetProperty enables (1) or disables (0) a token basic, a token, or a token map together with its reverse.
+func (c *AdminController) SetProperty() {
+	var req models.AdminPropertyReq
+	if !c.input(&req) {
+		return
+	}
+	if req.Property != 0 && req.Property != 1 {
+		c.fail(fmt.Errorf("property %d is invalid", req.Property))
+		return
+	}
+	req.Hash, req.DstTokenHash = strings.ToLower(req.Hash), strings.ToLower(req.DstTokenHash)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		switch req.Table {
+		case auditTableTokenBasic:
+			tokenBasic := new(models.TokenBasic)
+			if res := tx.Where("name = ?", req.Name).First(tokenBasic); res.RowsAffected == 0 {
+				return fmt.Errorf("token basic %s does not exist", req.Name)
+			}
+			before := *tokenBasic
+			if err := tx.Model(tokenBasic).Update("property", req.Property).Error; err != nil {
+				return err
+			}
+			return c.audit(tx, models.AuditActionProperty, req.Table, tokenBasic.Name, &before, tokenBasic)
+		case auditTableToken:
+			token := new(models.Token)
+			if res := tx.Where("chain_id = ? and hash = ?", req.ChainId, req.Hash).First(token); res.RowsAffected == 0 {
+				return fmt.Errorf("token %s does not exist on chain %d", req.Hash, req.ChainId)
+			}
+			before := *token
+			if err := tx.Model(token).Update("property", req.Property).Error; err != nil {
+				return err
+			}
+			return c.audit(tx, models.AuditActionProperty, req.Table, tokenAuditKey(token.ChainId, token.Hash), &before, token)
+		case auditTableTokenMap:
+			tokenMaps := make([]*models.TokenMap, 0)
+			tx.Where("(src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and dst_token_hash = ?) or (src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and dst_token_hash = ?)",
+				req.ChainId, req.Hash, req.DstChainId, req.DstTokenHash, req.DstChainId, req.DstTokenHash, req.ChainId, req.Hash).Find(&tokenMaps)
+			if len(tokenMaps) == 0 {
+				return fmt.Errorf("token map %d:%s->%d:%s does not exist", req.ChainId, req.Hash, req.DstChainId, req.DstTokenHash)
+			}
+			for _, tokenMap := range tokenMaps {
+				before := *tokenMap
+				if err := tx.Model(tokenMap).Update("property", req.Property).Error; err != nil {
+					return err
+				}
+				if err := c.audit(tx, models.AuditActionProperty, req.Table, tokenMapAuditKey(tokenMap), &before, tokenMap); err != nil {
+					return err
+				}
+			}
+			return nil
+		default:
+			return fmt.Errorf("table %s does not have property", req.Table)
+		}
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	c.output(req)
+}
+
+func (c *AdminController) AuditLogs() {
+	var req models.AuditLogsReq
+	if !c.input(&req) {
+		return
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	query := db.Model(&models.AuditLog{})
+	if req.Table != "" {
+		query = query.Where("`table` = ?", req.Table)
+	}
+	if req.Key != "" {
+		query = query.Where("`key` = ?", req.Key)
+	}
+	var auditLogNum int64
+	query.Count(&auditLogNum)
+	auditLogs := make([]*models.AuditLog, 0)
+	query.Limit(req.PageSize).Offset(req.PageSize * req.PageNo).Order("id desc").Find(&auditLogs)
+	c.output(models.MakeAuditLogsRsp(req.PageSize, req.PageNo, (int(auditLogNum)+req.PageSize-1)/req.PageSize, int(auditLogNum), auditLogs))
+}
+
+// FeeReconciliation aggregates the fees paid against the fees spent on the destination chains.
+func (c *AdminController) FeeReconciliation() {
+	var feeReconciliationReq models.FeeReconciliationReq
+	if !c.input(&feeReconciliationReq) {
+		return
+	}
+	if feeReconciliationReq.Period == "" {
+		feeReconciliationReq.Period = models.ReconciliationPeriodDay
+	}
+	if feeReconciliationReq.Period != models.ReconciliationPeriodDay && feeReconciliationReq.Period != models.ReconciliationPeriodWeek {
+		c.fail(fmt.Errorf("period: %s is not supported", feeReconciliationReq.Period))
+		return
+	}
+	if feeReconciliationReq.EndTime == 0 {
+		feeReconciliationReq.EndTime = uint64(time.Now().Unix())
+	}
+	if feeReconciliationReq.StartTime == 0 {
+		feeReconciliationReq.StartTime = feeReconciliationReq.EndTime - 30*24*60*60
+	}
+	reconciliations := make([]*models.FeeReconciliation, 0)
+	query := readDB().Where("ind = 1 and time >= ? and time < ?", feeReconciliationReq.StartTime, feeReconciliationReq.EndTime)
+	if feeReconciliationReq.SrcChainId != 0 {
+		query = query.Where("src_chain_id = ?", feeReconciliationReq.SrcChainId)
+	}
+	if feeReconciliationReq.DstChainId != 0 {
+		query = query.Where("dst_chain_id = ?", feeReconciliationReq.DstChainId)
+	}
+	query.Find(&reconciliations)
+	stats := models.AggregateFeeReconciliations(reconciliations, feeReconciliationReq.Period)
+	c.output(models.MakeFeeReconciliationRsp(feeReconciliationReq.Period, feeReconciliationReq.StartTime, feeReconciliationReq.EndTime, stats))
+}
+
+// VerifyTokenMaps checks the enabled token maps against the lock proxies now and saves the results.
+func (c *AdminController) VerifyTokenMaps() {
+	adminSdkOnce.Do(initAdminSdks)
+	if tokenMapVerifier == nil {
+		c.fail(fmt.Errorf("bridgeconfig is not set"))
+		return
+	}
+	verifications, err := tokenMapVerifier.VerifyAndSave(db)
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	mismatches := make([]*models.TokenMapVerification, 0)
+	for _, verification := range verifications {
+		if verification.Status == models.TokenMapVerifyMismatch || verification.Status == models.TokenMapVerifyFailed {
+			mismatches = append(mismatches, verification)
+		}
+	}
+	c.output(models.MakeTokenMapVerificationsRsp(len(mismatches), 0, 1, len(mismatches), mismatches))
+}
+
+func (c *AdminController) TokenMapVerifications() {
+	var req models.TokenMapVerificationsReq
+	if !c.input(&req) {
+		return
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	query := db.Model(&models.TokenMapVerification{})
+	if req.Mismatch {
+		query = query.Where("status in ?", []interface{}{models.TokenMapVerifyMismatch, models.TokenMapVerifyFailed})
+	}
+	var verificationNum int64
+	query.Count(&verificationNum)
+	verifications := make([]*models.TokenMapVerification, 0)
+	query.Limit(req.PageSize).Offset(req.PageSize * req.PageNo).Order("status desc, id asc").Find(&verifications)
+	c.output(models.MakeTokenMapVerificationsRsp(req.PageSize, req.PageNo, (int(verificationNum)+req.PageSize-1)/req.PageSize, int(verificationNum), verifications))
+}
+
+func (c *AdminController) ApiKeys() {
+	apiKeys := make([]*models.ApiKey, 0)
+	db.Order("id asc").Find(&apiKeys)
+	c.output(apiKeys)
+}
+
+func (c *AdminController) AddApiKey() {
+	var req models.ApiKey
+	if !c.input(&req) {
+		return
+	}
+	if err := validateApiKey(&req); err != nil {
+		c.fail(err)
+		return
+	}
+	apiKey := &models.ApiKey{
+		Key:      req.Key,
+		Name:     req.Name,
+		Tier:     req.Tier,
+		Rate:     req.Rate,
+		Burst:    req.Burst,
+		Quota:    req.Quota,
+		Property: 1,
+		Time:     time.Now().Unix(),
+	}
+	if apiKey.Key == "" {
+		data := make([]byte, 16)
+		if _, err := rand.Read(data); err != nil {
+			c.fail(err)
+			return
+		}
+		apiKey.Key = hex.EncodeToString(data)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Where("name = ? or `key` = ?", apiKey.Name, apiKey.Key).Find(&models.ApiKey{}); res.RowsAffected > 0 {
+			return fmt.Errorf("api key %s exists", apiKey.Name)
+		}
+		if err := tx.Create(apiKey).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionAdd, auditTableApiKey, apiKey.Name, nil, apiKey)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	getApiKeys().refresh()
+	c.output(apiKey)
+}
+
+func (c *AdminController) UpdateApiKey() {
+	var req models.AdminUpdateApiKeyReq
+	if !c.input(&req) {
+		return
+	}
+	apiKey := new(models.ApiKey)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Where("name = ?", req.Name).First(apiKey); res.RowsAffected == 0 {
+			return fmt.Errorf("api key %s does not exist", req.Name)
+		}
+		before := *apiKey
+		updated := *apiKey
+		if req.Tier != nil {
+			updated.Tier = *req.Tier
+		}
+		if req.Rate != nil {
+			updated.Rate = *req.Rate
+		}
+		if req.Burst != nil {
+			updated.Burst = *req.Burst
+		}
+		if req.Quota != nil {
+			updated.Quota = *req.Quota
+		}
+		if req.Property != nil {
+			updated.Property = *req.Property
+		}
+		if err := validateApiKey(&updated); err != nil {
+			return err
+		}
+		updates := map[string]interface{}{
+			"tier":     updated.Tier,
+			"rate":     updated.Rate,
+			"burst":    updated.Burst,
+			"quota":    updated.Quota,
+			"property": updated.Property,
+		}
+		if err := tx.Model(apiKey).Updates(updates).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionUpdate, auditTableApiKey, apiKey.Name, &before, apiKey)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	getApiKeys().refresh()
+	c.output(apiKey)
+}
+
+func (c *AdminController) RemoveApiKey() {
+	var req models.AdminApiKeyReq
+	if !c.input(&req) {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		apiKey := new(models.ApiKey)
+		if res := tx.Where("name = ?", req.Name).First(apiKey); res.RowsAffected == 0 {
+			return fmt.Errorf("api key %s does not exist", req.Name)
+		}
+		if err := tx.Delete(apiKey).Error; err != nil {
+			return err
+		}
+		return c.audit(tx, models.AuditActionRemove, auditTableApiKey, apiKey.Name, apiKey, nil)
+	})
+	if err != nil {
+		c.fail(err)
+		return
+	}
+	getApiKeys().refresh()
+	c.output(req)
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/logs"
+	"poly-bridge/basedef"
+	"poly-bridge/chainsdk"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/tokenmapverify"
+	"sync"
+)
+
+const nativeTokenHash = "0000000000000000000000000000000000000000"
+
+var (
+	adminSdkOnce     sync.Once
+	ethereumSdks     = make(map[uint64]*chainsdk.EthereumSdkPro)
+	neoSdks          = make(map[uint64]*chainsdk.NeoSdkPro)
+	tokenMapVerifier *tokenmapverify.Verifier
+)
+
+func initAdminSdks() {
+	configFile := beego.AppConfig.String("bridgeconfig")
+	if configFile == "" {
+		logs.Warn("bridgeconfig is not set, tokens will not be checked on chain")
+		return
+	}
+	config := conf.NewConfig(configFile)
+	if config == nil {
+		logs.Error("read bridge config %s failed, tokens will not be checked on chain", configFile)
+		return
+	}
+	ethereumSdks = tokenmapverify.NewEthereumSdks(config.ChainListenConfig)
+	for _, chainListenConfig := range config.ChainListenConfig {
+		if chainListenConfig.ChainId == basedef.NEO_CROSSCHAIN_ID {
+			neoSdks[chainListenConfig.ChainId] = chainsdk.NewNeoSdkPro(chainListenConfig.GetNodesUrl(), chainListenConfig.ListenSlot, chainListenConfig.ChainId)
+		}
+	}
+	tokenMapVerifier = tokenmapverify.NewVerifier(config.ChainListenConfig, ethereumSdks)
+}
+
+// checkTokenOnChain makes sure the token exists on its chain with the configured precision, or as an ERC721 token.
+// Native tokens, ERC1155 tokens which have no name and decimals, NFT tokens out of the ethereum chains, and chains
+// without a sdk are not checked.
+func checkTokenOnChain(token *models.Token) error {
+	adminSdkOnce.Do(initAdminSdks)
+	if token.Hash == nativeTokenHash || token.Standard == models.TokenTypeErc1155 {
+		return nil
+	}
+	if token.Standard == models.TokenTypeErc721 {
+		sdk, ok := ethereumSdks[token.ChainId]
+		if !ok {
+			return nil
+		}
+		erc721, err := sdk.IsErc721(token.Hash)
+		if err != nil {
+			return fmt.Errorf("token %s does not exist on chain %d: %v", token.Hash, token.ChainId, err)
+		}
+		if !erc721 {
+			return fmt.Errorf("token %s on chain %d is not an erc721 token", token.Hash, token.ChainId)
+		}
+		return nil
+	}
+	var decimal int64
+	var err error
+	if sdk, ok := ethereumSdks[token.ChainId]; ok {
+		_, _, decimal, _, err = sdk.Erc20Info(token.Hash)
+	} else if sdk, ok := neoSdks[token.ChainId]; ok {
+		_, _, decimal, err = sdk.Nep5Info(token.Hash)
+	} else {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("token %s does not exist on chain %d: %v", token.Hash, token.ChainId, err)
+	}
+	if uint64(decimal) != token.Precision {
+		return fmt.Errorf("precision of token %s on chain %d is %d, not %d", token.Hash, token.ChainId, decimal, token.Precision)
+	}
+	return nil
+}
+
+func validateTokenBasic(tokenBasic *models.TokenBasic) error {
+	if tokenBasic.Name == "" {
+		return fmt.Errorf("token basic name is empty")
+	}
+	if tokenBasic.Standard != models.TokenTypeErc20 && !models.IsNFTStandard(tokenBasic.Standard) {
+		return fmt.Errorf("standard %d is not supported", tokenBasic.Standard)
+	}
+	return nil
+}
+
+func validateToken(token *models.Token) error {
+	if token.Hash == "" || token.Name == "" {
+		return fmt.Errorf("token hash or name is empty")
+	}
+	tokenBasic := new(models.TokenBasic)
+	res := db.Where("name = ?", token.TokenBasicName).First(tokenBasic)
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("token basic %s does not exist", token.TokenBasicName)
+	}
+	if token.Standard != tokenBasic.Standard {
+		return fmt.Errorf("standard of token %s is %d, token basic %s is %d", token.Hash, token.Standard, tokenBasic.Name, tokenBasic.Standard)
+	}
+	return checkTokenOnChain(token)
+}
+
+func validateApiKey(apiKey *models.ApiKey) error {
+	if apiKey.Name == "" {
+		return fmt.Errorf("api key name is empty")
+	}
+	if apiKey.Tier != models.ApiKeyTierPublic && apiKey.Tier != models.ApiKeyTierRelayer {
+		return fmt.Errorf("tier %s is not supported", apiKey.Tier)
+	}
+	if apiKey.Rate < 0 || apiKey.Burst < 0 || apiKey.Quota < 0 {
+		return fmt.Errorf("rate, burst or quota of api key %s is negative", apiKey.Name)
+	}
+	return nil
+}
+
+// validateTokenMaps checks both tokens of every map exist, and every map is added together with or after its reverse.
+func validateTokenMaps(tokenMaps []*models.TokenMap) error {
+	tokenMapKey := func(srcChainId uint64, srcTokenHash string, dstChainId uint64, dstTokenHash string) string {
+		return fmt.Sprintf("%d:%s->%d:%s", srcChainId, srcTokenHash, dstChainId, dstTokenHash)
+	}
+	keys := make(map[string]bool)
+	for _, tokenMap := range tokenMaps {
+		keys[tokenMapKey(tokenMap.SrcChainId, tokenMap.SrcTokenHash, tokenMap.DstChainId, tokenMap.DstTokenHash)] = true
+	}
+	for _, tokenMap := range tokenMaps {
+		if tokenMap.SrcChainId == tokenMap.DstChainId {
+			return fmt.Errorf("token map %d:%s->%d:%s is on the same chain", tokenMap.SrcChainId, tokenMap.SrcTokenHash, tokenMap.DstChainId, tokenMap.DstTokenHash)
+		}
+		for _, token := range []*models.Token{{ChainId: tokenMap.SrcChainId, Hash: tokenMap.SrcTokenHash}, {ChainId: tokenMap.DstChainId, Hash: tokenMap.DstTokenHash}} {
+			res := db.Where("chain_id = ? and hash = ?", token.ChainId, token.Hash).First(token)
+			if res.RowsAffected == 0 {
+				return fmt.Errorf("token %s does not exist on chain %d", token.Hash, token.ChainId)
+			}
+			if token.Standard != tokenMap.Standard {
+				return fmt.Errorf("standard of token %s is %d, token map is %d", token.Hash, token.Standard, tokenMap.Standard)
+			}
+		}
+		if keys[tokenMapKey(tokenMap.DstChainId, tokenMap.DstTokenHash, tokenMap.SrcChainId, tokenMap.SrcTokenHash)] {
+			continue
+		}
+		reverse := new(models.TokenMap)
+		res := db.Where("src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and dst_token_hash = ?",
+			tokenMap.DstChainId, tokenMap.DstTokenHash, tokenMap.SrcChainId, tokenMap.SrcTokenHash).First(reverse)
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("token map %d:%s->%d:%s does not have a reverse map", tokenMap.SrcChainId, tokenMap.SrcTokenHash, tokenMap.DstChainId, tokenMap.DstTokenHash)
+		}
+	}
+	return nil
+}
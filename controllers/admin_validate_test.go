@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"poly-bridge/chainsdk"
+	"poly-bridge/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testErc20  = "0x1111111111111111111111111111111111111111"
+	testErc721 = "0x2222222222222222222222222222222222222222"
+)
+
+func abiWord(value *big.Int) string {
+	return common.Bytes2Hex(common.LeftPadBytes(value.Bytes(), 32))
+}
+
+func abiString(value string) string {
+	return abiWord(big.NewInt(32)) + abiWord(big.NewInt(int64(len(value)))) + common.Bytes2Hex(common.RightPadBytes([]byte(value), 32))
+}
+
+// newEthereumNode serves an erc20 token of 18 decimals and an erc721 token, the other calls revert.
+func newEthereumNode(blockNumberCalls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage
+			Method string
+			Params []json.RawMessage
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		result, revert := "", false
+		switch req.Method {
+		case "eth_blockNumber":
+			atomic.AddInt32(blockNumberCalls, 1)
+			result = "0x10"
+		case "eth_call":
+			var msg struct{ To, Data string }
+			_ = json.Unmarshal(req.Params[0], &msg)
+			call := strings.ToLower(msg.To) + ":" + strings.TrimPrefix(msg.Data, "0x")[:8]
+			switch call {
+			case testErc20 + ":06fdde03", testErc20 + ":95d89b41":
+				result = "0x" + abiString("TEST")
+			case testErc20 + ":313ce567":
+				result = "0x" + abiWord(big.NewInt(18))
+			case testErc721 + ":01ffc9a7":
+				result = "0x" + abiWord(big.NewInt(1))
+			default:
+				revert = true
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if revert {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"execution reverted"}}`, req.Id)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"%s"}`, req.Id, result)
+	}))
+}
+
+func TestCheckTokenOnChain(t *testing.T) {
+	var blockNumberCalls int32
+	node := newEthereumNode(&blockNumberCalls)
+	defer node.Close()
+	adminSdkOnce.Do(func() {})
+	sdk := chainsdk.NewEthereumSdkPro([]string{node.URL}, 3600, 2)
+	ethereumSdks[2] = sdk
+
+	var testdata = []struct {
+		token *models.Token
+		pass  bool
+	}{
+		{token: &models.Token{ChainId: 2, Hash: testErc20, Precision: 18, Standard: models.TokenTypeErc20}, pass: true},
+		{token: &models.Token{ChainId: 2, Hash: testErc20, Precision: 6, Standard: models.TokenTypeErc20}},
+		{token: &models.Token{ChainId: 2, Hash: testErc721, Standard: models.TokenTypeErc721}, pass: true},
+		{token: &models.Token{ChainId: 2, Hash: testErc721, Precision: 18, Standard: models.TokenTypeErc20}},
+		{token: &models.Token{ChainId: 2, Hash: testErc20, Standard: models.TokenTypeErc721}},
+		{token: &models.Token{ChainId: 2, Hash: testErc20, Standard: models.TokenTypeErc1155}, pass: true},
+		{token: &models.Token{ChainId: 6, Hash: testErc20, Standard: models.TokenTypeErc721}, pass: true},
+	}
+
+	for i, v := range testdata {
+		err := checkTokenOnChain(v.token)
+		assert.Equal(t, v.pass, err == nil, "%d: %v", i, err)
+		// the reverts of the contracts do not fail over the node
+		assert.NotNil(t, sdk.GetLatest(), i)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&blockNumberCalls))
+}
@@ -25,6 +25,7 @@ import (
 	"math/big"
 	"poly-bridge/basedef"
 	"poly-bridge/models"
+	"time"
 )
 
 type FeeController struct {
@@ -39,31 +40,104 @@ func (c *FeeController) GetFee() {
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 	}
+	key := fmt.Sprintf("fee_%d_%s_%d_%s_%s", getFeeReq.SrcChainId, getFeeReq.Hash, getFeeReq.DstChainId, getFeeReq.User, getFeeReq.SwapTokenHash)
+	if serveCache(&c.Controller, cacheNamespaceFee, key) {
+		return
+	}
 	token := new(models.Token)
-	res := db.Where("hash = ? and chain_id = ?", getFeeReq.Hash, getFeeReq.SrcChainId).Preload("TokenBasic").First(token)
+	res := readDB().Where("hash = ? and chain_id = ?", getFeeReq.Hash, getFeeReq.SrcChainId).Preload("TokenBasic").First(token)
 	if res.RowsAffected == 0 {
 		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have token: %s", getFeeReq.SrcChainId, getFeeReq.Hash))
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 		return
 	}
+	swapToken := token
+	if getFeeReq.SwapTokenHash != "" {
+		swapToken = new(models.Token)
+		res = readDB().Where("hash = ? and chain_id = ?", getFeeReq.SwapTokenHash, getFeeReq.SrcChainId).First(swapToken)
+		if res.RowsAffected == 0 {
+			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have token: %s", getFeeReq.SrcChainId, getFeeReq.SwapTokenHash))
+			c.Ctx.ResponseWriter.WriteHeader(400)
+			c.ServeJSON()
+			return
+		}
+	}
 	chainFee := new(models.ChainFee)
-	res = db.Where("chain_id = ?", getFeeReq.DstChainId).Preload("TokenBasic").First(chainFee)
+	res = readDB().Where("chain_id = ?", getFeeReq.DstChainId).Preload("TokenBasic").Preload("AssetFees").First(chainFee)
 	if res.RowsAffected == 0 {
 		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have fee", getFeeReq.DstChainId))
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 		return
 	}
-	proxyFee := new(big.Float).SetInt(&chainFee.ProxyFee.Int)
-	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.FEE_PRECISION))
-	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
-	usdtFee := new(big.Float).Mul(proxyFee, new(big.Float).SetInt64(chainFee.TokenBasic.Price))
-	usdtFee = new(big.Float).Quo(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-	tokenFee := new(big.Float).Mul(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-	tokenFee = new(big.Float).Quo(tokenFee, new(big.Float).SetInt64(token.TokenBasic.Price))
-	tokenFeeWithPrecision := new(big.Float).Mul(tokenFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
-	c.Data["json"] = models.MakeGetFeeRsp(getFeeReq.SrcChainId, getFeeReq.Hash, getFeeReq.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision)
+	dstTokenHash := ""
+	tokenMap := new(models.TokenMap)
+	res = readDB().Where("src_chain_id = ? and src_token_hash = ? and dst_chain_id = ?", getFeeReq.SrcChainId, swapToken.Hash, getFeeReq.DstChainId).Find(tokenMap)
+	if res.RowsAffected > 0 {
+		dstTokenHash = tokenMap.DstTokenHash
+	}
+	feePolicies := make([]*models.FeePolicy, 0)
+	readDB().Find(&feePolicies)
+	getFeeRsp, err := quoteFee(token, swapToken.TokenBasicName, chainFee.Select(swapToken.Standard, dstTokenHash), feePolicies, getFeeReq.DstChainId, getFeeReq.User)
+	if err != nil {
+		c.Data["json"] = models.MakeErrorRsp(err.Error())
+		c.Ctx.ResponseWriter.WriteHeader(400)
+		c.ServeJSON()
+		return
+	}
+	getFeeRsp.Hash = getFeeReq.Hash
+	serveAndCache(&c.Controller, cacheNamespaceFee, key, getFeeRsp)
+}
+
+func (c *FeeController) GetFees() {
+	var getFeesReq models.GetFeesReq
+	var err error
+	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &getFeesReq); err != nil {
+		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
+		c.Ctx.ResponseWriter.WriteHeader(400)
+		c.ServeJSON()
+		return
+	}
+	if len(getFeesReq.Fees) > 100 {
+		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("too many fees: %d, at most 100", len(getFeesReq.Fees)))
+		c.Ctx.ResponseWriter.WriteHeader(400)
+		c.ServeJSON()
+		return
+	}
+	snapshot := feeSnapshots
+	getFeeReqs := getFeesReq.Fees
+	if len(getFeeReqs) == 0 && getFeesReq.Hash != "" {
+		routeHash := getFeesReq.Hash
+		if getFeesReq.SwapTokenHash != "" {
+			routeHash = getFeesReq.SwapTokenHash
+		}
+		for _, dstChainId := range snapshot.Routes(getFeesReq.SrcChainId, routeHash) {
+			getFeeReqs = append(getFeeReqs, &models.GetFeeReq{
+				SrcChainId: getFeesReq.SrcChainId,
+				Hash:       getFeesReq.Hash,
+				DstChainId: dstChainId,
+			})
+		}
+	}
+	getFeeRsps := make([]*models.GetFeeRsp, 0)
+	for _, getFeeReq := range getFeeReqs {
+		user := getFeeReq.User
+		if user == "" {
+			user = getFeesReq.User
+		}
+		swapTokenHash := getFeeReq.SwapTokenHash
+		if swapTokenHash == "" {
+			swapTokenHash = getFeesReq.SwapTokenHash
+		}
+		getFeeRsp, err := snapshot.Quote(getFeeReq.SrcChainId, getFeeReq.Hash, swapTokenHash, getFeeReq.DstChainId, user)
+		if err != nil {
+			logs.Debug("get fees, skip %d %s %d: %v", getFeeReq.SrcChainId, getFeeReq.Hash, getFeeReq.DstChainId, err)
+			continue
+		}
+		getFeeRsps = append(getFeeRsps, getFeeRsp)
+	}
+	c.Data["json"] = models.MakeGetFeesRsp(getFeeRsps)
 	c.ServeJSON()
 }
 
@@ -88,7 +162,7 @@ func (c *FeeController) CheckFee() {
 	checkFees4Normal := make([]*models.CheckFee, 0)
 	checkFees4O3 := make([]*models.CheckFee, 0)
 	if len(checkFeesReq4O3) > 0 {
-		checkFees4O3 = c.CheckSwapFee(checkFeesReq4O3)
+		checkFees4O3 = c.checkSwapFee(checkFeesReq4O3)
 	}
 	if len(checkFeesReq4Nomal) > 0 {
 		checkFees4Normal = c.checkFee(checkFeesReq4Nomal)
@@ -100,6 +174,7 @@ func (c *FeeController) CheckFee() {
 	c.ServeJSON()
 }
 
+// checkFee reads from the primary, the relayer checks the transactions right after they are listened.
 func (c *FeeController) checkFee(Checks []*models.CheckFeeReq) []*models.CheckFee {
 	hash2ChainId := make(map[string]uint64, 0)
 	requestHashs := make([]string, 0)
@@ -136,8 +211,29 @@ func (c *FeeController) checkFee(Checks []*models.CheckFeeReq) []*models.CheckFe
 	for _, wrapperTransactionWithToken := range wrapperTransactionWithTokens {
 		txHash2WrapperTransaction[wrapperTransactionWithToken.Hash] = wrapperTransactionWithToken
 	}
+	srcTransfers := make([]*models.SrcTransfer, 0)
+	db.Where("tx_hash in ?", checkHashes).Find(&srcTransfers)
+	txHash2DstAsset := make(map[string]string, 0)
+	assets := make([]string, 0)
+	for _, srcTransfer := range srcTransfers {
+		txHash2DstAsset[srcTransfer.TxHash] = srcTransfer.DstAsset
+		assets = append(assets, srcTransfer.Asset)
+	}
+	tokens := make([]*models.Token, 0)
+	db.Where("hash in ?", assets).Find(&tokens)
+	txHash2TokenBasicName := make(map[string]string, 0)
+	for _, srcTransfer := range srcTransfers {
+		for _, token := range tokens {
+			if token.ChainId == srcTransfer.ChainId && token.Hash == srcTransfer.Asset {
+				txHash2TokenBasicName[srcTransfer.TxHash] = token.TokenBasicName
+			}
+		}
+	}
+	feePolicies := make([]*models.FeePolicy, 0)
+	db.Find(&feePolicies)
+	now := time.Now().Unix()
 	chainFees := make([]*models.ChainFee, 0)
-	db.Preload("TokenBasic").Find(&chainFees)
+	db.Preload("TokenBasic").Preload("AssetFees").Find(&chainFees)
 	chain2Fees := make(map[uint64]*models.ChainFee, 0)
 	for _, chainFee := range chainFees {
 		chain2Fees[chainFee.ChainId] = chainFee
@@ -173,20 +269,8 @@ func (c *FeeController) checkFee(Checks []*models.CheckFeeReq) []*models.CheckFe
 			checkFees = append(checkFees, checkFee)
 			continue
 		}
-		x := new(big.Int).Mul(&wrapperTransactionWithToken.FeeAmount.Int, big.NewInt(wrapperTransactionWithToken.FeeToken.TokenBasic.Price))
-		feePay := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.Int64FromFigure(int(wrapperTransactionWithToken.FeeToken.Precision))))
-		feePay = new(big.Float).Quo(feePay, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-		x = new(big.Int).Mul(&chainFee.MinFee.Int, big.NewInt(chainFee.TokenBasic.Price))
-		feeMin := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-		feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.FEE_PRECISION))
-		feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
-		if feePay.Cmp(feeMin) >= 0 {
-			checkFee.PayState = 1
-		} else {
-			checkFee.PayState = -1
-		}
-		checkFee.Amount = feePay
-		checkFee.MinProxyFee = feeMin
+		chainFee = chainFee.Select(wrapperTransactionWithToken.Standard, txHash2DstAsset[newHash])
+		models.CheckPaidFee(checkFee, wrapperTransactionWithToken, chainFee, feePolicies, txHash2TokenBasicName[newHash], now)
 		checkFees = append(checkFees, checkFee)
 	}
 	return checkFees
@@ -210,7 +294,20 @@ func (c *FeeController) getSwapSrcTransactions(o3Hashs []string) (map[string]str
 	return checkHashes, nil
 }
 
-func (c *FeeController) CheckSwapFee(Checks []*models.CheckFeeReq) []*models.CheckFee {
+// CheckSwapFee checks the fees of the swaps of O3, whose hashes are the transactions on O3.
+func (c *FeeController) CheckSwapFee() {
+	var checkFeesReq models.CheckFeesReq
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &checkFeesReq); err != nil {
+		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
+		c.Ctx.ResponseWriter.WriteHeader(400)
+		c.ServeJSON()
+		return
+	}
+	c.Data["json"] = models.MakeCheckFeesRsp(c.checkSwapFee(checkFeesReq.Checks))
+	c.ServeJSON()
+}
+
+func (c *FeeController) checkSwapFee(Checks []*models.CheckFeeReq) []*models.CheckFee {
 	hash2ChainId := make(map[string]uint64, 0)
 	requestHashs := make([]string, 0)
 	for _, check := range Checks {
@@ -300,20 +397,7 @@ func (c *FeeController) CheckSwapFee(Checks []*models.CheckFeeReq) []*models.Che
 			checkFees = append(checkFees, checkFee)
 			continue
 		}
-		x := new(big.Int).Mul(&wrapperTransactionWithToken.FeeAmount.Int, big.NewInt(wrapperTransactionWithToken.FeeToken.TokenBasic.Price))
-		feePay := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.Int64FromFigure(int(wrapperTransactionWithToken.FeeToken.Precision))))
-		feePay = new(big.Float).Quo(feePay, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-		x = new(big.Int).Mul(&chainFee.MinFee.Int, big.NewInt(chainFee.TokenBasic.Price))
-		feeMin := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-		feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.FEE_PRECISION))
-		feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
-		if feePay.Cmp(feeMin) >= 0 {
-			checkFee.PayState = 1
-		} else {
-			checkFee.PayState = -1
-		}
-		checkFee.Amount = feePay
-		checkFee.MinProxyFee = feeMin
+		models.CheckPaidFee(checkFee, wrapperTransactionWithToken, chainFee, nil, "", 0)
 		checkFees = append(checkFees, checkFee)
 	}
 	return checkFees
@@ -0,0 +1,232 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/logs"
+	"math/big"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// feeSnapshot keeps the chain fees, tokens and fee policies in memory for quoting fees in batch.
+// It is reloaded when the fee or price listener updates the tables, and right after the admin api changes them.
+type feeSnapshot struct {
+	mutex       sync.RWMutex
+	version     string
+	chainFees   map[uint64]*models.ChainFee
+	tokens      map[string]*models.Token
+	feePolicies []*models.FeePolicy
+}
+
+var feeSnapshots = &feeSnapshot{}
+
+// startFeeSnapshot loads the snapshot and starts watching its version, so that the response cache is invalidated
+// from the start whether or not the fees are quoted in batch.
+func startFeeSnapshot() {
+	feeSnapshots.refresh(false)
+	go feeSnapshots.Refresh()
+}
+
+func tokenKey(chainId uint64, hash string) string {
+	return fmt.Sprintf("%d:%s", chainId, strings.ToLower(hash))
+}
+
+func (snapshot *feeSnapshot) Refresh() {
+	slot, err := beego.AppConfig.Int64("feesnapshotslot")
+	if err != nil || slot <= 0 {
+		slot = 10
+	}
+	for {
+		time.Sleep(time.Second * time.Duration(slot))
+		snapshot.refresh(false)
+	}
+}
+
+// Reload reloads the snapshot whether the version changes or not.
+func (snapshot *feeSnapshot) Reload() {
+	snapshot.refresh(true)
+}
+
+func (snapshot *feeSnapshot) refresh(force bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("fee snapshot refresh, recover info: %s", string(debug.Stack()))
+		}
+	}()
+	version, err := snapshot.currentVersion()
+	if err != nil {
+		logs.Error("fee snapshot version err: %v", err)
+		return
+	}
+	snapshot.mutex.RLock()
+	current := snapshot.version
+	snapshot.mutex.RUnlock()
+	if version == current && !force {
+		return
+	}
+	chainFees := make([]*models.ChainFee, 0)
+	res := db.Preload("TokenBasic").Preload("AssetFees").Find(&chainFees)
+	if res.Error != nil {
+		logs.Error("fee snapshot load chain fees err: %v", res.Error)
+		return
+	}
+	tokens := make([]*models.Token, 0)
+	res = db.Preload("TokenBasic").Preload("TokenMaps").Find(&tokens)
+	if res.Error != nil {
+		logs.Error("fee snapshot load tokens err: %v", res.Error)
+		return
+	}
+	feePolicies := make([]*models.FeePolicy, 0)
+	res = db.Find(&feePolicies)
+	if res.Error != nil {
+		logs.Error("fee snapshot load fee policies err: %v", res.Error)
+		return
+	}
+	chain2Fees := make(map[uint64]*models.ChainFee, len(chainFees))
+	for _, chainFee := range chainFees {
+		chain2Fees[chainFee.ChainId] = chainFee
+	}
+	key2Tokens := make(map[string]*models.Token, len(tokens))
+	for _, token := range tokens {
+		key2Tokens[tokenKey(token.ChainId, token.Hash)] = token
+	}
+	snapshot.mutex.Lock()
+	snapshot.version = version
+	snapshot.chainFees = chain2Fees
+	snapshot.tokens = key2Tokens
+	snapshot.feePolicies = feePolicies
+	snapshot.mutex.Unlock()
+	logs.Info("fee snapshot is reloaded, version: %s", version)
+	if current != "" {
+		invalidateResponseCache()
+	}
+}
+
+// currentVersion changes whenever a row of the fees, prices, tokens or policies is added or removed, a fee or
+// price is updated, or a token, token map or policy is enabled or disabled. It aggregates the small tables on the
+// replica instead of checksumming them, which locks them on the primary. Other updates of the tokens and policies
+// are made by the admin api, which reloads the snapshot.
+func (snapshot *feeSnapshot) currentVersion() (string, error) {
+	type tableVersions struct {
+		ChainFees   string
+		AssetFees   string
+		TokenBasics string
+		Tokens      string
+		TokenMaps   string
+		FeePolicies string
+	}
+	versions := new(tableVersions)
+	res := readDB().Raw("select " +
+		"(select concat_ws('-', count(*), coalesce(max(time), 0)) from chain_fees) as chain_fees, " +
+		"(select concat_ws('-', count(*), coalesce(max(time), 0)) from asset_fees) as asset_fees, " +
+		"(select concat_ws('-', count(*), coalesce(max(time), 0), coalesce(sum(property), 0)) from token_basics) as token_basics, " +
+		"(select concat_ws('-', count(*), coalesce(max(id), 0), coalesce(sum(property), 0)) from tokens) as tokens, " +
+		"(select concat_ws('-', count(*), coalesce(max(id), 0), coalesce(sum(property), 0)) from token_maps) as token_maps, " +
+		"(select concat_ws('-', count(*), coalesce(max(id), 0), coalesce(sum(ind), 0)) from fee_policies) as fee_policies").
+		Scan(versions)
+	if res.Error != nil {
+		return "", res.Error
+	}
+	return strings.Join([]string{versions.ChainFees, versions.AssetFees, versions.TokenBasics, versions.Tokens,
+		versions.TokenMaps, versions.FeePolicies}, "/"), nil
+}
+
+// Quote returns the fee of the transfer from the snapshot, paid in the token hash.
+// The transfer is of the token swapTokenHash, or of the token hash when it is empty.
+func (snapshot *feeSnapshot) Quote(srcChainId uint64, hash string, swapTokenHash string, dstChainId uint64, user string) (*models.GetFeeRsp, error) {
+	snapshot.mutex.RLock()
+	defer snapshot.mutex.RUnlock()
+	token, ok := snapshot.tokens[tokenKey(srcChainId, hash)]
+	if !ok {
+		return nil, fmt.Errorf("chain: %d does not have token: %s", srcChainId, hash)
+	}
+	swapToken := token
+	if swapTokenHash != "" {
+		swapToken, ok = snapshot.tokens[tokenKey(srcChainId, swapTokenHash)]
+		if !ok {
+			return nil, fmt.Errorf("chain: %d does not have token: %s", srcChainId, swapTokenHash)
+		}
+	}
+	chainFee, ok := snapshot.chainFees[dstChainId]
+	if !ok {
+		return nil, fmt.Errorf("chain: %d does not have fee", dstChainId)
+	}
+	dstTokenHash := ""
+	for _, tokenMap := range swapToken.TokenMaps {
+		if tokenMap.DstChainId == dstChainId {
+			dstTokenHash = tokenMap.DstTokenHash
+			break
+		}
+	}
+	getFeeRsp, err := quoteFee(token, swapToken.TokenBasicName, chainFee.Select(swapToken.Standard, dstTokenHash), snapshot.feePolicies, dstChainId, user)
+	if err != nil {
+		return nil, err
+	}
+	getFeeRsp.Hash = hash
+	return getFeeRsp, nil
+}
+
+// Routes returns the destination chains the token can be transferred to.
+func (snapshot *feeSnapshot) Routes(srcChainId uint64, hash string) []uint64 {
+	snapshot.mutex.RLock()
+	defer snapshot.mutex.RUnlock()
+	dstChainIds := make([]uint64, 0)
+	token, ok := snapshot.tokens[tokenKey(srcChainId, hash)]
+	if !ok {
+		return dstChainIds
+	}
+	for _, tokenMap := range token.TokenMaps {
+		if tokenMap.Property == 1 && tokenMap.DstChainId != srcChainId {
+			dstChainIds = append(dstChainIds, tokenMap.DstChainId)
+		}
+	}
+	return dstChainIds
+}
+
+// quoteFee converts the proxy fee of the destination chain to the token paid on the source chain.
+// The fee policy is matched on tokenBasicName, the token transferred, as the fee check does.
+func quoteFee(token *models.Token, tokenBasicName string, chainFee *models.ChainFee, feePolicies []*models.FeePolicy, dstChainId uint64, user string) (*models.GetFeeRsp, error) {
+	if token.TokenBasic == nil || token.TokenBasic.Price == 0 {
+		return nil, fmt.Errorf("token: %s does not have price", token.Hash)
+	}
+	if chainFee.TokenBasic == nil {
+		return nil, fmt.Errorf("chain: %d does not have fee token", dstChainId)
+	}
+	proxyFee := new(big.Float).SetInt(&chainFee.ProxyFee.Int)
+	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.FEE_PRECISION))
+	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
+	usdtFee := new(big.Float).Mul(proxyFee, new(big.Float).SetInt64(chainFee.TokenBasic.Price))
+	usdtFee = new(big.Float).Quo(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+	policyId := int64(0)
+	feePolicy := models.EvaluateFeePolicy(feePolicies, token.ChainId, dstChainId, tokenBasicName, user, time.Now().Unix())
+	if feePolicy != nil {
+		usdtFee = feePolicy.Apply(usdtFee)
+		policyId = feePolicy.Id
+	}
+	tokenFee := new(big.Float).Mul(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+	tokenFee = new(big.Float).Quo(tokenFee, new(big.Float).SetInt64(token.TokenBasic.Price))
+	tokenFeeWithPrecision := new(big.Float).Mul(tokenFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
+	return models.MakeGetFeeRsp(token.ChainId, token.Hash, dstChainId, usdtFee, tokenFee, tokenFeeWithPrecision, policyId), nil
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"math/big"
+	"testing"
+
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteAndCheckFee(t *testing.T) {
+	// the proxy fee to chain 6 is 0.01 BNB of 300 usd, the fee is paid in ETH of 2000 usd for a transfer of USDT
+	proxyFee := models.NewBigInt(new(big.Int).Mul(big.NewInt(1e16), big.NewInt(basedef.FEE_PRECISION)))
+	chainFee := &models.ChainFee{ChainId: 6, ProxyFee: proxyFee, MinFee: proxyFee, TokenBasicName: "BNB",
+		TokenBasic: &models.TokenBasic{Name: "BNB", Precision: 18, Price: 300 * basedef.PRICE_PRECISION}}
+	eth := &models.Token{ChainId: 2, Hash: "eth", Precision: 18, TokenBasicName: "ETH",
+		TokenBasic: &models.TokenBasic{Name: "ETH", Precision: 18, Price: 2000 * basedef.PRICE_PRECISION}}
+	usdt := &models.Token{ChainId: 2, Hash: "usdt", Precision: 6, TokenBasicName: "USDT",
+		TokenBasic: &models.TokenBasic{Name: "USDT", Precision: 6, Price: basedef.PRICE_PRECISION},
+		TokenMaps:  []*models.TokenMap{{SrcChainId: 2, SrcTokenHash: "usdt", DstChainId: 6, DstTokenHash: "busdt", Property: 1}}}
+	snapshot := &feeSnapshot{
+		chainFees:   map[uint64]*models.ChainFee{6: chainFee},
+		tokens:      map[string]*models.Token{tokenKey(2, "eth"): eth, tokenKey(2, "usdt"): usdt},
+		feePolicies: []*models.FeePolicy{{Id: 1, Name: "usdt", Version: 1, TokenBasicName: "USDT", Discount: 50, Ind: 1}},
+	}
+
+	var testdata = []struct {
+		swapTokenHash  string
+		tokenBasicName string // of the asset the check finds in the transfer
+		usdtAmount     string
+		policyId       int64
+	}{
+		{swapTokenHash: "usdt", tokenBasicName: "USDT", usdtAmount: "1.5", policyId: 1},
+		{tokenBasicName: "ETH", usdtAmount: "3"},
+	}
+
+	for i, v := range testdata {
+		getFeeRsp, err := snapshot.Quote(2, "eth", v.swapTokenHash, 6, "")
+		assert.Nil(t, err, i)
+		assert.Equal(t, v.usdtAmount, getFeeRsp.UsdtAmount, i)
+		assert.Equal(t, v.policyId, getFeeRsp.PolicyId, i)
+
+		feeAmount, ok := new(big.Int).SetString(getFeeRsp.TokenAmountWithPrecision, 10)
+		assert.True(t, ok, i)
+		checkFee := &models.CheckFee{Amount: new(big.Float), MinProxyFee: new(big.Float)}
+		wrapper := &models.WrapperTransactionWithToken{SrcChainId: 2, DstChainId: 6, FeeToken: eth, FeeAmount: models.NewBigInt(feeAmount)}
+		models.CheckPaidFee(checkFee, wrapper, chainFee.Select(usdt.Standard, "busdt"), snapshot.feePolicies, v.tokenBasicName, 150)
+		assert.Equal(t, 1, checkFee.PayState, i)
+		assert.Equal(t, v.policyId, checkFee.PolicyId, i)
+	}
+
+	_, err := snapshot.Quote(2, "eth", "unknown", 6, "")
+	assert.NotNil(t, err)
+}
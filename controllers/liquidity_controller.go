@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/astaxie/beego"
+	"poly-bridge/models"
+	"strings"
+	"time"
+)
+
+type LiquidityController struct {
+	beego.Controller
+}
+
+// latestLiquidities returns the latest lock proxy balance of every token, filtered by chain and hash when they are set.
+// The max id of every token is read from the index idx_token_liquidity_latest.
+func latestLiquidities(chainId uint64, hash string) []*models.TokenLiquidity {
+	query := readDB().Where("id in (?)", readDB().Model(&models.TokenLiquidity{}).Select("max(id)").Group("chain_id, hash"))
+	if chainId != 0 {
+		query = query.Where("chain_id = ?", chainId)
+	}
+	if hash != "" {
+		query = query.Where("hash = ?", strings.ToLower(hash))
+	}
+	liquidities := make([]*models.TokenLiquidity, 0)
+	query.Order("chain_id asc, hash asc").Find(&liquidities)
+	return liquidities
+}
+
+func (c *LiquidityController) Liquidity() {
+	var liquidityReq models.LiquidityReq
+	var err error
+	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &liquidityReq); err != nil {
+		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
+		c.Ctx.ResponseWriter.WriteHeader(400)
+		c.ServeJSON()
+		return
+	}
+	liquidities := latestLiquidities(liquidityReq.ChainId, liquidityReq.Hash)
+	c.Data["json"] = models.MakeLiquidityRsp(liquidities)
+	c.ServeJSON()
+}
+
+func (c *LiquidityController) LiquidityHistory() {
+	var liquidityHistoryReq models.LiquidityHistoryReq
+	var err error
+	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &liquidityHistoryReq); err != nil {
+		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
+		c.Ctx.ResponseWriter.WriteHeader(400)
+		c.ServeJSON()
+		return
+	}
+	if liquidityHistoryReq.EndTime == 0 {
+		liquidityHistoryReq.EndTime = time.Now().Unix()
+	}
+	if liquidityHistoryReq.StartTime == 0 {
+		liquidityHistoryReq.StartTime = liquidityHistoryReq.EndTime - 7*24*60*60
+	}
+	liquidities := make([]*models.TokenLiquidity, 0)
+	readDB().Where("chain_id = ? and hash = ? and time >= ? and time < ?", liquidityHistoryReq.ChainId, strings.ToLower(liquidityHistoryReq.Hash),
+		liquidityHistoryReq.StartTime, liquidityHistoryReq.EndTime).Order("time asc").Limit(2000).Find(&liquidities)
+	c.Data["json"] = models.MakeLiquidityHistoryRsp(liquidities)
+	c.ServeJSON()
+}
@@ -18,29 +18,54 @@
 package controllers
 
 import (
+	"poly-bridge/conf"
+	"poly-bridge/dbrouter"
+	"strings"
+
 	"github.com/astaxie/beego"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 var (
-	db = newDB()
+	dbRouter *dbrouter.Router
+	db       *gorm.DB
 )
 
-func newDB() *gorm.DB {
-	user := beego.AppConfig.String("mysqluser")
-	password := beego.AppConfig.String("mysqlpass")
-	url := beego.AppConfig.String("mysqlurls")
-	scheme := beego.AppConfig.String("mysqldb")
-	mode := beego.AppConfig.String("runmode")
-	Logger := logger.Default
-	if mode == "dev" {
-		Logger = Logger.LogMode(logger.Info)
+// Initialize opens the database of the controllers, loads the fee snapshot and shares the quotas of the api keys,
+// it must be called before the server runs.
+func Initialize() {
+	dbRouter = newDBRouter()
+	db = dbRouter.Primary()
+	startFeeSnapshot()
+	quota = newQuota()
+}
+
+func newDBRouter() *dbrouter.Router {
+	cfg := &conf.DBConfig{
+		User:            beego.AppConfig.String("mysqluser"),
+		Password:        beego.AppConfig.String("mysqlpass"),
+		URL:             beego.AppConfig.String("mysqlurls"),
+		Scheme:          beego.AppConfig.String("mysqldb"),
+		Debug:           beego.AppConfig.String("runmode") == "dev",
+		MaxOpenConns:    beego.AppConfig.DefaultInt("mysqlmaxopenconns", 0),
+		MaxIdleConns:    beego.AppConfig.DefaultInt("mysqlmaxidleconns", 0),
+		ConnMaxLifetime: beego.AppConfig.DefaultInt64("mysqlconnmaxlifetime", 0),
+		Timeout:         beego.AppConfig.DefaultInt64("mysqltimeout", 0),
+		MaxReplicaLag:   beego.AppConfig.DefaultInt64("mysqlmaxreplicalag", dbrouter.DefaultMaxReplicaLag),
 	}
-	db, err := gorm.Open(mysql.Open(user+":"+password+"@tcp("+url+")/"+scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	for _, url := range strings.Split(beego.AppConfig.String("mysqlreadurls"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			cfg.ReadURLs = append(cfg.ReadURLs, url)
+		}
+	}
+	router, err := dbrouter.Open(cfg)
 	if err != nil {
 		panic(err)
 	}
-	return db
+	return router
+}
+
+// readDB returns the database for the read only queries, which may be a replica of db.
+func readDB() *gorm.DB {
+	return dbRouter.Reader()
 }
@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/astaxie/beego"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"poly-bridge/basedef"
+	"poly-bridge/chainaddr"
+	"poly-bridge/models"
+	"time"
+)
+
+type PreCheckController struct {
+	beego.Controller
+}
+
+func appConfigInt64(key string, defaultValue int64) int64 {
+	value, err := beego.AppConfig.Int64(key)
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// PreCheck validates the route of a transfer before it is signed, every check is reported with the reason it fails.
+func (c *PreCheckController) PreCheck() {
+	var preCheckReq models.PreCheckReq
+	var err error
+	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &preCheckReq); err != nil {
+		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
+		c.Ctx.ResponseWriter.WriteHeader(400)
+		c.ServeJSON()
+		return
+	}
+	amount, err := decimal.NewFromString(preCheckReq.Amount)
+	if err != nil || !amount.IsPositive() {
+		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("amount: %s is invalid", preCheckReq.Amount))
+		c.Ctx.ResponseWriter.WriteHeader(400)
+		c.ServeJSON()
+		return
+	}
+	token := new(models.Token)
+	res := readDB().Where("hash = ? and chain_id = ?", preCheckReq.Hash, preCheckReq.SrcChainId).Preload("TokenBasic").First(token)
+	if res.RowsAffected == 0 {
+		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have token: %s", preCheckReq.SrcChainId, preCheckReq.Hash))
+		c.Ctx.ResponseWriter.WriteHeader(400)
+		c.ServeJSON()
+		return
+	}
+	now := time.Now().Unix()
+	checks := make([]*models.PreCheck, 0)
+	dstTokenHash := ""
+	tokenMap := new(models.TokenMap)
+	res = readDB().Where("src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and property = 1", preCheckReq.SrcChainId, preCheckReq.Hash, preCheckReq.DstChainId).
+		Preload("DstToken").First(tokenMap)
+	if res.RowsAffected > 0 {
+		dstTokenHash = tokenMap.DstTokenHash
+	}
+	checks = append(checks, checkTokenMap(token, tokenMap, res.RowsAffected > 0, preCheckReq.DstChainId))
+	chainFee := new(models.ChainFee)
+	res = readDB().Where("chain_id = ?", preCheckReq.DstChainId).Preload("TokenBasic").Preload("AssetFees").First(chainFee)
+	hasChainFee := res.RowsAffected > 0 && chainFee.TokenBasic != nil
+	checks = append(checks, checkChainFee(chainFee, hasChainFee, preCheckReq.DstChainId, now))
+	feeToken := token
+	if preCheckReq.FeeTokenHash != "" && preCheckReq.FeeTokenHash != preCheckReq.Hash {
+		feeToken = new(models.Token)
+		res = readDB().Where("hash = ? and chain_id = ?", preCheckReq.FeeTokenHash, preCheckReq.SrcChainId).Preload("TokenBasic").First(feeToken)
+		if res.RowsAffected == 0 {
+			feeToken = nil
+		}
+	}
+	priceTokenBasics := []*models.TokenBasic{token.TokenBasic}
+	if feeToken != nil && feeToken != token {
+		priceTokenBasics = append(priceTokenBasics, feeToken.TokenBasic)
+	}
+	if hasChainFee {
+		priceTokenBasics = append(priceTokenBasics, chainFee.TokenBasic)
+	}
+	checks = append(checks, checkPrice(priceTokenBasics, now))
+	checks = append(checks, checkLiquidity(amount, preCheckReq.DstChainId, dstTokenHash))
+	checks = append(checks, checkDstAddress(preCheckReq.DstChainId, preCheckReq.DstAddress))
+	var getFeeRsp *models.GetFeeRsp
+	if hasChainFee {
+		feePolicies := make([]*models.FeePolicy, 0)
+		readDB().Find(&feePolicies)
+		selectedFee := chainFee.Select(token.Standard, dstTokenHash)
+		getFeeRsp, _ = quoteFee(token, token.TokenBasicName, selectedFee, feePolicies, preCheckReq.DstChainId, preCheckReq.User)
+		checks = append(checks, checkOfferedFee(selectedFee, feePolicies, token, feeToken, &preCheckReq, now))
+	} else {
+		checks = append(checks, &models.PreCheck{Name: models.PreCheckFee, Reason: fmt.Sprintf("chain: %d does not have fee", preCheckReq.DstChainId)})
+	}
+	c.Data["json"] = models.MakePreCheckRsp(dstTokenHash, getFeeRsp, checks)
+	c.ServeJSON()
+}
+
+func checkTokenMap(token *models.Token, tokenMap *models.TokenMap, exist bool, dstChainId uint64) *models.PreCheck {
+	check := &models.PreCheck{Name: models.PreCheckTokenMap}
+	if !exist {
+		check.Reason = fmt.Sprintf("token: %s can not be transferred to chain: %d", token.Hash, dstChainId)
+	} else if token.Property != 1 {
+		check.Reason = fmt.Sprintf("token: %s is disabled", token.Hash)
+	} else if tokenMap.DstToken == nil || tokenMap.DstToken.Property != 1 {
+		check.Reason = fmt.Sprintf("chain: %d token: %s is disabled", dstChainId, tokenMap.DstTokenHash)
+	} else {
+		check.Pass = true
+	}
+	return check
+}
+
+func checkChainFee(chainFee *models.ChainFee, exist bool, dstChainId uint64, now int64) *models.PreCheck {
+	check := &models.PreCheck{Name: models.PreCheckChainFee}
+	feeExpire := appConfigInt64("precheckfeeexpire", 1800)
+	if !exist {
+		check.Reason = fmt.Sprintf("chain: %d does not have fee", dstChainId)
+	} else if chainFee.Ind == 0 || now-chainFee.Time > feeExpire {
+		check.Reason = fmt.Sprintf("fee of chain: %d is not updated since %d", dstChainId, chainFee.Time)
+	} else {
+		check.Pass = true
+	}
+	return check
+}
+
+func checkPrice(tokenBasics []*models.TokenBasic, now int64) *models.PreCheck {
+	check := &models.PreCheck{Name: models.PreCheckPrice, Pass: true}
+	priceExpire := appConfigInt64("precheckpriceexpire", 86400)
+	for _, tokenBasic := range tokenBasics {
+		if tokenBasic == nil {
+			continue
+		}
+		if tokenBasic.Ind == 0 || tokenBasic.Price == 0 {
+			check.Pass = false
+			check.Reason = fmt.Sprintf("token: %s does not have price", tokenBasic.Name)
+			break
+		}
+		if now-tokenBasic.Time > priceExpire {
+			check.Pass = false
+			check.Reason = fmt.Sprintf("price of token: %s is not updated since %d", tokenBasic.Name, tokenBasic.Time)
+			break
+		}
+	}
+	return check
+}
+
+// checkLiquidity passes when the destination lock proxy holds the amount, the liquidity of mintable tokens is not limited.
+// It fails without a token map, and is skipped for the untracked tokens.
+func checkLiquidity(amount decimal.Decimal, dstChainId uint64, dstTokenHash string) *models.PreCheck {
+	check := &models.PreCheck{Name: models.PreCheckLiquidity}
+	if dstTokenHash == "" {
+		check.Reason = "token map does not exist"
+		return check
+	}
+	check.Pass = true
+	liquidities := latestLiquidities(dstChainId, dstTokenHash)
+	if len(liquidities) == 0 {
+		check.Skipped = true
+		check.Reason = fmt.Sprintf("liquidity of chain: %d token: %s is not tracked", dstChainId, dstTokenHash)
+		return check
+	}
+	liquidity := models.MakeTokenLiquidityRsp(liquidities[0])
+	if liquidity.Mintable {
+		return check
+	}
+	balance, _ := decimal.NewFromString(liquidity.Balance)
+	if amount.GreaterThan(balance) {
+		check.Pass = false
+		check.Reason = fmt.Sprintf("amount: %s exceeds the liquidity: %s of chain: %d", amount.String(), balance.String(), dstChainId)
+	}
+	return check
+}
+
+func checkDstAddress(dstChainId uint64, dstAddress string) *models.PreCheck {
+	check := &models.PreCheck{Name: models.PreCheckAddress, Pass: true}
+	if err := chainaddr.Validate(dstChainId, dstAddress); err != nil {
+		check.Pass = false
+		check.Reason = err.Error()
+	}
+	return check
+}
+
+// checkOfferedFee passes when the fee offered is worth the min fee in usd. The fee amount is in units of the fee token
+// without precision, unlike the fee amount of the wrapper transactions checked by /checkfee/.
+func checkOfferedFee(chainFee *models.ChainFee, feePolicies []*models.FeePolicy, token *models.Token, feeToken *models.Token, preCheckReq *models.PreCheckReq, now int64) *models.PreCheck {
+	check := &models.PreCheck{Name: models.PreCheckFee}
+	if feeToken == nil || feeToken.TokenBasic == nil {
+		check.Reason = fmt.Sprintf("chain: %d does not have fee token: %s", preCheckReq.SrcChainId, preCheckReq.FeeTokenHash)
+		return check
+	}
+	feeAmount, err := decimal.NewFromString(preCheckReq.FeeAmount)
+	if err != nil {
+		check.Reason = fmt.Sprintf("fee amount: %s is invalid", preCheckReq.FeeAmount)
+		return check
+	}
+	feePay := new(big.Float).Mul(feeAmount.BigFloat(), new(big.Float).SetInt64(feeToken.TokenBasic.Price))
+	feePay = new(big.Float).Quo(feePay, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+	feeMin, _ := models.MinFeeUsd(chainFee, feePolicies, preCheckReq.SrcChainId, preCheckReq.DstChainId, token.TokenBasicName, preCheckReq.User, now)
+	if feePay.Cmp(feeMin) >= 0 {
+		check.Pass = true
+	} else {
+		check.Reason = fmt.Sprintf("fee: %s usd is less than the min fee: %s usd", feePay.Text('f', 8), feeMin.Text('f', 8))
+	}
+	return check
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"testing"
+
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckLiquidityWithoutTokenMap(t *testing.T) {
+	check := checkLiquidity(decimal.NewFromInt(1), 6, "")
+	assert.False(t, check.Pass)
+	assert.False(t, check.Skipped)
+	assert.Equal(t, "token map does not exist", check.Reason)
+}
+
+func TestCheckOfferedFee(t *testing.T) {
+	// the least fee is 2 usd, the fee is paid in ETH of 2000 usd
+	chainFee := &models.ChainFee{ChainId: 6, MinFee: models.NewBigIntFromInt(2 * basedef.FEE_PRECISION),
+		TokenBasic: &models.TokenBasic{Name: "USDT", Precision: 0, Price: basedef.PRICE_PRECISION}}
+	eth := &models.Token{ChainId: 2, Hash: "eth", Precision: 18, TokenBasicName: "ETH",
+		TokenBasic: &models.TokenBasic{Name: "ETH", Precision: 18, Price: 2000 * basedef.PRICE_PRECISION}}
+
+	var testdata = []struct {
+		feeAmount string
+		pass      bool
+	}{
+		{feeAmount: "0.0011", pass: true},
+		{feeAmount: "0.0009"},
+		{feeAmount: "2e-3", pass: true},
+		{feeAmount: "0x10"},
+	}
+
+	for _, v := range testdata {
+		req := &models.PreCheckReq{SrcChainId: 2, Hash: "eth", DstChainId: 6, FeeAmount: v.feeAmount}
+		check := checkOfferedFee(chainFee, nil, eth, eth, req, 100)
+		assert.Equal(t, v.pass, check.Pass, v.feeAmount)
+	}
+}
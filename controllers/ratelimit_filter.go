@@ -0,0 +1,197 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"poly-bridge/models"
+	"poly-bridge/ratelimit"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/context"
+	"github.com/astaxie/beego/logs"
+)
+
+const apiKeyRefreshSlot = 60
+
+// apiKeys keeps the enabled api keys in memory, it is reloaded every minute and after they are changed by the admin api.
+type apiKeys struct {
+	mutex sync.RWMutex
+	keys  map[string]*models.ApiKey
+}
+
+var (
+	apiKeysOnce     sync.Once
+	apiKeysInstance = &apiKeys{keys: make(map[string]*models.ApiKey)}
+	limiter         = ratelimit.NewLimiter()
+	quota           = ratelimit.NewQuota()
+	trustedOnce     sync.Once
+	trustedProxies  []*net.IPNet
+)
+
+// newQuota shares the daily quotas of the api keys through redis when it is the cache backend, otherwise every
+// instance counts the requests it serves on its own.
+func newQuota() *ratelimit.Quota {
+	if store := newRedisStore(); store != nil {
+		return ratelimit.NewSharedQuota(store)
+	}
+	return ratelimit.NewQuota()
+}
+
+func getApiKeys() *apiKeys {
+	apiKeysOnce.Do(func() {
+		apiKeysInstance.refresh()
+		go apiKeysInstance.Refresh()
+	})
+	return apiKeysInstance
+}
+
+func (keys *apiKeys) Refresh() {
+	for {
+		time.Sleep(time.Second * apiKeyRefreshSlot)
+		keys.refresh()
+	}
+}
+
+func (keys *apiKeys) refresh() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("api keys refresh, recover info: %s", string(debug.Stack()))
+		}
+	}()
+	apiKeyList := make([]*models.ApiKey, 0)
+	res := db.Where("property = ?", 1).Find(&apiKeyList)
+	if res.Error != nil {
+		logs.Error("load api keys err: %v", res.Error)
+		return
+	}
+	key2ApiKeys := make(map[string]*models.ApiKey, len(apiKeyList))
+	for _, apiKey := range apiKeyList {
+		key2ApiKeys[apiKey.Key] = apiKey
+	}
+	keys.mutex.Lock()
+	keys.keys = key2ApiKeys
+	keys.mutex.Unlock()
+}
+
+func (keys *apiKeys) Get(key string) (*models.ApiKey, bool) {
+	keys.mutex.RLock()
+	defer keys.mutex.RUnlock()
+	apiKey, ok := keys.keys[key]
+	return apiKey, ok
+}
+
+// RateLimit is the filter of the /v1 routes. Requests with an X-Api-Key header are limited by the bucket and
+// daily quota of the key, the others by the bucket of their ip. The relayer endpoints need a key of the relayer tier.
+// The admin api checks its own keys, so its requests are always limited by ip.
+func RateLimit(ctx *context.Context) {
+	if !beego.AppConfig.DefaultBool("ratelimitenable", false) {
+		return
+	}
+	path := ctx.Input.URL()
+	key := ctx.Input.Header("X-Api-Key")
+	if strings.HasPrefix(path, "/v1/admin/") {
+		key = ""
+	}
+	relayer := isRelayerPath(path)
+	now := time.Now()
+	if key == "" {
+		if relayer {
+			rejectRequest(ctx, 403, "relayer api key is required")
+			return
+		}
+		rate := beego.AppConfig.DefaultInt64("ratelimitiprate", 10)
+		burst := beego.AppConfig.DefaultInt64("ratelimitipburst", 20)
+		ok, remaining, wait := limiter.Allow("ip:"+clientIP(ctx), float64(rate), burst, now)
+		setRateLimitHeaders(ctx, burst, remaining)
+		if !ok {
+			tooManyRequests(ctx, wait, "rate limit exceeded")
+		}
+		return
+	}
+	apiKey, ok := getApiKeys().Get(key)
+	if !ok {
+		rejectRequest(ctx, 401, "api key is invalid!")
+		return
+	}
+	if relayer && apiKey.Tier != models.ApiKeyTierRelayer {
+		rejectRequest(ctx, 403, fmt.Sprintf("api key %s is not of the relayer tier", apiKey.Name))
+		return
+	}
+	if apiKey.Quota > 0 {
+		ok, remaining, reset := quota.Use("key:"+apiKey.Key, apiKey.Quota, now)
+		ctx.Output.Header("X-Quota-Limit", fmt.Sprintf("%d", apiKey.Quota))
+		ctx.Output.Header("X-Quota-Remaining", fmt.Sprintf("%d", remaining))
+		if !ok {
+			tooManyRequests(ctx, reset, "daily quota exceeded")
+			return
+		}
+	}
+	rate, burst := apiKey.Rate, apiKey.Burst
+	if rate <= 0 {
+		rate = beego.AppConfig.DefaultInt64("ratelimitkeyrate", 50)
+	}
+	if burst <= 0 {
+		burst = beego.AppConfig.DefaultInt64("ratelimitkeyburst", 100)
+	}
+	ok, remaining, wait := limiter.Allow("key:"+apiKey.Key, float64(rate), burst, now)
+	setRateLimitHeaders(ctx, burst, remaining)
+	if !ok {
+		tooManyRequests(ctx, wait, "rate limit exceeded")
+	}
+}
+
+// clientIP returns the ip the requests are limited by, X-Forwarded-For is only honored from the configured proxies.
+func clientIP(ctx *context.Context) string {
+	trustedOnce.Do(func() {
+		trustedProxies = ratelimit.ParseTrustedProxies(beego.AppConfig.String("ratelimittrustedproxies"))
+	})
+	return ratelimit.ClientIP(ctx.Request.RemoteAddr, ctx.Request.Header.Get("X-Forwarded-For"), trustedProxies)
+}
+
+func isRelayerPath(path string) bool {
+	for _, relayerPath := range strings.Split(beego.AppConfig.DefaultString("ratelimitrelayerpaths", "/v1/checkfee/,/v1/checkswapfee/"), ",") {
+		relayerPath = strings.TrimSpace(relayerPath)
+		if relayerPath != "" && strings.TrimSuffix(path, "/") == strings.TrimSuffix(relayerPath, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+func setRateLimitHeaders(ctx *context.Context, burst int64, remaining int64) {
+	ctx.Output.Header("X-RateLimit-Limit", fmt.Sprintf("%d", burst))
+	ctx.Output.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+}
+
+func tooManyRequests(ctx *context.Context, wait time.Duration, reason string) {
+	ctx.Output.Header("Retry-After", fmt.Sprintf("%d", int64(math.Ceil(wait.Seconds()))))
+	ctx.Output.Header("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(wait).Unix()))
+	rejectRequest(ctx, 429, reason)
+}
+
+func rejectRequest(ctx *context.Context, status int, reason string) {
+	ctx.Output.SetStatus(status)
+	ctx.Output.JSON(models.MakeErrorRsp(reason), false, false)
+}
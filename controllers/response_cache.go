@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"encoding/json"
+	"poly-bridge/cache"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/logs"
+)
+
+const (
+	cacheNamespaceToken = "token"
+	cacheNamespaceFee   = "fee"
+)
+
+var (
+	responseCacheOnce sync.Once
+	responseCache     *cache.Cache
+)
+
+// getResponseCache returns the cache of the hot read endpoints. The cached responses are invalidated
+// by the fee snapshot, which watches the versions of the prices, fees and tokens.
+func getResponseCache() *cache.Cache {
+	responseCacheOnce.Do(func() {
+		responseCache = newResponseCache()
+	})
+	return responseCache
+}
+
+func newResponseCache() *cache.Cache {
+	ttl := time.Second * time.Duration(beego.AppConfig.DefaultInt64("cachettl", 60))
+	if store := newRedisStore(); store != nil {
+		return cache.New(store, ttl)
+	}
+	store, err := cache.NewMemoryStore(beego.AppConfig.DefaultInt("cachesize", 10000))
+	if err != nil {
+		panic(err)
+	}
+	return cache.New(store, ttl)
+}
+
+// newRedisStore returns the redis store shared by the instances, nil if the cache backend is not redis.
+func newRedisStore() *cache.RedisStore {
+	if beego.AppConfig.String("cachebackend") != "redis" {
+		return nil
+	}
+	return cache.NewRedisStore(beego.AppConfig.String("cacheredisurl"), beego.AppConfig.String("cacheredispass"),
+		beego.AppConfig.DefaultInt("cacheredisdb", 0), time.Second*time.Duration(beego.AppConfig.DefaultInt64("cacheredistimeout", 3)))
+}
+
+// serveCache writes the cached response of key, it returns false if there is none.
+func serveCache(c *beego.Controller, namespace string, key string) bool {
+	data, ok := getResponseCache().Get(namespace, key)
+	if !ok {
+		return false
+	}
+	c.Data["json"] = json.RawMessage(data)
+	c.ServeJSON()
+	return true
+}
+
+// serveAndCache writes the response and keeps it in the cache.
+func serveAndCache(c *beego.Controller, namespace string, key string, rsp interface{}) {
+	if data, err := json.Marshal(rsp); err == nil {
+		getResponseCache().Set(namespace, key, data)
+	}
+	c.Data["json"] = rsp
+	c.ServeJSON()
+}
+
+func invalidateResponseCache() {
+	if err := getResponseCache().Invalidate(cacheNamespaceToken, cacheNamespaceFee); err != nil {
+		logs.Error("invalidate response cache err: %v", err)
+		return
+	}
+	logs.Info("response cache is invalidated")
+}
@@ -36,10 +36,13 @@ func (c *TokenController) Tokens() {
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 	}
+	key := fmt.Sprintf("tokens_%d", tokensReq.ChainId)
+	if serveCache(&c.Controller, cacheNamespaceToken, key) {
+		return
+	}
 	tokens := make([]*models.Token, 0)
-	db.Where("chain_id = ?", tokensReq.ChainId).Preload("TokenBasic").Preload("TokenMaps").Preload("TokenMaps.DstToken").Find(&tokens)
-	c.Data["json"] = models.MakeTokensRsp(tokens)
-	c.ServeJSON()
+	readDB().Where("chain_id = ?", tokensReq.ChainId).Preload("TokenBasic").Preload("TokenMaps").Preload("TokenMaps.DstToken").Find(&tokens)
+	serveAndCache(&c.Controller, cacheNamespaceToken, key, models.MakeTokensRsp(tokens))
 }
 
 func (c *TokenController) Token() {
@@ -51,7 +54,7 @@ func (c *TokenController) Token() {
 		c.ServeJSON()
 	}
 	token := new(models.Token)
-	res := db.Where("hash = ? and chain_id = ?", tokenReq.Hash, tokenReq.ChainId).Preload("TokenBasic").Preload("TokenMaps").Preload("TokenMaps.DstToken").First(token)
+	res := readDB().Where("hash = ? and chain_id = ?", tokenReq.Hash, tokenReq.ChainId).Preload("TokenBasic").Preload("TokenMaps").Preload("TokenMaps.DstToken").First(token)
 	if res.RowsAffected == 0 {
 		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("token: (%s,%d) does not exist", tokenReq.Hash, tokenReq.ChainId))
 		c.Ctx.ResponseWriter.WriteHeader(400)
@@ -70,8 +73,10 @@ func (c *TokenController) TokenBasics() {
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 	}
+	if serveCache(&c.Controller, cacheNamespaceToken, "tokenbasics") {
+		return
+	}
 	tokenBasics := make([]*models.TokenBasic, 0)
-	db.Model(&models.TokenBasic{}).Preload("Tokens").Find(&tokenBasics)
-	c.Data["json"] = models.MakeTokenBasicsRsp(tokenBasics)
-	c.ServeJSON()
+	readDB().Model(&models.TokenBasic{}).Preload("Tokens").Find(&tokenBasics)
+	serveAndCache(&c.Controller, cacheNamespaceToken, "tokenbasics", models.MakeTokenBasicsRsp(tokenBasics))
 }
@@ -36,16 +36,19 @@ func (c *TokenMapController) TokenMap() {
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 	}
+	key := fmt.Sprintf("tokenmap_%d_%s", tokenMapReq.ChainId, tokenMapReq.Hash)
+	if serveCache(&c.Controller, cacheNamespaceToken, key) {
+		return
+	}
 	tokenMaps := make([]*models.TokenMap, 0)
-	res := db.Where("src_chain_id = ? and src_token_hash = ?", tokenMapReq.ChainId, tokenMapReq.Hash).Preload("SrcToken").Preload("DstToken").Find(&tokenMaps)
+	res := readDB().Where("src_chain_id = ? and src_token_hash = ?", tokenMapReq.ChainId, tokenMapReq.Hash).Preload("SrcToken").Preload("DstToken").Find(&tokenMaps)
 	if res.RowsAffected == 0 {
 		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("token map: (%s,%d) does not exist", tokenMapReq.Hash, tokenMapReq.ChainId))
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 		return
 	}
-	c.Data["json"] = models.MakeTokenMapsRsp(tokenMaps)
-	c.ServeJSON()
+	serveAndCache(&c.Controller, cacheNamespaceToken, key, models.MakeTokenMapsRsp(tokenMaps))
 }
 
 func (c *TokenMapController) TokenMapReverse() {
@@ -56,14 +59,17 @@ func (c *TokenMapController) TokenMapReverse() {
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 	}
+	key := fmt.Sprintf("tokenmapreverse_%d_%s", tokenMapReq.ChainId, tokenMapReq.Hash)
+	if serveCache(&c.Controller, cacheNamespaceToken, key) {
+		return
+	}
 	tokenMaps := make([]*models.TokenMap, 0)
-	res := db.Where("dst_chain_id = ? and dst_token_hash = ?", tokenMapReq.ChainId, tokenMapReq.Hash).Preload("SrcToken").Preload("DstToken").Find(&tokenMaps)
+	res := readDB().Where("dst_chain_id = ? and dst_token_hash = ?", tokenMapReq.ChainId, tokenMapReq.Hash).Preload("SrcToken").Preload("DstToken").Find(&tokenMaps)
 	if res.RowsAffected == 0 {
 		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("token map: (%s,%d) does not exist", tokenMapReq.Hash, tokenMapReq.ChainId))
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 		return
 	}
-	c.Data["json"] = models.MakeTokenMapsRsp(tokenMaps)
-	c.ServeJSON()
+	serveAndCache(&c.Controller, cacheNamespaceToken, key, models.MakeTokenMapsRsp(tokenMaps))
 }
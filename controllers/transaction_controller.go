@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/astaxie/beego"
 	"poly-bridge/basedef"
+	"poly-bridge/chainaddr"
 	"poly-bridge/models"
 )
 
@@ -38,9 +39,9 @@ func (c *TransactionController) Transactions() {
 		c.ServeJSON()
 	}
 	transactions := make([]*models.WrapperTransaction, 0)
-	db.Limit(transactionsReq.PageSize).Offset(transactionsReq.PageSize * transactionsReq.PageNo).Order("time asc").Find(&transactions)
+	readDB().Limit(transactionsReq.PageSize).Offset(transactionsReq.PageSize * transactionsReq.PageNo).Order("time asc").Find(&transactions)
 	var transactionNum int64
-	db.Model(&models.WrapperTransaction{}).Count(&transactionNum)
+	readDB().Model(&models.WrapperTransaction{}).Count(&transactionNum)
 	c.Data["json"] = models.MakeWrapperTransactionsRsp(transactionsReq.PageSize, transactionsReq.PageNo, (int(transactionNum)+transactionsReq.PageSize-1)/transactionsReq.PageSize,
 		int(transactionNum), transactions)
 	c.ServeJSON()
@@ -55,9 +56,9 @@ func (c *TransactionController) PolyTransactions() {
 		c.ServeJSON()
 	}
 	transactions := make([]*models.PolyTransaction, 0)
-	db.Limit(transactionsReq.PageSize).Offset(transactionsReq.PageSize * transactionsReq.PageNo).Order("time asc").Find(&transactions)
+	readDB().Limit(transactionsReq.PageSize).Offset(transactionsReq.PageSize * transactionsReq.PageNo).Order("time asc").Find(&transactions)
 	var transactionNum int64
-	db.Model(&models.PolyTransaction{}).Count(&transactionNum)
+	readDB().Model(&models.PolyTransaction{}).Count(&transactionNum)
 	c.Data["json"] = models.MakePolyTransactionsRsp(transactionsReq.PageSize, transactionsReq.PageNo, (int(transactionNum)+transactionsReq.PageSize-1)/transactionsReq.PageSize,
 		int(transactionNum), transactions)
 	c.ServeJSON()
@@ -70,10 +71,15 @@ func (c *TransactionController) TransactionsOfAddress() {
 		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
+		return
+	}
+	addresses := make([]string, 0)
+	for _, address := range transactionsOfAddressReq.Addresses {
+		addresses = append(addresses, chainaddr.Hashes(transactionsOfAddressReq.ChainId, address)...)
 	}
 	srcPolyDstRelations := make([]*models.SrcPolyDstRelation, 0)
-	db.Table("(?) as u", db.Model(&models.SrcTransfer{}).Select("tx_hash as hash, asset as asset, src_transfers.chain_id as chain_id").Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-		Where("`from` in ? or src_transfers.dst_user in ?", transactionsOfAddressReq.Addresses, transactionsOfAddressReq.Addresses)).
+	readDB().Table("(?) as u", readDB().Model(&models.SrcTransfer{}).Select("tx_hash as hash, asset as asset, src_transfers.chain_id as chain_id").Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
+		Where("`from` in ? or src_transfers.dst_user in ?", addresses, addresses)).
 		Where("src_transactions.standard = ?", 0).
 		Select("src_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash, src_transactions.chain_id as chain_id, u.asset as token_hash").
 		Joins("inner join tokens on u.chain_id = tokens.chain_id and u.asset = tokens.hash").
@@ -92,9 +98,9 @@ func (c *TransactionController) TransactionsOfAddress() {
 		Order("src_transactions.time desc").
 		Find(&srcPolyDstRelations)
 	var transactionNum int64
-	db.Model(&models.SrcTransfer{}).Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").Where("`from` in ? or src_transfers.dst_user in ?", transactionsOfAddressReq.Addresses, transactionsOfAddressReq.Addresses).Count(&transactionNum)
+	readDB().Model(&models.SrcTransfer{}).Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").Where("`from` in ? or src_transfers.dst_user in ?", addresses, addresses).Count(&transactionNum)
 	chains := make([]*models.Chain, 0)
-	db.Model(&models.Chain{}).Find(&chains)
+	readDB().Model(&models.Chain{}).Find(&chains)
 	chainsMap := make(map[uint64]*models.Chain)
 	for _, chain := range chains {
 		chainsMap[*chain.ChainId] = chain
@@ -106,7 +112,7 @@ func (c *TransactionController) TransactionsOfAddress() {
 
 func (c *TransactionController) getTransactionByHash(hash string) (*models.SrcPolyDstRelation, error) {
 	srcPolyDstRelation := new(models.SrcPolyDstRelation)
-	res := db.Table("src_transactions").
+	res := readDB().Table("src_transactions").
 		Select("src_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash, src_transactions.chain_id as chain_id, src_transfers.asset as token_hash").
 		Where("src_transactions.hash = ?", hash).
 		Where("src_transactions.standard = ?", 0).
@@ -131,7 +137,7 @@ func (c *TransactionController) getTransactionByHash(hash string) (*models.SrcPo
 
 func (c *TransactionController) getTransactionByDstHash(hash string) (*models.SrcPolyDstRelation, error) {
 	srcPolyDstRelation := new(models.SrcPolyDstRelation)
-	res := db.Table("dst_transactions").
+	res := readDB().Table("dst_transactions").
 		Select("src_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash, src_transactions.chain_id as chain_id, src_transfers.asset as token_hash").
 		Where("dst_transactions.hash = ?", hash).
 		Where("dst_transactions.standard = ?", 0).
@@ -171,7 +177,7 @@ func (c *TransactionController) TransactionOfHash() {
 	}
 	if srcPolyDstRelation.SrcTransaction.DstChainId != basedef.O3_CROSSCHAIN_ID || srcPolyDstRelation.DstTransaction == nil {
 		chains := make([]*models.Chain, 0)
-		db.Model(&models.Chain{}).Find(&chains)
+		readDB().Model(&models.Chain{}).Find(&chains)
 		chainsMap := make(map[uint64]*models.Chain)
 		for _, chain := range chains {
 			chainsMap[*chain.ChainId] = chain
@@ -190,7 +196,7 @@ func (c *TransactionController) TransactionOfHash() {
 	srcPolyDstRelation.DstHash = srcPolyDstRelation2.DstHash
 	srcPolyDstRelation.DstTransaction = srcPolyDstRelation2.DstTransaction
 	chains := make([]*models.Chain, 0)
-	db.Model(&models.Chain{}).Find(&chains)
+	readDB().Model(&models.Chain{}).Find(&chains)
 	chainsMap := make(map[uint64]*models.Chain)
 	for _, chain := range chains {
 		chainsMap[*chain.ChainId] = chain
@@ -229,7 +235,7 @@ func (c *TransactionController) TransactionOfCurve() {
 		return
 	}
 	chains := make([]*models.Chain, 0)
-	db.Model(&models.Chain{}).Find(&chains)
+	readDB().Model(&models.Chain{}).Find(&chains)
 	chainsMap := make(map[uint64]*models.Chain)
 	for _, chain := range chains {
 		chainsMap[*chain.ChainId] = chain
@@ -247,9 +253,9 @@ func (c *TransactionController) TransactionsOfState() {
 		c.ServeJSON()
 	}
 	transactions := make([]*models.WrapperTransaction, 0)
-	db.Where("status = ?", transactionsOfStateReq.State).Limit(transactionsOfStateReq.PageSize).Offset(transactionsOfStateReq.PageSize * transactionsOfStateReq.PageNo).Order("time asc").Find(&transactions)
+	readDB().Where("status = ?", transactionsOfStateReq.State).Limit(transactionsOfStateReq.PageSize).Offset(transactionsOfStateReq.PageSize * transactionsOfStateReq.PageNo).Order("time asc").Find(&transactions)
 	var transactionNum int64
-	db.Model(&models.WrapperTransaction{}).Where("status = ?", transactionsOfStateReq.State).Count(&transactionNum)
+	readDB().Model(&models.WrapperTransaction{}).Where("status = ?", transactionsOfStateReq.State).Count(&transactionNum)
 	c.Data["json"] = models.MakeTransactionsOfStateRsp(transactionsOfStateReq.PageSize, transactionsOfStateReq.PageNo,
 		(int(transactionNum)+transactionsOfStateReq.PageSize-1)/transactionsOfStateReq.PageSize, int(transactionNum), transactions)
 	c.ServeJSON()
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
@@ -218,6 +219,7 @@ func (dao *BridgeDao) getTokenMapsFromToken(tokenBasics []*models.TokenBasic) []
 						SrcTokenHash: tokenSrc.Hash,
 						DstChainId:   tokenDst.ChainId,
 						DstTokenHash: tokenDst.Hash,
+						Standard:     tokenBasic.Standard,
 						Property:     1,
 					})
 				}
@@ -272,6 +274,70 @@ func (dao *BridgeDao) RemoveToken(token string) error {
 	return nil
 }
 
+func (dao *BridgeDao) GetNFTAssets(chainId uint64) ([]string, error) {
+	assets := make([]string, 0)
+	res := dao.db.Model(&models.Token{}).Where("chain_id = ? and standard in ? and property = ?", chainId, models.NFTStandards, 1).
+		Pluck("hash", &assets)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return assets, nil
+}
+
+// UpdateNFTOwnerships replaces the owner of the transferred ERC721 tokens and applies the ERC1155 balance
+// changes. A balance which is updated at the height of the block already is skipped, so a block can be
+// handled again.
+func (dao *BridgeDao) UpdateNFTOwnerships(transfers []*models.NFTTransferEvent) error {
+	if len(transfers) == 0 || dao.backup {
+		return nil
+	}
+	owners, balances := models.NFTOwnershipChanges(transfers)
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		for _, owner := range owners {
+			res := tx.Where("chain_id = ? and asset = ? and token_id = ?", owner.ChainId, owner.Asset, owner.TokenId).
+				Delete(&models.NFTOwnership{})
+			if res.Error != nil {
+				return res.Error
+			}
+			if owner.Owner == models.NFTZeroAddress {
+				continue
+			}
+			if res = tx.Create(owner); res.Error != nil {
+				return res.Error
+			}
+		}
+		for _, balance := range balances {
+			current := new(models.NFTOwnership)
+			res := tx.Where("chain_id = ? and asset = ? and token_id = ? and owner = ?",
+				balance.ChainId, balance.Asset, balance.TokenId, balance.Owner).Find(current)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected > 0 {
+				if current.Height >= balance.Height {
+					continue
+				}
+				balance.Quantity.Add(&balance.Quantity.Int, &current.Quantity.Int)
+			}
+			if balance.Quantity.Sign() <= 0 {
+				if res.RowsAffected == 0 {
+					continue
+				}
+				res = tx.Delete(current)
+			} else {
+				res = tx.Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "chain_id"}, {Name: "asset"}, {Name: "token_id"}, {Name: "owner"}},
+					DoUpdates: clause.AssignmentColumns([]string{"quantity", "height"}),
+				}).Create(balance)
+			}
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+		return nil
+	})
+}
+
 func (dao *BridgeDao) Name() string {
 	return basedef.SERVER_POLY_BRIDGE
 }
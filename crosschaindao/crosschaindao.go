@@ -39,6 +39,12 @@ type CrossChainDao interface {
 	Name() string
 }
 
+// NFTOwnershipDao is implemented by the daos which index the owners of the NFT assets.
+type NFTOwnershipDao interface {
+	GetNFTAssets(chainId uint64) ([]string, error)
+	UpdateNFTOwnerships(transfers []*models.NFTTransferEvent) error
+}
+
 func NewCrossChainDao(server string, backup bool, dbCfg *conf.DBConfig) CrossChainDao {
 	if server == basedef.SERVER_POLY_SWAP {
 		return swapdao.NewSwapDao(dbCfg, backup)
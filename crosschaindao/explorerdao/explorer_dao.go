@@ -20,10 +20,12 @@ package explorerdao
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/astaxie/beego/logs"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
+	"poly-bridge/chainaddr"
 	"poly-bridge/conf"
 	"poly-bridge/models"
 )
@@ -178,6 +180,15 @@ func NewExplorerDao(dbCfg *conf.DBConfig, backup bool) *ExplorerDao {
 	return explorerDao
 }
 
+func addressOf(chainId uint64, hash string) string {
+	address, err := chainaddr.FromHash(chainId, hash)
+	if err != nil {
+		logs.Error("hash %s of chain %d is not an address: %v", hash, chainId, err)
+		return hash
+	}
+	return address
+}
+
 func (dao *ExplorerDao) UpdateEvents(chain *models.Chain, wrapperTransactions []*models.WrapperTransaction, srcTransactions []*models.SrcTransaction, polyTransactions []*models.PolyTransaction, dstTransactions []*models.DstTransaction) error {
 	if srcTransactions != nil && len(srcTransactions) > 0 {
 		srcTransactionsJson, err := json.Marshal(srcTransactions)
@@ -190,11 +201,11 @@ func (dao *ExplorerDao) UpdateEvents(chain *models.Chain, wrapperTransactions []
 			return err
 		}
 		for _, transaction := range newSrcTransactions {
-			transaction.User = basedef.Hash2Address(transaction.ChainId, transaction.User)
+			transaction.User = addressOf(transaction.ChainId, transaction.User)
 			if transaction.SrcTransfer != nil {
-				transaction.SrcTransfer.From = basedef.Hash2Address(transaction.SrcTransfer.ChainId, transaction.SrcTransfer.From)
-				transaction.SrcTransfer.To = basedef.Hash2Address(transaction.SrcTransfer.ChainId, transaction.SrcTransfer.To)
-				transaction.SrcTransfer.DstUser = basedef.Hash2Address(transaction.SrcTransfer.DstChainId, transaction.SrcTransfer.DstUser)
+				transaction.SrcTransfer.From = addressOf(transaction.SrcTransfer.ChainId, transaction.SrcTransfer.From)
+				transaction.SrcTransfer.To = addressOf(transaction.SrcTransfer.ChainId, transaction.SrcTransfer.To)
+				transaction.SrcTransfer.DstUser = addressOf(transaction.SrcTransfer.DstChainId, transaction.SrcTransfer.DstUser)
 			}
 			if transaction.ChainId == basedef.ETHEREUM_CROSSCHAIN_ID {
 				transaction.Hash, transaction.Key = transaction.Key, transaction.Hash
@@ -235,8 +246,8 @@ func (dao *ExplorerDao) UpdateEvents(chain *models.Chain, wrapperTransactions []
 		}
 		for _, transaction := range newDstTransactions {
 			if transaction.DstTransfer != nil {
-				transaction.DstTransfer.From = basedef.Hash2Address(transaction.DstTransfer.ChainId, transaction.DstTransfer.From)
-				transaction.DstTransfer.To = basedef.Hash2Address(transaction.DstTransfer.ChainId, transaction.DstTransfer.To)
+				transaction.DstTransfer.From = addressOf(transaction.DstTransfer.ChainId, transaction.DstTransfer.From)
+				transaction.DstTransfer.To = addressOf(transaction.DstTransfer.ChainId, transaction.DstTransfer.To)
 			}
 		}
 		res := dao.db.Save(newDstTransactions)
@@ -19,6 +19,7 @@ package bridgeeffect
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/astaxie/beego/logs"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -35,6 +36,10 @@ type BridgeEffect struct {
 	db     *gorm.DB
 	chains []*models.Chain
 	time   int64
+	// retryId is the id of the last unpriced fee reconciliation retried, the retries go round the unpriced ones.
+	retryId int64
+	// downsampledTime is the time until which the price history is downsampled
+	downsampledTime int64
 }
 
 func NewBridgeEffect(cfg *conf.EventEffectConfig, dbCfg *conf.DBConfig) *BridgeEffect {
@@ -81,6 +86,14 @@ func (eff *BridgeEffect) Effect() error {
 	if err != nil {
 		logs.Error("check chain listening- err: %s", err)
 	}
+	err = eff.reconcileFees()
+	if err != nil {
+		logs.Error("reconcile fees- err: %s", err)
+	}
+	err = eff.downsamplePriceHistory()
+	if err != nil {
+		logs.Error("downsample price history- err: %s", err)
+	}
 	return nil
 }
 func (eff *BridgeEffect) Name() string {
@@ -205,3 +218,165 @@ func (eff *BridgeEffect) checkChainListening() error {
 	eff.time = now
 	return nil
 }
+
+func (eff *BridgeEffect) reconcileFees() error {
+	wrapperPolyDstRelations := make([]*models.SrcPolyDstRelation, 0)
+	res := eff.db.Table("wrapper_transactions").
+		Select("wrapper_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash").
+		Where("wrapper_transactions.status = ? and fee_reconciliations.id is null", basedef.STATE_FINISHED).
+		Joins("inner join poly_transactions on wrapper_transactions.hash = poly_transactions.src_hash").
+		Joins("inner join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
+		Joins("left join fee_reconciliations on wrapper_transactions.hash = fee_reconciliations.src_hash").
+		Order("wrapper_transactions.time asc").Limit(200).
+		Preload("WrapperTransaction").Preload("DstTransaction").Find(&wrapperPolyDstRelations)
+	if res.Error != nil {
+		return res.Error
+	}
+	reconciliations := make([]*models.FeeReconciliation, 0)
+	for _, relation := range wrapperPolyDstRelations {
+		if relation.WrapperTransaction == nil || relation.DstTransaction == nil {
+			continue
+		}
+		reconciliations = append(reconciliations, &models.FeeReconciliation{SrcHash: relation.SrcHash})
+	}
+	if err := eff.reconcile(reconciliations, wrapperPolyDstRelations); err != nil {
+		return err
+	}
+	return eff.retryUnpricedFees()
+}
+
+// retryUnpricedFees reconciles again the fees saved before the prices of their tokens were known.
+func (eff *BridgeEffect) retryUnpricedFees() error {
+	unpriced := make([]*models.FeeReconciliation, 0)
+	res := eff.db.Where("ind = 0 and id > ?", eff.retryId).Order("id asc").Limit(200).Find(&unpriced)
+	if res.Error != nil {
+		return res.Error
+	}
+	if len(unpriced) < 200 {
+		eff.retryId = 0
+	} else {
+		eff.retryId = unpriced[len(unpriced)-1].Id
+	}
+	if len(unpriced) == 0 {
+		return nil
+	}
+	srcHashes := make([]string, 0, len(unpriced))
+	dstHashes := make([]string, 0, len(unpriced))
+	for _, reconciliation := range unpriced {
+		srcHashes = append(srcHashes, reconciliation.SrcHash)
+		dstHashes = append(dstHashes, reconciliation.DstHash)
+	}
+	wrapperTransactions := make([]*models.WrapperTransaction, 0)
+	eff.db.Where("hash in ?", srcHashes).Find(&wrapperTransactions)
+	dstTransactions := make([]*models.DstTransaction, 0)
+	eff.db.Where("hash in ?", dstHashes).Find(&dstTransactions)
+	hash2Wrapper := make(map[string]*models.WrapperTransaction, len(wrapperTransactions))
+	for _, wrapperTransaction := range wrapperTransactions {
+		hash2Wrapper[wrapperTransaction.Hash] = wrapperTransaction
+	}
+	hash2Dst := make(map[string]*models.DstTransaction, len(dstTransactions))
+	for _, dstTransaction := range dstTransactions {
+		hash2Dst[dstTransaction.Hash] = dstTransaction
+	}
+	relations := make([]*models.SrcPolyDstRelation, 0, len(unpriced))
+	for _, reconciliation := range unpriced {
+		relations = append(relations, &models.SrcPolyDstRelation{
+			SrcHash:            reconciliation.SrcHash,
+			WrapperTransaction: hash2Wrapper[reconciliation.SrcHash],
+			DstHash:            reconciliation.DstHash,
+			DstTransaction:     hash2Dst[reconciliation.DstHash],
+		})
+	}
+	return eff.reconcile(unpriced, relations)
+}
+
+// reconcile saves the reconciliations of the relations, previous is the saved reconciliation of each relation
+// (with a zero id when there is none), and only the priced ones replace a saved reconciliation.
+func (eff *BridgeEffect) reconcile(previous []*models.FeeReconciliation, relations []*models.SrcPolyDstRelation) error {
+	if len(relations) == 0 {
+		return nil
+	}
+	hash2Previous := make(map[string]*models.FeeReconciliation, len(previous))
+	for _, reconciliation := range previous {
+		hash2Previous[reconciliation.SrcHash] = reconciliation
+	}
+	chainFees := make([]*models.ChainFee, 0)
+	eff.db.Preload("TokenBasic").Find(&chainFees)
+	chain2CostTokens := make(map[uint64]*models.TokenBasic)
+	for _, chainFee := range chainFees {
+		chain2CostTokens[chainFee.ChainId] = chainFee.TokenBasic
+	}
+	feeTokenHashes := make([]string, 0, len(relations))
+	tokenBasicNames := make([]string, 0)
+	from, to := int64(0), int64(0)
+	addTime := func(t uint64) {
+		if from == 0 || int64(t) < from {
+			from = int64(t)
+		}
+		if int64(t) > to {
+			to = int64(t)
+		}
+	}
+	for _, relation := range relations {
+		if relation.WrapperTransaction == nil || relation.DstTransaction == nil {
+			continue
+		}
+		feeTokenHashes = append(feeTokenHashes, relation.WrapperTransaction.FeeTokenHash)
+		addTime(relation.WrapperTransaction.Time)
+		addTime(relation.DstTransaction.Time)
+		if costToken := chain2CostTokens[relation.DstTransaction.ChainId]; costToken != nil {
+			tokenBasicNames = append(tokenBasicNames, costToken.Name)
+		}
+	}
+	tokens := make([]*models.Token, 0)
+	if len(feeTokenHashes) > 0 {
+		res := eff.db.Where("hash in ?", feeTokenHashes).Find(&tokens)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+	feeTokens := make(map[string]*models.Token, len(tokens))
+	for _, token := range tokens {
+		feeTokens[fmt.Sprintf("%d:%s", token.ChainId, token.Hash)] = token
+		tokenBasicNames = append(tokenBasicNames, token.TokenBasicName)
+	}
+	prices, err := eff.loadPriceHistory(tokenBasicNames, from, to)
+	if err != nil {
+		return err
+	}
+	reconciliations := make([]*models.FeeReconciliation, 0)
+	for _, relation := range relations {
+		wrapperTransaction := relation.WrapperTransaction
+		dstTransaction := relation.DstTransaction
+		old, ok := hash2Previous[relation.SrcHash]
+		if wrapperTransaction == nil || dstTransaction == nil || !ok {
+			continue
+		}
+		feeToken := feeTokens[fmt.Sprintf("%d:%s", wrapperTransaction.SrcChainId, wrapperTransaction.FeeTokenHash)]
+		var feePrice, costPrice *models.TokenPriceHistory
+		if feeToken != nil {
+			feePrice = prices.at(feeToken.TokenBasicName, int64(wrapperTransaction.Time))
+		}
+		costToken := chain2CostTokens[dstTransaction.ChainId]
+		if costToken != nil {
+			costPrice = prices.at(costToken.Name, int64(dstTransaction.Time))
+		}
+		reconciliation := models.MakeFeeReconciliation(wrapperTransaction, dstTransaction, feeToken, costToken, feePrice, costPrice)
+		if reconciliation.Ind != 1 {
+			if old.Id != 0 {
+				continue
+			}
+			logs.Warn("fee of transaction %s can not be reconciled, fee token: %s, cost token: %s",
+				wrapperTransaction.Hash, reconciliation.FeeTokenBasicName, reconciliation.CostTokenBasicName)
+		}
+		reconciliation.Id = old.Id
+		reconciliations = append(reconciliations, reconciliation)
+	}
+	if len(reconciliations) > 0 {
+		res := eff.db.Save(reconciliations)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
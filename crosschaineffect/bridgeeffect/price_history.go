@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bridgeeffect
+
+import (
+	"fmt"
+	"poly-bridge/models"
+	"sort"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+)
+
+const (
+	// defaultPriceHistoryDays is the days of the price history kept at full resolution
+	defaultPriceHistoryDays = 7
+	// priceHistoryInterval is the interval of the prices kept after PriceHistoryDays
+	priceHistoryInterval = 3600
+)
+
+// priceHistory is the price history of the token basics sorted by time, which is loaded once for the
+// reconciliations of a round.
+type priceHistory map[string][]*models.TokenPriceHistory
+
+// at returns the latest price of the token at the time, or the earliest one when the history starts later.
+func (h priceHistory) at(tokenBasicName string, timestamp int64) *models.TokenPriceHistory {
+	histories := h[tokenBasicName]
+	if len(histories) == 0 {
+		return nil
+	}
+	i := sort.Search(len(histories), func(i int) bool {
+		return histories[i].Time > timestamp
+	})
+	if i == 0 {
+		return histories[0]
+	}
+	return histories[i-1]
+}
+
+func (h priceHistory) add(histories []*models.TokenPriceHistory) {
+	for _, history := range histories {
+		h[history.TokenBasicName] = append(h[history.TokenBasicName], history)
+	}
+}
+
+// loadPriceHistory loads the prices of the token basics between from and to, with the latest price before from
+// and, for the tokens which have no price until to, the earliest price after it.
+func (eff *BridgeEffect) loadPriceHistory(tokenBasicNames []string, from int64, to int64) (priceHistory, error) {
+	prices := make(priceHistory)
+	if len(tokenBasicNames) == 0 {
+		return prices, nil
+	}
+	before := make([]*models.TokenPriceHistory, 0)
+	res := eff.db.Table("token_price_histories").
+		Joins("inner join (select token_basic_name, max(time) as time from token_price_histories where token_basic_name in ? and time < ? group by token_basic_name) l "+
+			"on token_price_histories.token_basic_name = l.token_basic_name and token_price_histories.time = l.time", tokenBasicNames, from).
+		Select("token_price_histories.*").Find(&before)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	between := make([]*models.TokenPriceHistory, 0)
+	res = eff.db.Where("token_basic_name in ? and time >= ? and time <= ?", tokenBasicNames, from, to).Order("time asc").Find(&between)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	prices.add(before)
+	prices.add(between)
+	unpriced := make([]string, 0)
+	for _, name := range tokenBasicNames {
+		if len(prices[name]) == 0 {
+			unpriced = append(unpriced, name)
+		}
+	}
+	if len(unpriced) > 0 {
+		after := make([]*models.TokenPriceHistory, 0)
+		res = eff.db.Table("token_price_histories").
+			Joins("inner join (select token_basic_name, min(time) as time from token_price_histories where token_basic_name in ? and time > ? group by token_basic_name) e "+
+				"on token_price_histories.token_basic_name = e.token_basic_name and token_price_histories.time = e.time", unpriced, to).
+			Select("token_price_histories.*").Find(&after)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		prices.add(after)
+	}
+	for _, histories := range prices {
+		sort.SliceStable(histories, func(i, j int) bool {
+			return histories[i].Time < histories[j].Time
+		})
+	}
+	return prices, nil
+}
+
+// downsamplePriceHistory keeps one price an hour of the prices older than PriceHistoryDays, as the coin price
+// listener saves a price of every token basic on every update. It runs once an hour, from the time it reached.
+func (eff *BridgeEffect) downsamplePriceHistory() error {
+	days := eff.cfg.PriceHistoryDays
+	if days == 0 {
+		days = defaultPriceHistoryDays
+	}
+	end := time.Now().Unix() - days*24*3600
+	end -= end % priceHistoryInterval
+	if end <= eff.downsampledTime {
+		return nil
+	}
+	res := eff.db.Exec(fmt.Sprintf("delete h from token_price_histories h inner join "+
+		"(select token_basic_name, floor(time / %d) as slot, min(id) as id from token_price_histories where time >= ? and time < ? group by token_basic_name, floor(time / %d)) k "+
+		"on h.token_basic_name = k.token_basic_name and floor(h.time / %d) = k.slot and h.id != k.id where h.time >= ? and h.time < ?",
+		priceHistoryInterval, priceHistoryInterval, priceHistoryInterval), eff.downsampledTime, end, eff.downsampledTime, end)
+	if res.Error != nil {
+		return res.Error
+	}
+	logs.Info("downsample price history before %d, %d prices are deleted", end, res.RowsAffected)
+	eff.downsampledTime = end
+	return nil
+}
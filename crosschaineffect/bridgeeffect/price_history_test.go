@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bridgeeffect
+
+import (
+	"poly-bridge/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceHistoryAt(t *testing.T) {
+	prices := make(priceHistory)
+	prices.add([]*models.TokenPriceHistory{
+		{TokenBasicName: "ETH", Price: 100, Time: 100},
+		{TokenBasicName: "ETH", Price: 200, Time: 200},
+		{TokenBasicName: "ETH", Price: 300, Time: 300},
+		{TokenBasicName: "BNB", Price: 50, Time: 500},
+	})
+	for _, v := range []struct {
+		name  string
+		time  int64
+		price int64
+	}{
+		{"ETH", 250, 200},
+		{"ETH", 200, 200},
+		{"ETH", 1000, 300},
+		{"ETH", 50, 100},
+		{"BNB", 100, 50},
+	} {
+		assert.Equal(t, v.price, prices.at(v.name, v.time).Price, "%s %d", v.name, v.time)
+	}
+	assert.Nil(t, prices.at("HT", 100))
+}
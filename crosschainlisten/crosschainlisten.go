@@ -22,6 +22,7 @@ import (
 	"github.com/astaxie/beego/logs"
 	"math"
 	"poly-bridge/basedef"
+	"poly-bridge/chainaddr"
 	"poly-bridge/conf"
 	"poly-bridge/crosschaindao"
 	"poly-bridge/crosschainlisten/ethereumlisten"
@@ -42,6 +43,9 @@ func StartCrossChainListen(server string, backup bool, listenCfg []*conf.ChainLi
 		panic("server is not valid")
 	}
 	for i, cfg := range listenCfg {
+		if cfg.AddressPrefix != "" {
+			chainaddr.SetBech32Prefix(cfg.ChainId, cfg.AddressPrefix)
+		}
 		chainHandle := NewChainHandle(cfg)
 		if chainHandle == nil {
 			panic(fmt.Sprintf("chain %d handler is invalid", cfg.ChainId))
@@ -70,6 +74,11 @@ type ChainHandle interface {
 	GetDefer() uint64
 }
 
+// NFTTransferHandle is implemented by the chains which index the owners of the NFT assets from their Transfer events.
+type NFTTransferHandle interface {
+	HandleNFTTransfers(height uint64, assets []string) ([]*models.NFTTransferEvent, error)
+}
+
 func NewChainHandle(chainListenConfig *conf.ChainListenConfig) ChainHandle {
 	if chainListenConfig.ChainId == basedef.ETHEREUM_CROSSCHAIN_ID {
 		return ethereumlisten.NewEthereumChainListen(chainListenConfig)
@@ -173,12 +182,22 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 				continue
 			}
 			logs.Info("ListenChain - chain %s latest height is %d, listen height: %d", ccl.handle.GetChainName(), height, chain.Height)
+			nftAssets, err := ccl.getNFTAssets()
+			if err != nil {
+				logs.Error("ListenChain - cannot get chain %s nft assets, err: %v", ccl.handle.GetChainName(), err)
+				continue
+			}
 			for chain.Height < height-ccl.handle.GetDefer() {
 				wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, err := ccl.handle.HandleNewBlock(chain.Height + 1)
 				if err != nil {
 					logs.Error("HandleNewBlock %d err: %v", chain.Height+1, err)
 					break
 				}
+				err = ccl.handleNFTTransfers(chain.Height+1, nftAssets)
+				if err != nil {
+					logs.Error("HandleNFTTransfers %d err: %v", chain.Height+1, err)
+					break
+				}
 				chain.Height += 1
 				err = ccl.db.UpdateEvents(chain, wrapperTransactions, srcTransactions, polyTransactions, dstTransactions)
 				if err != nil {
@@ -193,3 +212,32 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 		}
 	}
 }
+
+func (ccl *CrossChainListen) getNFTAssets() ([]string, error) {
+	if _, ok := ccl.handle.(NFTTransferHandle); !ok {
+		return nil, nil
+	}
+	dao, ok := ccl.db.(crosschaindao.NFTOwnershipDao)
+	if !ok {
+		return nil, nil
+	}
+	return dao.GetNFTAssets(ccl.handle.GetChainId())
+}
+
+// handleNFTTransfers updates the owners of the NFTs transferred in the block, it is done before the chain height
+// is updated, so a failed block is handled again.
+func (ccl *CrossChainListen) handleNFTTransfers(height uint64, assets []string) error {
+	handle, ok := ccl.handle.(NFTTransferHandle)
+	if !ok || len(assets) == 0 {
+		return nil
+	}
+	dao, ok := ccl.db.(crosschaindao.NFTOwnershipDao)
+	if !ok {
+		return nil
+	}
+	transfers, err := handle.HandleNFTTransfers(height, assets)
+	if err != nil {
+		return err
+	}
+	return dao.UpdateNFTOwnerships(transfers)
+}
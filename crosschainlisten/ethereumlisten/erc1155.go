@@ -0,0 +1,202 @@
+package ethereumlisten
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"poly-bridge/models"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// erc1155EventsAbi has the ERC1155 transfer events and the events of the ERC1155 lock proxy,
+// which are the NFT lock proxy events with the quantity of the token.
+const erc1155EventsAbi = `[
+	{"anonymous":false,"name":"TransferSingle","type":"event","inputs":[
+		{"indexed":true,"name":"operator","type":"address"},
+		{"indexed":true,"name":"from","type":"address"},
+		{"indexed":true,"name":"to","type":"address"},
+		{"indexed":false,"name":"id","type":"uint256"},
+		{"indexed":false,"name":"value","type":"uint256"}]},
+	{"anonymous":false,"name":"TransferBatch","type":"event","inputs":[
+		{"indexed":true,"name":"operator","type":"address"},
+		{"indexed":true,"name":"from","type":"address"},
+		{"indexed":true,"name":"to","type":"address"},
+		{"indexed":false,"name":"ids","type":"uint256[]"},
+		{"indexed":false,"name":"values","type":"uint256[]"}]},
+	{"anonymous":false,"name":"LockEvent","type":"event","inputs":[
+		{"indexed":false,"name":"fromAssetHash","type":"address"},
+		{"indexed":false,"name":"fromAddress","type":"address"},
+		{"indexed":false,"name":"toAssetHash","type":"bytes"},
+		{"indexed":false,"name":"toAddress","type":"bytes"},
+		{"indexed":false,"name":"toChainId","type":"uint64"},
+		{"indexed":false,"name":"tokenId","type":"uint256"},
+		{"indexed":false,"name":"amount","type":"uint256"}]},
+	{"anonymous":false,"name":"UnlockEvent","type":"event","inputs":[
+		{"indexed":false,"name":"toAssetHash","type":"address"},
+		{"indexed":false,"name":"toAddress","type":"address"},
+		{"indexed":false,"name":"tokenId","type":"uint256"},
+		{"indexed":false,"name":"amount","type":"uint256"}]}
+]`
+
+var (
+	erc1155ABI                 abi.ABI
+	erc1155TransferSingleTopic common.Hash
+	erc1155TransferBatchTopic  common.Hash
+	erc1155LockTopic           common.Hash
+	erc1155UnlockTopic         common.Hash
+)
+
+func init() {
+	var err error
+	erc1155ABI, err = abi.JSON(strings.NewReader(erc1155EventsAbi))
+	if err != nil {
+		panic(err)
+	}
+	erc1155TransferSingleTopic = erc1155ABI.Events["TransferSingle"].ID
+	erc1155TransferBatchTopic = erc1155ABI.Events["TransferBatch"].ID
+	erc1155LockTopic = erc1155ABI.Events["LockEvent"].ID
+	erc1155UnlockTopic = erc1155ABI.Events["UnlockEvent"].ID
+}
+
+type erc1155TransferSingle struct {
+	Id    *big.Int
+	Value *big.Int
+}
+
+type erc1155TransferBatch struct {
+	Ids    []*big.Int
+	Values []*big.Int
+}
+
+type erc1155LockEvent struct {
+	FromAssetHash common.Address
+	FromAddress   common.Address
+	ToAssetHash   []byte
+	ToAddress     []byte
+	ToChainId     uint64
+	TokenId       *big.Int
+	Amount        *big.Int
+}
+
+type erc1155UnlockEvent struct {
+	ToAssetHash common.Address
+	ToAddress   common.Address
+	TokenId     *big.Int
+	Amount      *big.Int
+}
+
+func (e *EthereumChainListen) getNFT1155ProxyEventByBlockNumber(
+	proxyAddrStr string,
+	startHeight, endHeight uint64) (
+	[]*models.ProxyLockEvent,
+	[]*models.ProxyUnlockEvent,
+	error,
+) {
+	if !isContract(proxyAddrStr) {
+		return nil, nil, nil
+	}
+	client := e.ethSdk.GetClient()
+	if client == nil {
+		return nil, nil, fmt.Errorf("all node is not working")
+	}
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(startHeight),
+		ToBlock:   new(big.Int).SetUint64(endHeight),
+		Addresses: []common.Address{common.HexToAddress(proxyAddrStr)},
+		Topics:    [][]common.Hash{{erc1155LockTopic, erc1155UnlockTopic}},
+	}
+	proxyLogs, err := client.FilterLogs(context.Background(), query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetSmartContractEventByBlock, filter erc1155 proxy events :%s", err.Error())
+	}
+	return erc1155ProxyLogs2Events(proxyLogs)
+}
+
+// erc1155ProxyLogs2Events converts the logs of the ERC1155 lock proxy, Amount of the events is the token id
+// like the NFT lock proxy events and Quantity is the amount of the token.
+func erc1155ProxyLogs2Events(proxyLogs []types.Log) ([]*models.ProxyLockEvent, []*models.ProxyUnlockEvent, error) {
+	proxyLockEvents := make([]*models.ProxyLockEvent, 0)
+	proxyUnlockEvents := make([]*models.ProxyUnlockEvent, 0)
+	for _, log := range proxyLogs {
+		if log.Removed || len(log.Topics) == 0 {
+			continue
+		}
+		switch log.Topics[0] {
+		case erc1155LockTopic:
+			evt := new(erc1155LockEvent)
+			if err := erc1155ABI.Unpack(evt, "LockEvent", log.Data); err != nil {
+				return nil, nil, fmt.Errorf("unpack erc1155 lock event of tx %s: %s", log.TxHash.String(), err.Error())
+			}
+			proxyLockEvents = append(proxyLockEvents, &models.ProxyLockEvent{
+				Method:        _eth_lock,
+				TxHash:        log.TxHash.String()[2:],
+				FromAddress:   evt.FromAddress.String()[2:],
+				FromAssetHash: strings.ToLower(evt.FromAssetHash.String()[2:]),
+				ToChainId:     uint32(evt.ToChainId),
+				ToAssetHash:   hex.EncodeToString(evt.ToAssetHash),
+				ToAddress:     hex.EncodeToString(evt.ToAddress),
+				Amount:        evt.TokenId,
+				Quantity:      evt.Amount,
+			})
+		case erc1155UnlockTopic:
+			evt := new(erc1155UnlockEvent)
+			if err := erc1155ABI.Unpack(evt, "UnlockEvent", log.Data); err != nil {
+				return nil, nil, fmt.Errorf("unpack erc1155 unlock event of tx %s: %s", log.TxHash.String(), err.Error())
+			}
+			proxyUnlockEvents = append(proxyUnlockEvents, &models.ProxyUnlockEvent{
+				Method:      _eth_unlock,
+				TxHash:      log.TxHash.String()[2:],
+				ToAssetHash: strings.ToLower(evt.ToAssetHash.String()[2:]),
+				ToAddress:   strings.ToLower(evt.ToAddress.String()[2:]),
+				Amount:      evt.TokenId,
+				Quantity:    evt.Amount,
+			})
+		}
+	}
+	return proxyLockEvents, proxyUnlockEvents, nil
+}
+
+// erc1155TransferLog2Transfers decodes the TransferSingle and TransferBatch events, a log which can not be
+// decoded has no transfers.
+func erc1155TransferLog2Transfers(chainId uint64, log types.Log) []*models.NFTTransferEvent {
+	if len(log.Topics) != 4 {
+		return nil
+	}
+	var ids, values []*big.Int
+	switch log.Topics[0] {
+	case erc1155TransferSingleTopic:
+		evt := new(erc1155TransferSingle)
+		if err := erc1155ABI.Unpack(evt, "TransferSingle", log.Data); err != nil {
+			return nil
+		}
+		ids, values = []*big.Int{evt.Id}, []*big.Int{evt.Value}
+	case erc1155TransferBatchTopic:
+		evt := new(erc1155TransferBatch)
+		if err := erc1155ABI.Unpack(evt, "TransferBatch", log.Data); err != nil || len(evt.Ids) != len(evt.Values) {
+			return nil
+		}
+		ids, values = evt.Ids, evt.Values
+	default:
+		return nil
+	}
+	transfers := make([]*models.NFTTransferEvent, 0, len(ids))
+	for i := range ids {
+		transfers = append(transfers, &models.NFTTransferEvent{
+			Standard: models.TokenTypeErc1155,
+			ChainId:  chainId,
+			Asset:    strings.ToLower(log.Address.String()[2:]),
+			TokenId:  ids[i],
+			From:     strings.ToLower(common.BytesToAddress(log.Topics[2].Bytes()).String()[2:]),
+			To:       strings.ToLower(common.BytesToAddress(log.Topics[3].Bytes()).String()[2:]),
+			Quantity: values[i],
+			Height:   log.BlockNumber,
+		})
+	}
+	return transfers
+}
@@ -116,11 +116,14 @@ func (this *EthereumChainListen) HandleNewBlock(height uint64) ([]*models.Wrappe
 	proxyLockEvents, proxyUnlockEvents := make([]*models.ProxyLockEvent, 0), make([]*models.ProxyUnlockEvent, 0)
 	erc20ProxyLockEvents, erc20ProxyUnlockEvents, err3 := this.getProxyEventByBlockNumber(this.ethCfg.ProxyContract, height, height)
 	nftProxyLockEvents, nftProxyUnlockEvents, err4 := this.getNFTProxyEventByBlockNumber(this.ethCfg.NFTProxyContract, height, height)
+	nft1155ProxyLockEvents, nft1155ProxyUnlockEvents, err5 := this.getNFT1155ProxyEventByBlockNumber(this.ethCfg.NFT1155ProxyContract, height, height)
 	proxyLockEvents = append(proxyLockEvents, erc20ProxyLockEvents...)
 	proxyUnlockEvents = append(proxyUnlockEvents, erc20ProxyUnlockEvents...)
 	proxyLockEvents = append(proxyLockEvents, nftProxyLockEvents...)
 	proxyUnlockEvents = append(proxyUnlockEvents, nftProxyUnlockEvents...)
-	if err := allErr(err3, err4); err != nil {
+	proxyLockEvents = append(proxyLockEvents, nft1155ProxyLockEvents...)
+	proxyUnlockEvents = append(proxyUnlockEvents, nft1155ProxyUnlockEvents...)
+	if err := allErr(err3, err4, err5); err != nil {
 		return nil, nil, nil, nil, err
 	}
 	swapLockEvents, swapEvents, err := this.getSwapEventByBlockNumber(this.ethCfg.SwapContract, height, height)
@@ -162,8 +165,9 @@ func (this *EthereumChainListen) HandleNewBlock(height uint64) ([]*models.Wrappe
 					srcTransfer.DstUser = v.ToAddress
 					srcTransaction.SrcTransfer = srcTransfer
 					if this.isNFTECCMLockEvent(lockEvent) {
-						srcTransaction.Standard = models.TokenTypeErc721
-						srcTransaction.SrcTransfer.Standard = models.TokenTypeErc721
+						setNFTSrcTransfer(srcTransaction, models.TokenTypeErc721, v)
+					} else if this.isNFT1155ECCMLockEvent(lockEvent) {
+						setNFTSrcTransfer(srcTransaction, models.TokenTypeErc1155, v)
 					}
 					break
 				}
@@ -204,6 +208,7 @@ func (this *EthereumChainListen) HandleNewBlock(height uint64) ([]*models.Wrappe
 			}
 		}
 	}
+	addERC1155WrapperStandard(wrapperTransactions, srcTransactions)
 	// save unLockEvent to db
 	for _, unLockEvent := range eccmUnLockEvents {
 		if unLockEvent.Method == _eth_crosschainunlock {
@@ -230,8 +235,9 @@ func (this *EthereumChainListen) HandleNewBlock(height uint64) ([]*models.Wrappe
 					dstTransfer.Amount = models.NewBigInt(v.Amount)
 					dstTransaction.DstTransfer = dstTransfer
 					if this.isNFTECCMUnlockEvent(unLockEvent) {
-						dstTransaction.Standard = models.TokenTypeErc721
-						dstTransaction.DstTransfer.Standard = models.TokenTypeErc721
+						setNFTDstTransfer(dstTransaction, models.TokenTypeErc721, v)
+					} else if this.isNFT1155ECCMUnlockEvent(unLockEvent) {
+						setNFTDstTransfer(dstTransaction, models.TokenTypeErc1155, v)
 					}
 					break
 				}
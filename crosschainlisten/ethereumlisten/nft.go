@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math/big"
 	"poly-bridge/go_abi/eccm_abi"
 	nftlp "poly-bridge/go_abi/nft_lock_proxy_abi"
 	nftwp "poly-bridge/go_abi/nft_wrap_abi"
 	"poly-bridge/models"
 	"strings"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func allErr(errs ...error) error {
@@ -56,6 +60,24 @@ func (e *EthereumChainListen) isNFTECCMUnlockEvent(event *models.ECCMUnlockEvent
 	return bytes.Equal(addr1.Bytes(), addr2.Bytes())
 }
 
+func (e *EthereumChainListen) isNFT1155ECCMLockEvent(event *models.ECCMLockEvent) bool {
+	if !isContract(e.ethCfg.NFT1155ProxyContract) {
+		return false
+	}
+	addr1 := common.HexToAddress(event.Contract)
+	addr2 := common.HexToAddress(e.ethCfg.NFT1155ProxyContract)
+	return bytes.Equal(addr1.Bytes(), addr2.Bytes())
+}
+
+func (e *EthereumChainListen) isNFT1155ECCMUnlockEvent(event *models.ECCMUnlockEvent) bool {
+	if !isContract(e.ethCfg.NFT1155ProxyContract) {
+		return false
+	}
+	addr1 := common.HexToAddress(event.Contract)
+	addr2 := common.HexToAddress(e.ethCfg.NFT1155ProxyContract)
+	return bytes.Equal(addr1.Bytes(), addr2.Bytes())
+}
+
 func (e *EthereumChainListen) NFTWrapperAddress() common.Address {
 	return common.HexToAddress(e.ethCfg.NFTWrapperContract)
 }
@@ -176,6 +198,63 @@ func (e *EthereumChainListen) NFTProxyAddress() common.Address {
 //	return wrapperTransactions, srcTransactions, dstTransactions, nil
 //}
 
+var erc721TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// HandleNFTTransfers reads the ERC721 Transfer and the ERC1155 TransferSingle and TransferBatch events of the
+// assets in the block.
+func (e *EthereumChainListen) HandleNFTTransfers(height uint64, assets []string) ([]*models.NFTTransferEvent, error) {
+	addresses := make([]common.Address, 0, len(assets))
+	for _, asset := range assets {
+		if isContract(asset) {
+			addresses = append(addresses, common.HexToAddress(asset))
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	client := e.ethSdk.GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(height),
+		ToBlock:   new(big.Int).SetUint64(height),
+		Addresses: addresses,
+		Topics:    [][]common.Hash{{erc721TransferTopic, erc1155TransferSingleTopic, erc1155TransferBatchTopic}},
+	}
+	transferLogs, err := client.FilterLogs(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("HandleNFTTransfers, filter transfer logs: %s", err.Error())
+	}
+	return transferLogs2NFTTransfers(e.GetChainId(), transferLogs), nil
+}
+
+// transferLogs2NFTTransfers decodes the transfers in the logs in order. The ERC20 Transfer events which have
+// no indexed token id are skipped.
+func transferLogs2NFTTransfers(chainId uint64, transferLogs []types.Log) []*models.NFTTransferEvent {
+	transfers := make([]*models.NFTTransferEvent, 0)
+	for _, log := range transferLogs {
+		if log.Removed || len(log.Topics) != 4 {
+			continue
+		}
+		if log.Topics[0] != erc721TransferTopic {
+			transfers = append(transfers, erc1155TransferLog2Transfers(chainId, log)...)
+			continue
+		}
+		transfers = append(transfers, &models.NFTTransferEvent{
+			Standard: models.TokenTypeErc721,
+			ChainId:  chainId,
+			Asset:    strings.ToLower(log.Address.String()[2:]),
+			TokenId:  new(big.Int).SetBytes(log.Topics[3].Bytes()),
+			From:     strings.ToLower(common.BytesToAddress(log.Topics[1].Bytes()).String()[2:]),
+			To:       strings.ToLower(common.BytesToAddress(log.Topics[2].Bytes()).String()[2:]),
+			Quantity: big.NewInt(1),
+			Height:   log.BlockNumber,
+		})
+	}
+	return transfers
+}
+
 func (e *EthereumChainListen) getNFTWrapperEventByBlockNumber(
 	wrapAddrStr string,
 	startHeight, endHeight uint64) (
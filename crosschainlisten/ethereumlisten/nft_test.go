@@ -2,7 +2,11 @@ package ethereumlisten
 
 import (
 	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
+	"math/big"
+	"poly-bridge/models"
 	"testing"
 )
 
@@ -32,3 +36,71 @@ func TestNewEthereumChainListen_AllErr(t *testing.T) {
 	y2 = errors.New("mi")
 	assert.NotNil(t, allErr(y1, y2))
 }
+
+func TestTransferLogs2NFTTransfers(t *testing.T) {
+	asset := common.HexToAddress("0x88aD4fD94a05602E595101a3e3171f91289C8f6b")
+	alice := common.HexToAddress("0x9bEF1AE7304D3d2F344ea00e796ADa18cE1beb03")
+	bob := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	transfer := func(from, to common.Address, tokenId int64) types.Log {
+		return types.Log{
+			Address:     asset,
+			Topics:      []common.Hash{erc721TransferTopic, from.Hash(), to.Hash(), common.BigToHash(big.NewInt(tokenId))},
+			BlockNumber: 100,
+		}
+	}
+	erc20Transfer := types.Log{Address: asset, Topics: []common.Hash{erc721TransferTopic, alice.Hash(), bob.Hash()}}
+	single, err := erc1155ABI.Events["TransferSingle"].Inputs.NonIndexed().Pack(big.NewInt(7), big.NewInt(5))
+	assert.NoError(t, err)
+	batch, err := erc1155ABI.Events["TransferBatch"].Inputs.NonIndexed().Pack(
+		[]*big.Int{big.NewInt(7), big.NewInt(8)}, []*big.Int{big.NewInt(2), big.NewInt(3)})
+	assert.NoError(t, err)
+
+	transfers := transferLogs2NFTTransfers(2, []types.Log{
+		transfer(common.Address{}, alice, 1),
+		erc20Transfer,
+		transfer(alice, bob, 1),
+		{Address: asset, Topics: []common.Hash{erc1155TransferSingleTopic, alice.Hash(), common.Address{}.Hash(), alice.Hash()}, Data: single, BlockNumber: 100},
+		{Address: asset, Topics: []common.Hash{erc1155TransferBatchTopic, alice.Hash(), alice.Hash(), bob.Hash()}, Data: batch, BlockNumber: 100},
+		{Address: asset, Topics: []common.Hash{erc1155TransferBatchTopic, alice.Hash(), alice.Hash(), bob.Hash()}, Data: single[:32]},
+	})
+	assert.Equal(t, 5, len(transfers))
+	assert.Equal(t, models.TokenTypeErc721, transfers[0].Standard)
+	assert.Equal(t, "1", transfers[1].TokenId.String())
+	assert.Equal(t, "9bef1ae7304d3d2f344ea00e796ada18ce1beb03", transfers[1].From)
+	assert.Equal(t, "1111111111111111111111111111111111111111", transfers[1].To)
+	assert.Equal(t, "88ad4fd94a05602e595101a3e3171f91289c8f6b", transfers[1].Asset)
+	assert.Equal(t, models.TokenTypeErc1155, transfers[2].Standard)
+	assert.Equal(t, models.NFTZeroAddress, transfers[2].From)
+	assert.Equal(t, "5", transfers[2].Quantity.String())
+	assert.Equal(t, "8", transfers[4].TokenId.String())
+	assert.Equal(t, "3", transfers[4].Quantity.String())
+	assert.Equal(t, uint64(2), transfers[4].ChainId)
+	assert.Equal(t, uint64(100), transfers[4].Height)
+}
+
+func TestErc1155ProxyLogs2Events(t *testing.T) {
+	asset := common.HexToAddress("0x88aD4fD94a05602E595101a3e3171f91289C8f6b")
+	alice := common.HexToAddress("0x9bEF1AE7304D3d2F344ea00e796ADa18cE1beb03")
+	lock, err := erc1155ABI.Events["LockEvent"].Inputs.Pack(asset, alice, asset.Bytes(), alice.Bytes(), uint64(6), big.NewInt(7), big.NewInt(5))
+	assert.NoError(t, err)
+	unlock, err := erc1155ABI.Events["UnlockEvent"].Inputs.Pack(asset, alice, big.NewInt(7), big.NewInt(5))
+	assert.NoError(t, err)
+
+	lockEvents, unlockEvents, err := erc1155ProxyLogs2Events([]types.Log{
+		{Topics: []common.Hash{erc1155LockTopic}, Data: lock, TxHash: common.HexToHash("0x01")},
+		{Topics: []common.Hash{erc1155UnlockTopic}, Data: unlock, TxHash: common.HexToHash("0x02")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(lockEvents))
+	assert.Equal(t, "88ad4fd94a05602e595101a3e3171f91289c8f6b", lockEvents[0].FromAssetHash)
+	assert.Equal(t, "88ad4fd94a05602e595101a3e3171f91289c8f6b", lockEvents[0].ToAssetHash)
+	assert.Equal(t, uint32(6), lockEvents[0].ToChainId)
+	assert.Equal(t, "7", lockEvents[0].Amount.String())
+	assert.Equal(t, "5", lockEvents[0].Quantity.String())
+	assert.Equal(t, 1, len(unlockEvents))
+	assert.Equal(t, "9bef1ae7304d3d2f344ea00e796ada18ce1beb03", unlockEvents[0].ToAddress)
+	assert.Equal(t, "5", unlockEvents[0].Quantity.String())
+
+	_, _, err = erc1155ProxyLogs2Events([]types.Log{{Topics: []common.Hash{erc1155LockTopic}, Data: unlock[:32]}})
+	assert.Error(t, err)
+}
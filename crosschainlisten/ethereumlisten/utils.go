@@ -2,6 +2,7 @@ package ethereumlisten
 
 import (
 	"encoding/hex"
+	"math/big"
 	"strings"
 
 	"poly-bridge/basedef"
@@ -195,3 +196,43 @@ func addERC721TokenStandard(
 		}
 	}
 }
+
+// setNFTSrcTransfer marks the transfer of the source transaction as an NFT transfer whose amount is the token id,
+// the quantity of an ERC721 token is 1.
+func setNFTSrcTransfer(srcTransaction *models.SrcTransaction, standard uint8, evt *models.ProxyLockEvent) {
+	srcTransaction.Standard = standard
+	srcTransaction.SrcTransfer.Standard = standard
+	srcTransaction.SrcTransfer.TokenId = models.NewBigInt(evt.Amount)
+	srcTransaction.SrcTransfer.Quantity = nftQuantity(evt.Quantity)
+}
+
+// setNFTDstTransfer marks the transfer of the destination transaction as an NFT transfer whose amount is the
+// token id, the quantity of an ERC721 token is 1.
+func setNFTDstTransfer(dstTransaction *models.DstTransaction, standard uint8, evt *models.ProxyUnlockEvent) {
+	dstTransaction.Standard = standard
+	dstTransaction.DstTransfer.Standard = standard
+	dstTransaction.DstTransfer.TokenId = models.NewBigInt(evt.Amount)
+	dstTransaction.DstTransfer.Quantity = nftQuantity(evt.Quantity)
+}
+
+func nftQuantity(quantity *big.Int) *models.BigInt {
+	if quantity == nil {
+		return models.NewBigIntFromInt(1)
+	}
+	return models.NewBigInt(quantity)
+}
+
+// addERC1155WrapperStandard marks the wrapper transactions of the ERC1155 transfers, the NFT wrapper events
+// are the same for both NFT standards.
+func addERC1155WrapperStandard(wptxs []*models.WrapperTransaction, srcTxs []*models.SrcTransaction) {
+	for _, srcTx := range srcTxs {
+		if srcTx.Standard != models.TokenTypeErc1155 {
+			continue
+		}
+		for _, wptx := range wptxs {
+			if wptx.Hash == srcTx.Hash {
+				wptx.Standard = models.TokenTypeErc1155
+			}
+		}
+	}
+}
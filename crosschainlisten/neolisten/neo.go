@@ -30,6 +30,8 @@ import (
 	"poly-bridge/conf"
 	"poly-bridge/models"
 	"strings"
+
+	neomodels "github.com/joeqian10/neo-gogogo/rpc/models"
 )
 
 const (
@@ -101,176 +103,211 @@ func (this *NeoChainListen) HandleNewBlock(height uint64) ([]*models.WrapperTran
 		if err != nil || appLog == nil {
 			continue
 		}
-		for _, exeitem := range appLog.Executions {
-			for _, notify := range exeitem.Notifications {
-				if notify.Contract[2:] == this.neoCfg.WrapperContract {
-					if len(notify.State.Value) < 0 {
+		wrappers, srcs, dsts := this.parseApplicationLog(tx.Txid, appLog, uint64(tt), height)
+		wrapperTransactions = append(wrapperTransactions, wrappers...)
+		srcTransactions = append(srcTransactions, srcs...)
+		dstTransactions = append(dstTransactions, dsts...)
+	}
+	return wrapperTransactions, srcTransactions, nil, dstTransactions, nil
+}
+
+// parseApplicationLog decodes the wrapper, the lock and the unlock transactions from the notifications of the application log.
+func (this *NeoChainListen) parseApplicationLog(txId string, appLog *neomodels.RpcApplicationLog, tt uint64, height uint64) ([]*models.WrapperTransaction, []*models.SrcTransaction, []*models.DstTransaction) {
+	wrapperTransactions := make([]*models.WrapperTransaction, 0)
+	srcTransactions := make([]*models.SrcTransaction, 0)
+	dstTransactions := make([]*models.DstTransaction, 0)
+	for _, exeitem := range appLog.Executions {
+		for _, notify := range exeitem.Notifications {
+			if notify.Contract[2:] == this.neoCfg.WrapperContract {
+				if len(notify.State.Value) < 0 {
+					continue
+				}
+				contractMethod := this.parseNeoMethod(notify.State.Value[0].Value)
+				switch contractMethod {
+				case _poly_wrapper_lock:
+					logs.Info("(wrapper) from chain: %s, txhash: %s", this.GetChainName(), txId[2:])
+					if len(notify.State.Value) < 8 {
 						continue
 					}
-					contractMethod := this.parseNeoMethod(notify.State.Value[0].Value)
-					switch contractMethod {
-					case _poly_wrapper_lock:
-						logs.Info("(wrapper) from chain: %s, txhash: %s", this.GetChainName(), tx.Txid[2:])
-						if len(notify.State.Value) < 8 {
-							continue
-						}
-						value := notify.State.Value
-						tchainId := big.NewInt(0)
-						if value[3].Type == "Integer" {
-							tchainId, _ = new(big.Int).SetString(value[3].Value, 10)
-						} else {
-							tchainId, _ = new(big.Int).SetString(basedef.HexStringReverse(value[3].Value), 16)
-						}
-						serverId := big.NewInt(0)
-						if value[7].Type == "Integer" {
-							serverId, _ = new(big.Int).SetString(value[7].Value, 10)
-						} else {
-							serverId, _ = new(big.Int).SetString(basedef.HexStringReverse(value[7].Value), 16)
-						}
-						if serverId == nil {
-							serverId = new(big.Int).SetUint64(0)
-						}
-						asset := basedef.HexStringReverse(value[1].Value)
-						amount := big.NewInt(0)
-						if value[6].Type == "Integer" {
-							amount, _ = new(big.Int).SetString(value[6].Value, 10)
-						} else {
-							amount, _ = new(big.Int).SetString(basedef.HexStringReverse(value[6].Value), 16)
-						}
-						wrapperTransactions = append(wrapperTransactions, &models.WrapperTransaction{
-							Hash:         tx.Txid[2:],
-							User:         notify.State.Value[2].Value,
-							DstChainId:   tchainId.Uint64(),
-							DstUser:      notify.State.Value[4].Value,
-							FeeTokenHash: asset,
-							FeeAmount:    models.NewBigInt(amount),
-							ServerId:     serverId.Uint64(),
-							Status:       basedef.STATE_SOURCE_DONE,
-							Time:         uint64(tt),
-							BlockHeight:  height,
-							SrcChainId:   this.GetChainId(),
-						})
+					value := notify.State.Value
+					tchainId := big.NewInt(0)
+					if value[3].Type == "Integer" {
+						tchainId, _ = new(big.Int).SetString(value[3].Value, 10)
+					} else {
+						tchainId, _ = new(big.Int).SetString(basedef.HexStringReverse(value[3].Value), 16)
+					}
+					serverId := big.NewInt(0)
+					if value[7].Type == "Integer" {
+						serverId, _ = new(big.Int).SetString(value[7].Value, 10)
+					} else {
+						serverId, _ = new(big.Int).SetString(basedef.HexStringReverse(value[7].Value), 16)
 					}
-				} else if notify.Contract[2:] == this.neoCfg.CCMContract {
-					if len(notify.State.Value) <= 0 {
+					if serverId == nil {
+						serverId = new(big.Int).SetUint64(0)
+					}
+					asset := basedef.HexStringReverse(value[1].Value)
+					amount := big.NewInt(0)
+					if value[6].Type == "Integer" {
+						amount, _ = new(big.Int).SetString(value[6].Value, 10)
+					} else {
+						amount, _ = new(big.Int).SetString(basedef.HexStringReverse(value[6].Value), 16)
+					}
+					wrapperTransactions = append(wrapperTransactions, &models.WrapperTransaction{
+						Hash:         txId[2:],
+						User:         notify.State.Value[2].Value,
+						DstChainId:   tchainId.Uint64(),
+						DstUser:      notify.State.Value[4].Value,
+						FeeTokenHash: asset,
+						FeeAmount:    models.NewBigInt(amount),
+						ServerId:     serverId.Uint64(),
+						Status:       basedef.STATE_SOURCE_DONE,
+						Time:         tt,
+						BlockHeight:  height,
+						SrcChainId:   this.GetChainId(),
+					})
+				}
+			} else if notify.Contract[2:] == this.neoCfg.NFTWrapperContract {
+				if len(notify.State.Value) <= 0 {
+					continue
+				}
+				contractMethod := this.parseNeoMethod(notify.State.Value[0].Value)
+				if contractMethod != _poly_wrapper_lock {
+					continue
+				}
+				logs.Info("(nft wrapper) from chain: %s, txhash: %s", this.GetChainName(), txId[2:])
+				if len(notify.State.Value) < 9 {
+					continue
+				}
+				wrapperTransactions = append(wrapperTransactions, this.nftWrapperTransaction(txId[2:], notify.State.Value, tt, height))
+			} else if notify.Contract[2:] == this.neoCfg.CCMContract {
+				if len(notify.State.Value) <= 0 {
+					continue
+				}
+				contractMethod := this.parseNeoMethod(notify.State.Value[0].Value)
+				switch contractMethod {
+				case _neo_crosschainlock:
+					logs.Info("(lock) from chain: %s, txhash: %s", this.GetChainName(), txId[2:])
+					if len(notify.State.Value) < 6 {
 						continue
 					}
-					contractMethod := this.parseNeoMethod(notify.State.Value[0].Value)
-					switch contractMethod {
-					case _neo_crosschainlock:
-						logs.Info("(lock) from chain: %s, txhash: %s", this.GetChainName(), tx.Txid[2:])
-						if len(notify.State.Value) < 6 {
-							continue
-						}
-						fctransfer := &models.SrcTransfer{}
-						for _, notifynew := range exeitem.Notifications {
-							contractMethodNew := this.parseNeoMethod(notifynew.State.Value[0].Value)
-							if contractMethodNew == _neo_lock || contractMethodNew == _neo_lock2 {
-								if len(notifynew.State.Value) < 7 {
-									continue
-								}
-								fctransfer.ChainId = this.GetChainId()
-								fctransfer.TxHash = tx.Txid[2:]
-								fctransfer.Time = uint64(tt)
-								fctransfer.From = notifynew.State.Value[2].Value
-								fctransfer.To = notify.State.Value[2].Value
-								fctransfer.Asset = basedef.HexStringReverse(notifynew.State.Value[1].Value)
-								amount := big.NewInt(0)
-								if notifynew.State.Value[6].Type == "Integer" {
-									amount, _ = new(big.Int).SetString(notifynew.State.Value[6].Value, 10)
-								} else {
-									amount, _ = new(big.Int).SetString(basedef.HexStringReverse(notifynew.State.Value[6].Value), 16)
-								}
-								fctransfer.Amount = models.NewBigInt(amount)
-								tChainId := big.NewInt(0)
-								if notifynew.State.Value[3].Type == "Integer" {
-									tChainId, _ = new(big.Int).SetString(notifynew.State.Value[3].Value, 10)
-								} else {
-									tChainId, _ = new(big.Int).SetString(basedef.HexStringReverse(notifynew.State.Value[3].Value), 16)
-								}
-								fctransfer.DstChainId = tChainId.Uint64()
-								if len(notifynew.State.Value[5].Value) != 40 {
-									continue
-								}
-								fctransfer.DstUser = notifynew.State.Value[5].Value
-								fctransfer.DstAsset = notifynew.State.Value[4].Value
-								break
+					fctransfer := &models.SrcTransfer{}
+					nftLock := false
+					for _, notifynew := range exeitem.Notifications {
+						contractMethodNew := this.parseNeoMethod(notifynew.State.Value[0].Value)
+						if contractMethodNew == _neo_lock || contractMethodNew == _neo_lock2 {
+							if len(notifynew.State.Value) < 7 {
+								continue
 							}
-						}
-						fctx := &models.SrcTransaction{}
-						fctx.ChainId = this.GetChainId()
-						fctx.Hash = tx.Txid[2:]
-						fctx.State = 1
-						fctx.Fee = models.NewBigInt(big.NewInt(int64(basedef.String2Float64(exeitem.GasConsumed))))
-						fctx.Time = uint64(tt)
-						fctx.Height = height
-						fctx.User = fctransfer.From
-						toChainId := big.NewInt(0)
-						if notify.State.Value[3].Type == "Integer" {
-							toChainId, _ = new(big.Int).SetString(notify.State.Value[3].Value, 10)
-						} else {
-							toChainId, _ = new(big.Int).SetString(basedef.HexStringReverse(notify.State.Value[3].Value), 16)
-						}
-						fctx.DstChainId = toChainId.Uint64()
-						fctx.Contract = notify.State.Value[2].Value
-						fctx.Key = notify.State.Value[4].Value
-						fctx.Param = notify.State.Value[5].Value
-						fctx.SrcTransfer = fctransfer
-						srcTransactions = append(srcTransactions, fctx)
-					case _neo_crosschainunlock:
-						logs.Info("(unlock) to chain: %s, txhash: %s", this.GetChainName(), tx.Txid[2:])
-						if len(notify.State.Value) < 4 {
-							continue
-						}
-						tctransfer := &models.DstTransfer{}
-						for _, notifynew := range exeitem.Notifications {
-							contractMethodNew := this.parseNeoMethod(notifynew.State.Value[0].Value)
-							if contractMethodNew == _neo_unlock || contractMethodNew == _neo_unlock2 {
-								if len(notifynew.State.Value) < 4 {
-									continue
-								}
-								tctransfer.ChainId = this.GetChainId()
-								tctransfer.TxHash = tx.Txid[2:]
-								tctransfer.Time = uint64(tt)
-								tctransfer.From = notify.State.Value[2].Value
-								tctransfer.To = notifynew.State.Value[2].Value
-								tctransfer.Asset = basedef.HexStringReverse(notifynew.State.Value[1].Value)
-								amount := big.NewInt(0)
-								if notifynew.State.Value[3].Type == "Integer" {
-									amount, _ = new(big.Int).SetString(notifynew.State.Value[3].Value, 10)
-								} else {
-									amount, _ = new(big.Int).SetString(basedef.HexStringReverse(notifynew.State.Value[3].Value), 16)
-								}
-								tctransfer.Amount = models.NewBigInt(amount)
-								break
+							nftLock = this.isNFTProxy(notifynew.Contract)
+							fctransfer.ChainId = this.GetChainId()
+							fctransfer.TxHash = txId[2:]
+							fctransfer.Time = tt
+							fctransfer.From = notifynew.State.Value[2].Value
+							fctransfer.To = notify.State.Value[2].Value
+							fctransfer.Asset = basedef.HexStringReverse(notifynew.State.Value[1].Value)
+							amount := big.NewInt(0)
+							if notifynew.State.Value[6].Type == "Integer" {
+								amount, _ = new(big.Int).SetString(notifynew.State.Value[6].Value, 10)
+							} else {
+								amount, _ = new(big.Int).SetString(basedef.HexStringReverse(notifynew.State.Value[6].Value), 16)
+							}
+							fctransfer.Amount = models.NewBigInt(amount)
+							tChainId := big.NewInt(0)
+							if notifynew.State.Value[3].Type == "Integer" {
+								tChainId, _ = new(big.Int).SetString(notifynew.State.Value[3].Value, 10)
+							} else {
+								tChainId, _ = new(big.Int).SetString(basedef.HexStringReverse(notifynew.State.Value[3].Value), 16)
 							}
+							fctransfer.DstChainId = tChainId.Uint64()
+							if len(notifynew.State.Value[5].Value) != 40 {
+								continue
+							}
+							fctransfer.DstUser = notifynew.State.Value[5].Value
+							fctransfer.DstAsset = notifynew.State.Value[4].Value
+							break
 						}
-						tctx := &models.DstTransaction{}
-						tctx.ChainId = this.GetChainId()
-						tctx.Hash = tx.Txid[2:]
-						tctx.State = 1
-						tctx.Fee = models.NewBigInt(big.NewInt(int64(basedef.String2Float64(exeitem.GasConsumed))))
-						tctx.Time = uint64(tt)
-						tctx.Height = height
-						fChainId := big.NewInt(0)
-						if notify.State.Value[1].Type == "Integer" {
-							fChainId, _ = new(big.Int).SetString(notify.State.Value[1].Value, 10)
-						} else {
-							fChainId, _ = new(big.Int).SetString(basedef.HexStringReverse(notify.State.Value[1].Value), 16)
+					}
+					fctx := &models.SrcTransaction{}
+					fctx.ChainId = this.GetChainId()
+					fctx.Hash = txId[2:]
+					fctx.State = 1
+					fctx.Fee = models.NewBigInt(big.NewInt(int64(basedef.String2Float64(exeitem.GasConsumed))))
+					fctx.Time = tt
+					fctx.Height = height
+					fctx.User = fctransfer.From
+					toChainId := big.NewInt(0)
+					if notify.State.Value[3].Type == "Integer" {
+						toChainId, _ = new(big.Int).SetString(notify.State.Value[3].Value, 10)
+					} else {
+						toChainId, _ = new(big.Int).SetString(basedef.HexStringReverse(notify.State.Value[3].Value), 16)
+					}
+					fctx.DstChainId = toChainId.Uint64()
+					fctx.Contract = notify.State.Value[2].Value
+					fctx.Key = notify.State.Value[4].Value
+					fctx.Param = notify.State.Value[5].Value
+					fctx.SrcTransfer = fctransfer
+					if nftLock {
+						fctx.SetERC721Transfer()
+					}
+					srcTransactions = append(srcTransactions, fctx)
+				case _neo_crosschainunlock:
+					logs.Info("(unlock) to chain: %s, txhash: %s", this.GetChainName(), txId[2:])
+					if len(notify.State.Value) < 4 {
+						continue
+					}
+					tctransfer := &models.DstTransfer{}
+					nftUnlock := false
+					for _, notifynew := range exeitem.Notifications {
+						contractMethodNew := this.parseNeoMethod(notifynew.State.Value[0].Value)
+						if contractMethodNew == _neo_unlock || contractMethodNew == _neo_unlock2 {
+							if len(notifynew.State.Value) < 4 {
+								continue
+							}
+							nftUnlock = this.isNFTProxy(notifynew.Contract)
+							tctransfer.ChainId = this.GetChainId()
+							tctransfer.TxHash = txId[2:]
+							tctransfer.Time = tt
+							tctransfer.From = notify.State.Value[2].Value
+							tctransfer.To = notifynew.State.Value[2].Value
+							tctransfer.Asset = basedef.HexStringReverse(notifynew.State.Value[1].Value)
+							amount := big.NewInt(0)
+							if notifynew.State.Value[3].Type == "Integer" {
+								amount, _ = new(big.Int).SetString(notifynew.State.Value[3].Value, 10)
+							} else {
+								amount, _ = new(big.Int).SetString(basedef.HexStringReverse(notifynew.State.Value[3].Value), 16)
+							}
+							tctransfer.Amount = models.NewBigInt(amount)
+							break
 						}
-						tctx.SrcChainId = fChainId.Uint64()
-						tctx.Contract = basedef.HexStringReverse(notify.State.Value[2].Value)
-						tctx.PolyHash = basedef.HexStringReverse(notify.State.Value[3].Value)
-						tctx.DstTransfer = tctransfer
-						dstTransactions = append(dstTransactions, tctx)
-					default:
-						logs.Warn("ignore method: %s", contractMethod)
 					}
+					tctx := &models.DstTransaction{}
+					tctx.ChainId = this.GetChainId()
+					tctx.Hash = txId[2:]
+					tctx.State = 1
+					tctx.Fee = models.NewBigInt(big.NewInt(int64(basedef.String2Float64(exeitem.GasConsumed))))
+					tctx.Time = tt
+					tctx.Height = height
+					fChainId := big.NewInt(0)
+					if notify.State.Value[1].Type == "Integer" {
+						fChainId, _ = new(big.Int).SetString(notify.State.Value[1].Value, 10)
+					} else {
+						fChainId, _ = new(big.Int).SetString(basedef.HexStringReverse(notify.State.Value[1].Value), 16)
+					}
+					tctx.SrcChainId = fChainId.Uint64()
+					tctx.Contract = basedef.HexStringReverse(notify.State.Value[2].Value)
+					tctx.PolyHash = basedef.HexStringReverse(notify.State.Value[3].Value)
+					tctx.DstTransfer = tctransfer
+					if nftUnlock {
+						tctx.SetERC721Transfer()
+					}
+					dstTransactions = append(dstTransactions, tctx)
+				default:
+					logs.Warn("ignore method: %s", contractMethod)
 				}
 			}
 		}
 	}
-	return wrapperTransactions, srcTransactions, nil, dstTransactions, nil
+	return wrapperTransactions, srcTransactions, dstTransactions
 }
 
 type Error struct {
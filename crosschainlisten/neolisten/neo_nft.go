@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neolisten
+
+import (
+	"math/big"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+	"strings"
+
+	neomodels "github.com/joeqian10/neo-gogogo/rpc/models"
+)
+
+func (this *NeoChainListen) isNFTProxy(contract string) bool {
+	return this.neoCfg.NFTProxyContract != "" && strings.TrimPrefix(contract, "0x") == this.neoCfg.NFTProxyContract
+}
+
+// nftWrapperTransaction decodes the NFT wrapper lock from the contract parameters of the notification.
+func (this *NeoChainListen) nftWrapperTransaction(txHash string, value []neomodels.RpcContractParameter, tt uint64, height uint64) *models.WrapperTransaction {
+	lock := &models.NFTWrapperLock{
+		Sender:    value[2].Value,
+		ToChainId: parseNeoInteger(value[3]).Uint64(),
+		ToAddress: value[4].Value,
+		FeeToken:  value[6].Value,
+		Fee:       parseNeoInteger(value[7]),
+		Id:        parseNeoInteger(value[8]).Uint64(),
+	}
+	return lock.WrapperTransaction(txHash, this.GetChainId(), tt, height)
+}
+
+// parseNeoInteger reads an integer state, which is a reversed hex string unless its type is Integer.
+func parseNeoInteger(value neomodels.RpcContractParameter) *big.Int {
+	var number *big.Int
+	if value.Type == "Integer" {
+		number, _ = new(big.Int).SetString(value.Value, 10)
+	} else {
+		number, _ = new(big.Int).SetString(basedef.HexStringReverse(value.Value), 16)
+	}
+	if number == nil {
+		return big.NewInt(0)
+	}
+	return number
+}
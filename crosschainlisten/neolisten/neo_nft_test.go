@@ -0,0 +1,205 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neolisten
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"testing"
+
+	neomodels "github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testWrapper    = "a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1"
+	testNFTWrapper = "b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1"
+	testCCM        = "c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1"
+	testNFTProxy   = "d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1"
+	testProxy      = "e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1"
+)
+
+// the application logs of a lock by the nft wrapper, a lock and an unlock by the nft proxy
+const (
+	testNFTWrapperLog = `{"txid":"0x1111111111111111111111111111111111111111111111111111111111111111","executions":[{"trigger":"Application","vmstate":"HALT","gas_consumed":"1","notifications":[
+		{"contract":"0xb1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1","state":{"type":"Array","value":[
+			{"type":"ByteArray","value":"506f6c79577261707065724c6f636b"},
+			{"type":"ByteArray","value":"0102030405060708090a0b0c0d0e0f1011121314"},
+			{"type":"ByteArray","value":"2122232425262728292a2b2c2d2e2f3031323334"},
+			{"type":"Integer","value":"2"},
+			{"type":"ByteArray","value":"4142434445464748494a4b4c4d4e4f5051525354"},
+			{"type":"Integer","value":"7"},
+			{"type":"ByteArray","value":"6162636465666768696a6b6c6d6e6f7071727374"},
+			{"type":"ByteArray","value":"00e1f505"},
+			{"type":"Integer","value":"3"}]}}]}]}`
+	testLockLog = `{"txid":"0x2222222222222222222222222222222222222222222222222222222222222222","executions":[{"trigger":"Application","vmstate":"HALT","gas_consumed":"5","notifications":[
+		{"contract":"0x%s","state":{"type":"Array","value":[
+			{"type":"ByteArray","value":"4c6f636b"},
+			{"type":"ByteArray","value":"0102030405060708090a0b0c0d0e0f1011121314"},
+			{"type":"ByteArray","value":"2122232425262728292a2b2c2d2e2f3031323334"},
+			{"type":"Integer","value":"2"},
+			{"type":"ByteArray","value":"8182838485868788898a8b8c8d8e8f9091929394"},
+			{"type":"ByteArray","value":"4142434445464748494a4b4c4d4e4f5051525354"},
+			{"type":"ByteArray","value":"07"}]}},
+		{"contract":"0xc1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1","state":{"type":"Array","value":[
+			{"type":"ByteArray","value":"43726f7373436861696e4c6f636b4576656e74"},
+			{"type":"ByteArray","value":"2122232425262728292a2b2c2d2e2f3031323334"},
+			{"type":"ByteArray","value":"9192939495969798999a9b9c9d9e9fa0a1a2a3a4"},
+			{"type":"Integer","value":"2"},
+			{"type":"ByteArray","value":"0a0b"},
+			{"type":"ByteArray","value":"0c0d"}]}}]}]}`
+	testUnlockLog = `{"txid":"0x3333333333333333333333333333333333333333333333333333333333333333","executions":[{"trigger":"Application","vmstate":"HALT","gas_consumed":"5","notifications":[
+		{"contract":"0xc1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1c1","state":{"type":"Array","value":[
+			{"type":"ByteArray","value":"43726f7373436861696e556e6c6f636b4576656e74"},
+			{"type":"ByteArray","value":"02"},
+			{"type":"ByteArray","value":"9192939495969798999a9b9c9d9e9fa0a1a2a3a4"},
+			{"type":"ByteArray","value":"a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebfc0"}]}},
+		{"contract":"0x%s","state":{"type":"Array","value":[
+			{"type":"ByteArray","value":"556e6c6f636b4576656e74"},
+			{"type":"ByteArray","value":"0102030405060708090a0b0c0d0e0f1011121314"},
+			{"type":"ByteArray","value":"4142434445464748494a4b4c4d4e4f5051525354"},
+			{"type":"Integer","value":"7"}]}}]}]}`
+)
+
+func newTestNeoChainListen() *NeoChainListen {
+	return &NeoChainListen{neoCfg: &conf.ChainListenConfig{
+		ChainName:          "neo",
+		ChainId:            basedef.NEO_CROSSCHAIN_ID,
+		WrapperContract:    testWrapper,
+		NFTWrapperContract: testNFTWrapper,
+		CCMContract:        testCCM,
+		NFTProxyContract:   testNFTProxy,
+	}}
+}
+
+func parseTestLog(t *testing.T, listen *NeoChainListen, fixture string) ([]*models.WrapperTransaction, []*models.SrcTransaction, []*models.DstTransaction) {
+	appLog := new(neomodels.RpcApplicationLog)
+	assert.NoError(t, json.Unmarshal([]byte(fixture), appLog))
+	return listen.parseApplicationLog(appLog.TxId, appLog, 100, 10)
+}
+
+func TestParseNFTWrapperLock(t *testing.T) {
+	wrappers, srcs, dsts := parseTestLog(t, newTestNeoChainListen(), testNFTWrapperLog)
+	assert.Equal(t, 0, len(srcs))
+	assert.Equal(t, 0, len(dsts))
+	assert.Equal(t, 1, len(wrappers))
+	assert.Equal(t, &models.WrapperTransaction{
+		Hash:         "1111111111111111111111111111111111111111111111111111111111111111",
+		User:         "2122232425262728292a2b2c2d2e2f3031323334",
+		SrcChainId:   basedef.NEO_CROSSCHAIN_ID,
+		BlockHeight:  10,
+		Time:         100,
+		DstChainId:   2,
+		DstUser:      "4142434445464748494a4b4c4d4e4f5051525354",
+		ServerId:     3,
+		FeeTokenHash: "74737271706f6e6d6c6b6a696867666564636261",
+		FeeAmount:    models.NewBigIntFromInt(100000000),
+		Status:       basedef.STATE_SOURCE_DONE,
+		Standard:     models.TokenTypeErc721,
+	}, wrappers[0])
+}
+
+func TestParseLock(t *testing.T) {
+	for _, v := range []struct {
+		proxy    string
+		standard uint8
+	}{
+		{testNFTProxy, models.TokenTypeErc721},
+		{testProxy, models.TokenTypeErc20},
+	} {
+		wrappers, srcs, dsts := parseTestLog(t, newTestNeoChainListen(), fmt.Sprintf(testLockLog, v.proxy))
+		assert.Equal(t, 0, len(wrappers))
+		assert.Equal(t, 0, len(dsts))
+		assert.Equal(t, 1, len(srcs))
+		src := srcs[0]
+		assert.Equal(t, "2222222222222222222222222222222222222222222222222222222222222222", src.Hash)
+		assert.Equal(t, uint64(2), src.DstChainId)
+		assert.Equal(t, "2122232425262728292a2b2c2d2e2f3031323334", src.User)
+		assert.Equal(t, "9192939495969798999a9b9c9d9e9fa0a1a2a3a4", src.Contract)
+		assert.Equal(t, "0a0b", src.Key)
+		assert.Equal(t, "0c0d", src.Param)
+		assert.Equal(t, int64(5), src.Fee.Int64())
+		assert.Equal(t, v.standard, src.Standard, v.proxy)
+
+		transfer := src.SrcTransfer
+		assert.Equal(t, "14131211100f0e0d0c0b0a090807060504030201", transfer.Asset)
+		assert.Equal(t, "8182838485868788898a8b8c8d8e8f9091929394", transfer.DstAsset)
+		assert.Equal(t, "4142434445464748494a4b4c4d4e4f5051525354", transfer.DstUser)
+		assert.Equal(t, uint64(2), transfer.DstChainId)
+		assert.Equal(t, int64(7), transfer.Amount.Int64())
+		assert.Equal(t, v.standard, transfer.Standard, v.proxy)
+		if v.standard == models.TokenTypeErc721 {
+			assert.Equal(t, int64(7), transfer.TokenId.Int64(), "the amount locked by the nft proxy is the token id")
+			assert.Equal(t, int64(1), transfer.Quantity.Int64())
+		} else {
+			assert.Nil(t, transfer.TokenId)
+		}
+	}
+}
+
+func TestParseUnlock(t *testing.T) {
+	for _, v := range []struct {
+		proxy    string
+		standard uint8
+	}{
+		{testNFTProxy, models.TokenTypeErc721},
+		{testProxy, models.TokenTypeErc20},
+	} {
+		wrappers, srcs, dsts := parseTestLog(t, newTestNeoChainListen(), fmt.Sprintf(testUnlockLog, v.proxy))
+		assert.Equal(t, 0, len(wrappers))
+		assert.Equal(t, 0, len(srcs))
+		assert.Equal(t, 1, len(dsts))
+		dst := dsts[0]
+		assert.Equal(t, "3333333333333333333333333333333333333333333333333333333333333333", dst.Hash)
+		assert.Equal(t, uint64(2), dst.SrcChainId)
+		assert.Equal(t, "a4a3a2a1a09f9e9d9c9b9a999897969594939291", dst.Contract)
+		assert.Equal(t, "c0bfbebdbcbbbab9b8b7b6b5b4b3b2b1b0afaeadacabaaa9a8a7a6a5a4a3a2a1", dst.PolyHash)
+		assert.Equal(t, v.standard, dst.Standard, v.proxy)
+
+		transfer := dst.DstTransfer
+		assert.Equal(t, "14131211100f0e0d0c0b0a090807060504030201", transfer.Asset)
+		assert.Equal(t, "4142434445464748494a4b4c4d4e4f5051525354", transfer.To)
+		assert.Equal(t, "9192939495969798999a9b9c9d9e9fa0a1a2a3a4", transfer.From)
+		assert.Equal(t, int64(7), transfer.Amount.Int64())
+		assert.Equal(t, v.standard, transfer.Standard, v.proxy)
+		if v.standard == models.TokenTypeErc721 {
+			assert.Equal(t, int64(7), transfer.TokenId.Int64())
+			assert.Equal(t, int64(1), transfer.Quantity.Int64())
+		} else {
+			assert.Nil(t, transfer.TokenId)
+		}
+	}
+}
+
+func TestParseNeoInteger(t *testing.T) {
+	for _, v := range []struct {
+		value  neomodels.RpcContractParameter
+		expect int64
+	}{
+		{neomodels.RpcContractParameter{Type: "Integer", Value: "100"}, 100},
+		{neomodels.RpcContractParameter{Type: "ByteArray", Value: "00e1f505"}, 100000000},
+		{neomodels.RpcContractParameter{Type: "ByteArray", Value: ""}, 0},
+		{neomodels.RpcContractParameter{Type: "Integer", Value: "x"}, 0},
+	} {
+		assert.Equal(t, big.NewInt(v.expect), parseNeoInteger(v.value), v.value.Value)
+	}
+}
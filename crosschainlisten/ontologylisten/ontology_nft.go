@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ontologylisten
+
+import (
+	"math/big"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+)
+
+func (this *OntologyChainListen) isNFTProxy(contract string) bool {
+	return this.ontCfg.NFTProxyContract != "" && contract == this.ontCfg.NFTProxyContract
+}
+
+// nftWrapperTransaction decodes the NFT wrapper lock from the hex string states of the notification, it returns
+// nil if a state is not a string or an address is too long.
+func (this *OntologyChainListen) nftWrapperTransaction(txHash string, states []interface{}, tt uint64, height uint64) *models.WrapperTransaction {
+	for _, state := range states[:9] {
+		if _, ok := state.(string); !ok {
+			return nil
+		}
+	}
+	srcUser := states[2].(string)
+	dstUser := states[4].(string)
+	if len(srcUser) > basedef.ADDRESS_LENGTH || len(dstUser) > basedef.ADDRESS_LENGTH {
+		return nil
+	}
+	lock := &models.NFTWrapperLock{
+		Sender:    srcUser,
+		ToChainId: parseOntInteger(states[3].(string)).Uint64(),
+		ToAddress: dstUser,
+		FeeToken:  states[6].(string),
+		Fee:       parseOntInteger(states[7].(string)),
+		Id:        parseOntInteger(states[8].(string)).Uint64(),
+	}
+	return lock.WrapperTransaction(txHash, this.GetChainId(), tt, height)
+}
+
+// parseOntInteger reads an integer state, which is a reversed hex string.
+func parseOntInteger(value string) *big.Int {
+	number, ok := new(big.Int).SetString(basedef.HexStringReverse(value), 16)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return number
+}
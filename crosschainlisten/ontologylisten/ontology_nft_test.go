@@ -0,0 +1,189 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ontologylisten
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"testing"
+
+	"github.com/ontio/ontology-go-sdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testWrapper    = "a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1"
+	testNFTWrapper = "b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1"
+	testCCM        = "0300000000000000000000000000000000000000"
+	testNFTProxy   = "d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1d1"
+	testProxy      = "e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1"
+)
+
+// the smart contract events of a lock by the nft wrapper, a lock and an unlock by the nft proxy
+const (
+	testNFTWrapperEvent = `{"TxHash":"1111111111111111111111111111111111111111111111111111111111111111","State":1,"GasConsumed":10000000,"Notify":[
+		{"ContractAddress":"b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1","States":["506f6c79577261707065724c6f636b",
+			"0102030405060708090a0b0c0d0e0f1011121314","2122232425262728292a2b2c2d2e2f3031323334","02",
+			"4142434445464748494a4b4c4d4e4f5051525354","07","6162636465666768696a6b6c6d6e6f7071727374","00e1f505","03"]}]}`
+	testLockEvent = `{"TxHash":"2222222222222222222222222222222222222222222222222222222222222222","State":1,"GasConsumed":10000000,"Notify":[
+		{"ContractAddress":"%s","States":["6c6f636b","0102030405060708090a0b0c0d0e0f1011121314",
+			"2122232425262728292a2b2c2d2e2f3031323334","02","8182838485868788898a8b8c8d8e8f9091929394",
+			"4142434445464748494a4b4c4d4e4f5051525354","07"]},
+		{"ContractAddress":"0300000000000000000000000000000000000000","States":["makeFromOntProof","2122232425262728292a2b2c2d2e2f3031323334",
+			2,"01","0a0b","9192939495969798999a9b9c9d9e9fa0a1a2a3a4","0c0d"]}]}`
+	testUnlockEvent = `{"TxHash":"3333333333333333333333333333333333333333333333333333333333333333","State":1,"GasConsumed":10000000,"Notify":[
+		{"ContractAddress":"0300000000000000000000000000000000000000","States":["verifyToOntProof",
+			"a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebfc0","01",2,"0a0b","9192939495969798999a9b9c9d9e9fa0a1a2a3a4"]},
+		{"ContractAddress":"%s","States":["756e6c6f636b","0102030405060708090a0b0c0d0e0f1011121314",
+			"4142434445464748494a4b4c4d4e4f5051525354","07"]}]}`
+)
+
+func newTestOntologyChainListen() *OntologyChainListen {
+	return &OntologyChainListen{ontCfg: &conf.ChainListenConfig{
+		ChainName:          "ontology",
+		ChainId:            basedef.ONT_CROSSCHAIN_ID,
+		WrapperContract:    testWrapper,
+		NFTWrapperContract: testNFTWrapper,
+		CCMContract:        testCCM,
+		NFTProxyContract:   testNFTProxy,
+	}}
+}
+
+func parseTestEvent(t *testing.T, listen *OntologyChainListen, fixture string) ([]*models.WrapperTransaction, []*models.SrcTransaction, []*models.DstTransaction) {
+	event := new(common.SmartContactEvent)
+	assert.NoError(t, json.Unmarshal([]byte(fixture), event))
+	return listen.parseEvent(event, 100, 10)
+}
+
+func TestParseNFTWrapperLock(t *testing.T) {
+	wrappers, srcs, dsts := parseTestEvent(t, newTestOntologyChainListen(), testNFTWrapperEvent)
+	assert.Equal(t, 0, len(srcs))
+	assert.Equal(t, 0, len(dsts))
+	assert.Equal(t, 1, len(wrappers))
+	assert.Equal(t, &models.WrapperTransaction{
+		Hash:         "1111111111111111111111111111111111111111111111111111111111111111",
+		User:         "2122232425262728292a2b2c2d2e2f3031323334",
+		SrcChainId:   basedef.ONT_CROSSCHAIN_ID,
+		BlockHeight:  10,
+		Time:         100,
+		DstChainId:   2,
+		DstUser:      "4142434445464748494a4b4c4d4e4f5051525354",
+		ServerId:     3,
+		FeeTokenHash: "74737271706f6e6d6c6b6a696867666564636261",
+		FeeAmount:    models.NewBigIntFromInt(100000000),
+		Status:       basedef.STATE_SOURCE_DONE,
+		Standard:     models.TokenTypeErc721,
+	}, wrappers[0])
+
+	// the lock is dropped if a state is not a hex string
+	event := new(common.SmartContactEvent)
+	assert.NoError(t, json.Unmarshal([]byte(testNFTWrapperEvent), event))
+	event.Notify[0].States.([]interface{})[3] = float64(2)
+	wrappers, _, _ = newTestOntologyChainListen().parseEvent(event, 100, 10)
+	assert.Equal(t, 0, len(wrappers))
+}
+
+func TestParseLock(t *testing.T) {
+	for _, v := range []struct {
+		proxy    string
+		standard uint8
+	}{
+		{testNFTProxy, models.TokenTypeErc721},
+		{testProxy, models.TokenTypeErc20},
+	} {
+		wrappers, srcs, dsts := parseTestEvent(t, newTestOntologyChainListen(), fmt.Sprintf(testLockEvent, v.proxy))
+		assert.Equal(t, 0, len(wrappers))
+		assert.Equal(t, 0, len(dsts))
+		assert.Equal(t, 1, len(srcs))
+		src := srcs[0]
+		assert.Equal(t, "2222222222222222222222222222222222222222222222222222222222222222", src.Hash)
+		assert.Equal(t, uint64(2), src.DstChainId)
+		assert.Equal(t, "2122232425262728292a2b2c2d2e2f3031323334", src.User)
+		assert.Equal(t, "a4a3a2a1a09f9e9d9c9b9a999897969594939291", src.Contract)
+		assert.Equal(t, "0a0b", src.Key)
+		assert.Equal(t, "0c0d", src.Param)
+		assert.Equal(t, int64(10000000), src.Fee.Int64())
+		assert.Equal(t, v.standard, src.Standard, v.proxy)
+
+		transfer := src.SrcTransfer
+		assert.Equal(t, "14131211100f0e0d0c0b0a090807060504030201", transfer.Asset)
+		assert.Equal(t, "8182838485868788898a8b8c8d8e8f9091929394", transfer.DstAsset)
+		assert.Equal(t, "4142434445464748494a4b4c4d4e4f5051525354", transfer.DstUser)
+		assert.Equal(t, uint64(2), transfer.DstChainId)
+		assert.Equal(t, int64(7), transfer.Amount.Int64())
+		assert.Equal(t, v.standard, transfer.Standard, v.proxy)
+		if v.standard == models.TokenTypeErc721 {
+			assert.Equal(t, int64(7), transfer.TokenId.Int64(), "the amount locked by the nft proxy is the token id")
+			assert.Equal(t, int64(1), transfer.Quantity.Int64())
+		} else {
+			assert.Nil(t, transfer.TokenId)
+		}
+	}
+}
+
+func TestParseUnlock(t *testing.T) {
+	for _, v := range []struct {
+		proxy    string
+		standard uint8
+	}{
+		{testNFTProxy, models.TokenTypeErc721},
+		{testProxy, models.TokenTypeErc20},
+	} {
+		wrappers, srcs, dsts := parseTestEvent(t, newTestOntologyChainListen(), fmt.Sprintf(testUnlockEvent, v.proxy))
+		assert.Equal(t, 0, len(wrappers))
+		assert.Equal(t, 0, len(srcs))
+		assert.Equal(t, 1, len(dsts))
+		dst := dsts[0]
+		assert.Equal(t, "3333333333333333333333333333333333333333333333333333333333333333", dst.Hash)
+		assert.Equal(t, uint64(2), dst.SrcChainId)
+		assert.Equal(t, "a4a3a2a1a09f9e9d9c9b9a999897969594939291", dst.Contract)
+		assert.Equal(t, "c0bfbebdbcbbbab9b8b7b6b5b4b3b2b1b0afaeadacabaaa9a8a7a6a5a4a3a2a1", dst.PolyHash)
+		assert.Equal(t, v.standard, dst.Standard, v.proxy)
+
+		transfer := dst.DstTransfer
+		assert.Equal(t, "14131211100f0e0d0c0b0a090807060504030201", transfer.Asset)
+		assert.Equal(t, "4142434445464748494a4b4c4d4e4f5051525354", transfer.To)
+		assert.Equal(t, "9192939495969798999a9b9c9d9e9fa0a1a2a3a4", transfer.From)
+		assert.Equal(t, int64(7), transfer.Amount.Int64())
+		assert.Equal(t, v.standard, transfer.Standard, v.proxy)
+		if v.standard == models.TokenTypeErc721 {
+			assert.Equal(t, int64(7), transfer.TokenId.Int64())
+			assert.Equal(t, int64(1), transfer.Quantity.Int64())
+		} else {
+			assert.Nil(t, transfer.TokenId)
+		}
+	}
+}
+
+func TestParseOntInteger(t *testing.T) {
+	for _, v := range []struct {
+		value  string
+		expect int64
+	}{
+		{"02", 2},
+		{"00e1f505", 100000000},
+		{"", 0},
+		{"xx", 0},
+	} {
+		assert.Equal(t, big.NewInt(v.expect), parseOntInteger(v.value), v.value)
+	}
+}
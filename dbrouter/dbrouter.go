@@ -0,0 +1,225 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dbrouter
+
+import (
+	"database/sql"
+	"fmt"
+	"poly-bridge/conf"
+	"runtime/debug"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	DefaultMaxReplicaLag = 30
+	checkReplicaSlot     = 10
+)
+
+type replica struct {
+	url     string
+	db      *gorm.DB
+	healthy int32
+}
+
+// Router holds the primary database and its read replicas. Writes always go to the primary,
+// read only queries are spread over the replicas which are not lagging too far behind,
+// and fall back to the primary when there is none.
+type Router struct {
+	primary  *gorm.DB
+	replicas []*replica
+	maxLag   int64
+	next     uint32
+	exit     chan bool
+}
+
+// New routes all the queries to the opened primary, there is no replica.
+func New(primary *gorm.DB) *Router {
+	return &Router{
+		primary:  primary,
+		replicas: make([]*replica, 0),
+		maxLag:   DefaultMaxReplicaLag,
+		exit:     make(chan bool, 0),
+	}
+}
+
+func Open(cfg *conf.DBConfig) (*Router, error) {
+	primary, err := openDB(cfg, cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	router := &Router{
+		primary:  primary,
+		replicas: make([]*replica, 0),
+		maxLag:   cfg.MaxReplicaLag,
+		exit:     make(chan bool, 0),
+	}
+	if router.maxLag <= 0 {
+		router.maxLag = DefaultMaxReplicaLag
+	}
+	for _, url := range cfg.ReadURLs {
+		db, err := openDB(cfg, url)
+		if err != nil {
+			return nil, err
+		}
+		router.replicas = append(router.replicas, &replica{url: url, db: db})
+	}
+	if len(router.replicas) > 0 {
+		router.checkReplicas()
+		go router.monitor()
+	}
+	return router, nil
+}
+
+func DSN(cfg *conf.DBConfig, url string) string {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8", cfg.User, cfg.Password, url, cfg.Scheme)
+	if cfg.Timeout > 0 {
+		dsn += fmt.Sprintf("&timeout=%ds&readTimeout=%ds&writeTimeout=%ds", cfg.Timeout, cfg.Timeout, cfg.Timeout)
+	}
+	return dsn
+}
+
+func openDB(cfg *conf.DBConfig, url string) (*gorm.DB, error) {
+	Logger := logger.Default
+	if cfg.Debug {
+		Logger = Logger.LogMode(logger.Info)
+	}
+	db, err := gorm.Open(mysql.Open(DSN(cfg, url)), &gorm.Config{Logger: Logger})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	}
+	return db, nil
+}
+
+func (router *Router) Primary() *gorm.DB {
+	return router.primary
+}
+
+// Reader returns a healthy replica in round robin, or the primary if all the replicas are lagging.
+func (router *Router) Reader() *gorm.DB {
+	n := len(router.replicas)
+	if n == 0 {
+		return router.primary
+	}
+	start := atomic.AddUint32(&router.next, 1)
+	for i := 0; i < n; i++ {
+		replica := router.replicas[(int(start)+i)%n]
+		if atomic.LoadInt32(&replica.healthy) == 1 {
+			return replica.db
+		}
+	}
+	return router.primary
+}
+
+// Close stops monitoring the replicas.
+func (router *Router) Close() {
+	close(router.exit)
+}
+
+func (router *Router) monitor() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("replica monitor recover info: %s", string(debug.Stack()))
+		}
+	}()
+	ticker := time.NewTicker(time.Second * checkReplicaSlot)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			router.checkReplicas()
+		case <-router.exit:
+			logs.Info("stop replica monitor.")
+			return
+		}
+	}
+}
+
+func (router *Router) checkReplicas() {
+	for _, replica := range router.replicas {
+		lag, err := replicaLag(replica.db)
+		healthy := int32(1)
+		if err != nil {
+			logs.Error("check lag of replica %s err: %v", replica.url, err)
+			healthy = 0
+		} else if lag > router.maxLag {
+			logs.Warn("replica %s is %d seconds behind the primary", replica.url, lag)
+			healthy = 0
+		}
+		if atomic.SwapInt32(&replica.healthy, healthy) != healthy && healthy == 1 {
+			logs.Info("replica %s is back in service", replica.url)
+		}
+	}
+}
+
+// replicaLag reads Seconds_Behind_Master of the replica. An instance without replication status is not lagging.
+func replicaLag(db *gorm.DB) (int64, error) {
+	rows, err := db.Raw("SHOW SLAVE STATUS").Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+	return parseLag(columns, values)
+}
+
+func parseLag(columns []string, values []sql.RawBytes) (int64, error) {
+	for i, column := range columns {
+		if column != "Seconds_Behind_Master" {
+			continue
+		}
+		if values[i] == nil {
+			return 0, fmt.Errorf("replication is not running")
+		}
+		return strconv.ParseInt(string(values[i]), 10, 64)
+	}
+	return 0, fmt.Errorf("no Seconds_Behind_Master in slave status")
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dbrouter
+
+import (
+	"database/sql"
+	"poly-bridge/conf"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestDSN(t *testing.T) {
+	cfg := &conf.DBConfig{User: "root", Password: "123456", Scheme: "polyswap"}
+	assert.Equal(t, "root:123456@tcp(127.0.0.1:3306)/polyswap?charset=utf8", DSN(cfg, "127.0.0.1:3306"))
+	cfg.Timeout = 5
+	assert.Equal(t, "root:123456@tcp(127.0.0.1:3306)/polyswap?charset=utf8&timeout=5s&readTimeout=5s&writeTimeout=5s", DSN(cfg, "127.0.0.1:3306"))
+}
+
+func TestParseLag(t *testing.T) {
+	columns := []string{"Slave_IO_State", "Seconds_Behind_Master"}
+	lag, err := parseLag(columns, []sql.RawBytes{sql.RawBytes("Waiting"), sql.RawBytes("12")})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), lag)
+	_, err = parseLag(columns, []sql.RawBytes{sql.RawBytes("Waiting"), nil})
+	assert.NotNil(t, err)
+	_, err = parseLag(columns[:1], []sql.RawBytes{sql.RawBytes("Waiting")})
+	assert.NotNil(t, err)
+}
+
+func TestReader(t *testing.T) {
+	primary, replica1, replica2 := new(gorm.DB), new(gorm.DB), new(gorm.DB)
+	router := &Router{primary: primary}
+	assert.True(t, router.Reader() == primary)
+
+	router.replicas = []*replica{{url: "r1", db: replica1}, {url: "r2", db: replica2}}
+	assert.True(t, router.Reader() == primary)
+
+	router.replicas[1].healthy = 1
+	for i := 0; i < 4; i++ {
+		assert.True(t, router.Reader() == replica2)
+	}
+
+	router.replicas[0].healthy = 1
+	seen := make(map[*gorm.DB]bool)
+	for i := 0; i < 4; i++ {
+		seen[router.Reader()] = true
+	}
+	assert.Equal(t, 2, len(seen))
+	assert.False(t, seen[primary])
+}
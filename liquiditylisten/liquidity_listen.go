@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package liquiditylisten
+
+import (
+	"fmt"
+	"github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"poly-bridge/basedef"
+	"poly-bridge/chainsdk"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const defaultHistoryDays = 30
+
+var liquidityListen *LiquidityListen
+
+func StartLiquidityListen(server string, liquidityListenCfg *conf.LiquidityListenConfig, chainListenCfgs []*conf.ChainListenConfig, dbCfg *conf.DBConfig) {
+	if server != basedef.SERVER_POLY_BRIDGE || liquidityListenCfg == nil {
+		return
+	}
+	if liquidityListenCfg.UpdateSlot <= 0 {
+		panic("liquidity update slot is not valid")
+	}
+	Logger := logger.Default
+	if dbCfg.Debug == true {
+		Logger = Logger.LogMode(logger.Info)
+	}
+	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
+		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	if err != nil {
+		panic(err)
+	}
+	liquidityListen = NewLiquidityListen(liquidityListenCfg, chainListenCfgs, db)
+	liquidityListen.Start()
+}
+
+func StopLiquidityListen() {
+	if liquidityListen != nil {
+		liquidityListen.Stop()
+	}
+}
+
+// LiquidityListen records the balance of the lock proxy in every token of the ethereum like chains,
+// and the total supply of the mintable wrapped tokens.
+type LiquidityListen struct {
+	updateSlot  int64
+	historyDays int64
+	sdks        map[uint64]*chainsdk.EthereumSdkPro
+	proxies     map[uint64]common.Address
+	mintable    map[string]bool
+	db          *gorm.DB
+	exit        chan bool
+}
+
+func NewLiquidityListen(liquidityListenCfg *conf.LiquidityListenConfig, chainListenCfgs []*conf.ChainListenConfig, db *gorm.DB) *LiquidityListen {
+	liquidityListen := &LiquidityListen{
+		updateSlot:  liquidityListenCfg.UpdateSlot,
+		historyDays: liquidityListenCfg.HistoryDays,
+		sdks:        make(map[uint64]*chainsdk.EthereumSdkPro),
+		proxies:     make(map[uint64]common.Address),
+		mintable:    make(map[string]bool),
+		db:          db,
+		exit:        make(chan bool, 0),
+	}
+	if liquidityListen.historyDays <= 0 {
+		liquidityListen.historyDays = defaultHistoryDays
+	}
+	for _, cfg := range chainListenCfgs {
+		switch cfg.ChainId {
+		case basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID, basedef.HECO_CROSSCHAIN_ID:
+			if cfg.ProxyContract == "" {
+				continue
+			}
+			liquidityListen.sdks[cfg.ChainId] = chainsdk.NewEthereumSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+			liquidityListen.proxies[cfg.ChainId] = common.HexToAddress(cfg.ProxyContract)
+		}
+	}
+	for _, token := range liquidityListenCfg.MintableTokens {
+		liquidityListen.mintable[tokenKey(token.ChainId, token.Hash)] = true
+	}
+	return liquidityListen
+}
+
+func tokenKey(chainId uint64, hash string) string {
+	return fmt.Sprintf("%d:%s", chainId, strings.ToLower(hash))
+}
+
+func (ll *LiquidityListen) Start() {
+	logs.Info("start liquidity listen.")
+	go ll.ListenLiquidity()
+}
+
+func (ll *LiquidityListen) Stop() {
+	ll.exit <- true
+	logs.Info("stop liquidity listen.")
+}
+
+func (ll *LiquidityListen) ListenLiquidity() {
+	for {
+		exit := ll.listenLiquidity()
+		if exit {
+			close(ll.exit)
+			break
+		}
+		time.Sleep(time.Second * 5)
+	}
+}
+
+func (ll *LiquidityListen) listenLiquidity() (exit bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("service start, recover info: %s", string(debug.Stack()))
+			exit = false
+		}
+	}()
+
+	logs.Debug("liquidity listen......")
+	ticker := time.NewTicker(time.Minute)
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now().Unix() / 60
+			if now%ll.updateSlot != 0 {
+				continue
+			}
+			logs.Info("do liquidity update at time: %s", time.Now().Format("2006-01-02 15:04:05"))
+			if err := ll.updateLiquidity(); err != nil {
+				logs.Error("update liquidity err: %v", err)
+			}
+		case <-ll.exit:
+			logs.Info("liquidity listen exit......")
+			return true
+		}
+	}
+}
+
+func (ll *LiquidityListen) updateLiquidity() error {
+	tokens := make([]*models.Token, 0)
+	res := ll.db.Where("standard = ?", models.TokenTypeErc20).Find(&tokens)
+	if res.Error != nil {
+		return res.Error
+	}
+	now := time.Now().Unix()
+	liquidities := make([]*models.TokenLiquidity, 0)
+	for _, token := range tokens {
+		sdk, ok := ll.sdks[token.ChainId]
+		if !ok {
+			continue
+		}
+		asset := common.HexToAddress(token.Hash)
+		balance, err := sdk.Balance(asset, ll.proxies[token.ChainId])
+		if err != nil {
+			logs.Error("get lock proxy balance of token %s on chain %d err: %v", token.Hash, token.ChainId, err)
+			continue
+		}
+		liquidity := &models.TokenLiquidity{
+			ChainId:        token.ChainId,
+			Hash:           token.Hash,
+			TokenBasicName: token.TokenBasicName,
+			Precision:      token.Precision,
+			Balance:        models.NewBigInt(balance),
+			TotalSupply:    models.NewBigIntFromInt(0),
+			Time:           now,
+		}
+		if ll.mintable[tokenKey(token.ChainId, token.Hash)] {
+			totalSupply, err := sdk.ERC20TotalSupply(asset)
+			if err != nil {
+				logs.Error("get total supply of token %s on chain %d err: %v", token.Hash, token.ChainId, err)
+				continue
+			}
+			liquidity.Mintable = true
+			liquidity.TotalSupply = models.NewBigInt(totalSupply)
+		}
+		liquidities = append(liquidities, liquidity)
+	}
+	if len(liquidities) > 0 {
+		if err := ll.db.Create(liquidities).Error; err != nil {
+			return err
+		}
+	}
+	return ll.deleteHistory(now)
+}
+
+// deleteHistory deletes the records older than the history days.
+func (ll *LiquidityListen) deleteHistory(now int64) error {
+	res := ll.db.Where("time < ?", now-ll.historyDays*24*60*60).Delete(&models.TokenLiquidity{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		logs.Info("delete %d token liquidities older than %d days", res.RowsAffected, ll.historyDays)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+const (
+	AuditActionAdd      = "add"
+	AuditActionUpdate   = "update"
+	AuditActionRemove   = "remove"
+	AuditActionProperty = "property"
+)
+
+// AuditLog records a change made through the admin api, Before and After are the json of the row.
+type AuditLog struct {
+	Id       int64  `gorm:"primaryKey;autoIncrement"`
+	Operator string `gorm:"index;size:64;not null"`
+	Action   string `gorm:"size:16;not null"`
+	Table    string `gorm:"index:idx_audit_log;size:32;not null"`
+	Key      string `gorm:"index:idx_audit_log;size:256;not null"`
+	Before   string `gorm:"type:text"`
+	After    string `gorm:"type:text"`
+	Time     int64  `gorm:"index;type:bigint(20);not null"`
+}
+
+type AdminTokenBasicReq struct {
+	Name string
+}
+
+type AdminTokenReq struct {
+	ChainId uint64
+	Hash    string
+}
+
+type AdminTokenMapReq struct {
+	SrcChainId   uint64
+	SrcTokenHash string
+	DstChainId   uint64
+	DstTokenHash string
+}
+
+type AdminTokenMapsReq struct {
+	TokenMaps []*TokenMap
+}
+
+type AdminPriceMarketReq struct {
+	TokenBasicName string
+	MarketName     string
+}
+
+type AdminChainFeeReq struct {
+	ChainId uint64
+}
+
+// AdminPropertyReq enables or disables a token basic (Name), a token (ChainId, Hash) or a token map (all of them but Name).
+type AdminPropertyReq struct {
+	Table        string
+	Name         string
+	ChainId      uint64
+	Hash         string
+	DstChainId   uint64
+	DstTokenHash string
+	Property     int64
+}
+
+type AuditLogsReq struct {
+	PageSize int
+	PageNo   int
+	Table    string
+	Key      string
+}
+
+type AuditLogsRsp struct {
+	PageSize   int
+	PageNo     int
+	TotalPage  int
+	TotalCount int
+	AuditLogs  []*AuditLog
+}
+
+func MakeAuditLogsRsp(pageSize int, pageNo int, totalPage int, totalCount int, auditLogs []*AuditLog) *AuditLogsRsp {
+	auditLogsRsp := &AuditLogsRsp{
+		PageSize:   pageSize,
+		PageNo:     pageNo,
+		TotalPage:  totalPage,
+		TotalCount: totalCount,
+		AuditLogs:  auditLogs,
+	}
+	return auditLogsRsp
+}
+
+type TokenMapVerificationsReq struct {
+	PageSize int
+	PageNo   int
+	Mismatch bool // only the mismatched and failed token maps, not the unverified ones
+}
+
+type TokenMapVerificationsRsp struct {
+	PageSize      int
+	PageNo        int
+	TotalPage     int
+	TotalCount    int
+	Verifications []*TokenMapVerification
+}
+
+func MakeTokenMapVerificationsRsp(pageSize int, pageNo int, totalPage int, totalCount int, verifications []*TokenMapVerification) *TokenMapVerificationsRsp {
+	tokenMapVerificationsRsp := &TokenMapVerificationsRsp{
+		PageSize:      pageSize,
+		PageNo:        pageNo,
+		TotalPage:     totalPage,
+		TotalCount:    totalCount,
+		Verifications: verifications,
+	}
+	return tokenMapVerificationsRsp
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+const (
+	ApiKeyTierPublic  = "public"
+	ApiKeyTierRelayer = "relayer"
+)
+
+// ApiKey identifies a client of the api. Rate and Burst configure its token bucket, 0 takes the default of the api,
+// Quota limits its requests a day, 0 means no limit. The relayer tier may call the relayer endpoints.
+type ApiKey struct {
+	Id       int64  `gorm:"primaryKey;autoIncrement"`
+	Key      string `gorm:"uniqueIndex;size:64;not null"`
+	Name     string `gorm:"uniqueIndex;size:64;not null"`
+	Tier     string `gorm:"size:16;not null"`
+	Rate     int64  `gorm:"type:bigint(20);not null"`
+	Burst    int64  `gorm:"type:bigint(20);not null"`
+	Quota    int64  `gorm:"type:bigint(20);not null"`
+	Property int64  `gorm:"type:bigint(20);not null"`
+	Time     int64  `gorm:"type:bigint(20);not null"`
+}
+
+type AdminApiKeyReq struct {
+	Name string
+}
+
+// AdminUpdateApiKeyReq updates the api key of Name, only the fields which are set are changed.
+type AdminUpdateApiKeyReq struct {
+	Name     string
+	Tier     *string
+	Rate     *int64
+	Burst    *int64
+	Quota    *int64
+	Property *int64
+}
@@ -19,13 +19,23 @@ package models
 
 import (
 	"math/big"
+	"strings"
 )
 
 const (
 	TokenTypeErc20 uint8 = iota
 	TokenTypeErc721
+	TokenTypeErc1155
 )
 
+// NFTStandards are the standards of the NFT tokens to query with "standard in ?", they are not a []uint8
+// which is taken as bytes.
+var NFTStandards = []interface{}{TokenTypeErc721, TokenTypeErc1155}
+
+func IsNFTStandard(standard uint8) bool {
+	return standard == TokenTypeErc721 || standard == TokenTypeErc1155
+}
+
 type TokenBasic struct {
 	Id int64  `gorm:"primaryKey;autoIncrement"`
 	Name            string         `gorm:"uniqueIndex;size:64;not null"`
@@ -62,6 +72,72 @@ type ChainFee struct {
 	ProxyFee       *BigInt     `gorm:"type:varchar(64);not null"`
 	Ind            uint64      `gorm:"type:bigint(20);not null"`
 	Time           int64       `gorm:"type:bigint(20);not null"`
+	AssetFees      []*AssetFee `gorm:"foreignKey:ChainId;references:ChainId"`
+}
+
+// AssetFee overrides the fee of a chain for a token standard (Hash is empty) or for a single asset on the chain.
+type AssetFee struct {
+	Id       int64   `gorm:"primaryKey;autoIncrement"`
+	ChainId  uint64  `gorm:"uniqueIndex:idx_asset_fee;type:bigint(20);not null"`
+	Standard uint8   `gorm:"uniqueIndex:idx_asset_fee;type:int(8);not null"`
+	Hash     string  `gorm:"uniqueIndex:idx_asset_fee;size:66;not null"`
+	MaxFee   *BigInt `gorm:"type:varchar(64);not null"`
+	MinFee   *BigInt `gorm:"type:varchar(64);not null"`
+	ProxyFee *BigInt `gorm:"type:varchar(64);not null"`
+	Ind      uint64  `gorm:"type:bigint(20);not null"`
+	Time     int64   `gorm:"type:bigint(20);not null"`
+}
+
+// Select returns the fee of unlocking the asset hash of the given standard on this chain.
+// An override of the asset takes precedence over an override of the standard, and the chain fee is used otherwise.
+func (chainFee *ChainFee) Select(standard uint8, hash string) *ChainFee {
+	var standardFee, assetFee *AssetFee
+	for _, fee := range chainFee.AssetFees {
+		if fee.Ind == 0 {
+			continue
+		}
+		if fee.Hash == "" {
+			if fee.Standard == standard {
+				standardFee = fee
+			}
+		} else if hash != "" && strings.EqualFold(fee.Hash, hash) {
+			assetFee = fee
+		}
+	}
+	if assetFee == nil {
+		assetFee = standardFee
+	}
+	if assetFee == nil {
+		return chainFee
+	}
+	fee := *chainFee
+	fee.MaxFee = assetFee.MaxFee
+	fee.MinFee = assetFee.MinFee
+	fee.ProxyFee = assetFee.ProxyFee
+	return &fee
+}
+
+// HasAssetFee reports whether the chain has a fee override for the asset hash.
+func (chainFee *ChainFee) HasAssetFee(hash string) bool {
+	for _, fee := range chainFee.AssetFees {
+		if fee.Ind != 0 && fee.Hash != "" && strings.EqualFold(fee.Hash, hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scale returns the fee of unlocking with gasLimit, the fee of this chain is for baseGasLimit.
+func (chainFee *ChainFee) Scale(gasLimit int64, baseGasLimit int64) *ChainFee {
+	scale := func(value *BigInt) *BigInt {
+		x := new(big.Int).Mul(&value.Int, big.NewInt(gasLimit))
+		return NewBigInt(new(big.Int).Div(x, big.NewInt(baseGasLimit)))
+	}
+	fee := *chainFee
+	fee.MaxFee = scale(chainFee.MaxFee)
+	fee.MinFee = scale(chainFee.MinFee)
+	fee.ProxyFee = scale(chainFee.ProxyFee)
+	return &fee
 }
 
 type Token struct {
@@ -99,6 +175,7 @@ type WrapperTransactionWithToken struct {
 	DstChainId   uint64  `gorm:"type:bigint(20);not null"`
 	DstUser      string  `gorm:"type:varchar(66);not null"`
 	ServerId     uint64  `gorm:"type:bigint(20);not null"`
+	Standard     uint8   `gorm:"type:int(8);not null"`
 	FeeTokenHash string  `gorm:"size:66;not null"`
 	FeeToken     *Token  `gorm:"foreignKey:FeeTokenHash,SrcChainId;references:Hash,ChainId"`
 	FeeAmount    *BigInt `gorm:"type:varchar(64);not null"`
@@ -111,4 +188,5 @@ type CheckFee struct {
 	PayState    int
 	Amount      *big.Float
 	MinProxyFee *big.Float
+	PolicyId    int64
 }
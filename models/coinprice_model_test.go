@@ -0,0 +1,85 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainFeeSelect(t *testing.T) {
+	chainFee := &ChainFee{
+		ChainId:  2,
+		MinFee:   NewBigIntFromInt(10),
+		MaxFee:   NewBigIntFromInt(20),
+		ProxyFee: NewBigIntFromInt(30),
+		AssetFees: []*AssetFee{
+			{
+				ChainId:  2,
+				Standard: TokenTypeErc721,
+				MinFee:   NewBigIntFromInt(100),
+				MaxFee:   NewBigIntFromInt(200),
+				ProxyFee: NewBigIntFromInt(300),
+				Ind:      1,
+			},
+			{
+				ChainId:  2,
+				Standard: TokenTypeErc20,
+				Hash:     "dac17f958d2ee523a2206206994597c13d831ec7",
+				MinFee:   NewBigIntFromInt(11),
+				MaxFee:   NewBigIntFromInt(21),
+				ProxyFee: NewBigIntFromInt(31),
+				Ind:      1,
+			},
+			{
+				ChainId:  2,
+				Standard: TokenTypeErc20,
+				Hash:     "a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+				MinFee:   NewBigIntFromInt(12),
+				MaxFee:   NewBigIntFromInt(22),
+				ProxyFee: NewBigIntFromInt(32),
+				Ind:      0,
+			},
+		},
+	}
+
+	var testdata = []struct {
+		standard uint8
+		hash     string
+		expect   int64
+	}{
+		{standard: TokenTypeErc20, hash: "", expect: 30},
+		{standard: TokenTypeErc20, hash: "DAC17F958D2EE523A2206206994597C13D831EC7", expect: 31},
+		{standard: TokenTypeErc20, hash: "a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", expect: 30},
+		{standard: TokenTypeErc721, hash: "", expect: 300},
+		{standard: TokenTypeErc721, hash: "dac17f958d2ee523a2206206994597c13d831ec7", expect: 31},
+	}
+
+	for _, v := range testdata {
+		fee := chainFee.Select(v.standard, v.hash)
+		assert.Equal(t, v.expect, fee.ProxyFee.Int64())
+		assert.Equal(t, chainFee.ChainId, fee.ChainId)
+	}
+	assert.Equal(t, int64(30), chainFee.ProxyFee.Int64())
+}
+
+func TestChainFeeScale(t *testing.T) {
+	chainFee := &ChainFee{
+		ChainId:  2,
+		MinFee:   NewBigIntFromInt(100),
+		MaxFee:   NewBigIntFromInt(200),
+		ProxyFee: NewBigIntFromInt(300),
+		AssetFees: []*AssetFee{
+			{ChainId: 2, Standard: TokenTypeErc721, Hash: "dac17f958d2ee523a2206206994597c13d831ec7", Ind: 1},
+			{ChainId: 2, Standard: TokenTypeErc721, Hash: "a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Ind: 0},
+		},
+	}
+	fee := chainFee.Scale(150000, 300000)
+	assert.Equal(t, int64(50), fee.MinFee.Int64())
+	assert.Equal(t, int64(100), fee.MaxFee.Int64())
+	assert.Equal(t, int64(150), fee.ProxyFee.Int64())
+	assert.Equal(t, int64(300), chainFee.ProxyFee.Int64())
+
+	assert.True(t, chainFee.HasAssetFee("DAC17F958D2EE523A2206206994597C13D831EC7"))
+	assert.False(t, chainFee.HasAssetFee("a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"))
+	assert.False(t, chainFee.HasAssetFee(""))
+}
@@ -53,6 +53,8 @@ type SrcTransfer struct {
 	From       string  `gorm:"type:varchar(66);not null"`
 	To         string  `gorm:"type:varchar(66);not null"`
 	Amount     *BigInt `gorm:"type:varchar(64);not null"`
+	TokenId    *BigInt `gorm:"type:varchar(80);default:'null';not null"`
+	Quantity   *BigInt `gorm:"type:varchar(80);default:'null';not null"`
 	DstChainId uint64  `gorm:"type:bigint(20);not null"`
 	DstAsset   string  `gorm:"type:varchar(66);not null"`
 	DstUser    string  `gorm:"type:varchar(66);not null"`
@@ -121,6 +123,8 @@ type DstTransfer struct {
 	From     string  `gorm:"type:varchar(66);not null"`
 	To       string  `gorm:"type:varchar(66);not null"`
 	Amount   *BigInt `gorm:"type:varchar(64);not null"`
+	TokenId  *BigInt `gorm:"type:varchar(80);default:'null';not null"`
+	Quantity *BigInt `gorm:"type:varchar(80);default:'null';not null"`
 }
 
 type DstSwap struct {
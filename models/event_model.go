@@ -48,6 +48,7 @@ type ProxyLockEvent struct {
 	ToAssetHash   string
 	ToAddress     string
 	Amount        *big.Int
+	Quantity      *big.Int // quantity of the ERC1155 token, Amount is the token id
 }
 type ProxyUnlockEvent struct {
 	Method      string
@@ -55,6 +56,20 @@ type ProxyUnlockEvent struct {
 	ToAssetHash string
 	ToAddress   string
 	Amount      *big.Int
+	Quantity    *big.Int // quantity of the ERC1155 token, Amount is the token id
+}
+
+// NFTTransferEvent is an ERC721 Transfer or a transfer of the ERC1155 TransferSingle and TransferBatch events,
+// addresses are lower case hex without 0x.
+type NFTTransferEvent struct {
+	Standard uint8
+	ChainId  uint64
+	Asset    string
+	TokenId  *big.Int
+	From     string
+	To       string
+	Quantity *big.Int
+	Height   uint64
 }
 
 type SwapEvent struct {
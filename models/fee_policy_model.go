@@ -0,0 +1,191 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+import (
+	"fmt"
+	"math/big"
+	"poly-bridge/basedef"
+	"strings"
+)
+
+// FeePolicy adjusts the fee of the cross chain transfers it matches.
+// Empty conditions match everything. A version is in effect between StartTime and EndTime when they are set,
+// and the highest version of a policy name in effect replaces the others, so a version which has expired or not
+// started yet leaves the previous one in effect. Disabling the highest version in effect disables the policy.
+type FeePolicy struct {
+	Id             int64  `gorm:"primaryKey;autoIncrement"`
+	Name           string `gorm:"uniqueIndex:idx_fee_policy;size:64;not null"`
+	Version        uint64 `gorm:"uniqueIndex:idx_fee_policy;type:bigint(20);not null"`
+	SrcChainId     uint64 `gorm:"type:bigint(20);not null"`
+	DstChainId     uint64 `gorm:"type:bigint(20);not null"`
+	TokenBasicName string `gorm:"size:64;not null"`
+	User           string `gorm:"type:varchar(66);not null"` // whitelisted sender
+	Free           bool   `gorm:"not null"`
+	Discount       int64  `gorm:"type:bigint(20);not null"` // percentage taken off the fee
+	MinUsd         int64  `gorm:"type:bigint(20);not null"` // multiplied by PRICE_PRECISION, 0 for no floor
+	MaxUsd         int64  `gorm:"type:bigint(20);not null"` // multiplied by PRICE_PRECISION, 0 for no cap
+	StartTime      int64  `gorm:"type:bigint(20);not null"`
+	EndTime        int64  `gorm:"type:bigint(20);not null"`
+	Ind            uint64 `gorm:"type:bigint(20);not null"`
+}
+
+// Validate checks the policy can be applied, the discount is a percentage and the usd limits and times are ordered.
+func (policy *FeePolicy) Validate() error {
+	if policy.Name == "" {
+		return fmt.Errorf("fee policy name is empty")
+	}
+	if policy.Discount < 0 || policy.Discount > 100 {
+		return fmt.Errorf("discount %d of fee policy %s is not between 0 and 100", policy.Discount, policy.Name)
+	}
+	if policy.MinUsd < 0 || policy.MaxUsd < 0 {
+		return fmt.Errorf("min usd or max usd of fee policy %s is negative", policy.Name)
+	}
+	if policy.MaxUsd > 0 && policy.MaxUsd < policy.MinUsd {
+		return fmt.Errorf("max usd of fee policy %s is less than min usd", policy.Name)
+	}
+	if policy.EndTime != 0 && policy.EndTime <= policy.StartTime {
+		return fmt.Errorf("end time of fee policy %s is not after start time", policy.Name)
+	}
+	return nil
+}
+
+// Match returns whether the policy applies to the transfer at the time.
+func (policy *FeePolicy) Match(srcChainId uint64, dstChainId uint64, tokenBasicName string, user string, now int64) bool {
+	if policy.Ind != 1 || !policy.inEffect(now) {
+		return false
+	}
+	if policy.SrcChainId != 0 && policy.SrcChainId != srcChainId {
+		return false
+	}
+	if policy.DstChainId != 0 && policy.DstChainId != dstChainId {
+		return false
+	}
+	if policy.TokenBasicName != "" && policy.TokenBasicName != tokenBasicName {
+		return false
+	}
+	if policy.User != "" && strings.TrimPrefix(strings.ToLower(policy.User), "0x") != strings.TrimPrefix(strings.ToLower(user), "0x") {
+		return false
+	}
+	return true
+}
+
+func (policy *FeePolicy) inEffect(now int64) bool {
+	return (policy.StartTime == 0 || now >= policy.StartTime) && (policy.EndTime == 0 || now < policy.EndTime)
+}
+
+func (policy *FeePolicy) specificity() int {
+	specificity := 0
+	if policy.User != "" {
+		specificity += 8
+	}
+	if policy.TokenBasicName != "" {
+		specificity += 4
+	}
+	if policy.SrcChainId != 0 {
+		specificity += 2
+	}
+	if policy.DstChainId != 0 {
+		specificity += 1
+	}
+	return specificity
+}
+
+// Apply returns the usd fee after the policy is applied.
+func (policy *FeePolicy) Apply(usdFee *big.Float) *big.Float {
+	if policy.Free {
+		return new(big.Float).SetInt64(0)
+	}
+	fee := new(big.Float).Set(usdFee)
+	if policy.Discount > 0 {
+		fee = new(big.Float).Mul(fee, new(big.Float).SetInt64(100-policy.Discount))
+		fee = new(big.Float).Quo(fee, new(big.Float).SetInt64(100))
+	}
+	if policy.MinUsd > 0 {
+		minUsd := new(big.Float).Quo(new(big.Float).SetInt64(policy.MinUsd), new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+		if fee.Cmp(minUsd) < 0 {
+			fee = minUsd
+		}
+	}
+	if policy.MaxUsd > 0 {
+		maxUsd := new(big.Float).Quo(new(big.Float).SetInt64(policy.MaxUsd), new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+		if fee.Cmp(maxUsd) > 0 {
+			fee = maxUsd
+		}
+	}
+	return fee
+}
+
+// EvaluateFeePolicy returns the policy in effect for the transfer, the most specific one wins, nil if none matches.
+func EvaluateFeePolicy(policies []*FeePolicy, srcChainId uint64, dstChainId uint64, tokenBasicName string, user string, now int64) *FeePolicy {
+	name2Policies := make(map[string]*FeePolicy)
+	for _, policy := range policies {
+		if !policy.inEffect(now) {
+			continue
+		}
+		if latest, ok := name2Policies[policy.Name]; !ok || policy.Version > latest.Version {
+			name2Policies[policy.Name] = policy
+		}
+	}
+	var applied *FeePolicy
+	for _, policy := range name2Policies {
+		if !policy.Match(srcChainId, dstChainId, tokenBasicName, user, now) {
+			continue
+		}
+		if applied == nil || policy.specificity() > applied.specificity() ||
+			(policy.specificity() == applied.specificity() && policy.Id > applied.Id) {
+			applied = policy
+		}
+	}
+	return applied
+}
+
+// MinFeeUsd is the least fee in usd a transfer to the chain of chainFee must pay, after the fee policy is applied.
+// It returns the id of the policy applied, 0 if none.
+func MinFeeUsd(chainFee *ChainFee, feePolicies []*FeePolicy, srcChainId uint64, dstChainId uint64, tokenBasicName string, user string, now int64) (*big.Float, int64) {
+	x := new(big.Int).Mul(&chainFee.MinFee.Int, big.NewInt(chainFee.TokenBasic.Price))
+	feeMin := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+	feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.FEE_PRECISION))
+	feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
+	feePolicy := EvaluateFeePolicy(feePolicies, srcChainId, dstChainId, tokenBasicName, user, now)
+	if feePolicy != nil {
+		return feePolicy.Apply(feeMin), feePolicy.Id
+	}
+	return feeMin, 0
+}
+
+// CheckPaidFee sets the fee paid by the wrapper transaction and the least fee of chainFee to checkFee, the pay
+// state is 1 if the fee paid is enough, -1 otherwise or if the fee token of the transaction is unknown.
+func CheckPaidFee(checkFee *CheckFee, wrapper *WrapperTransactionWithToken, chainFee *ChainFee, feePolicies []*FeePolicy, tokenBasicName string, now int64) {
+	if wrapper.FeeToken == nil || wrapper.FeeToken.TokenBasic == nil {
+		checkFee.PayState = -1
+		return
+	}
+	x := new(big.Int).Mul(&wrapper.FeeAmount.Int, big.NewInt(wrapper.FeeToken.TokenBasic.Price))
+	feePay := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.Int64FromFigure(int(wrapper.FeeToken.Precision))))
+	feePay = new(big.Float).Quo(feePay, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+	feeMin, policyId := MinFeeUsd(chainFee, feePolicies, wrapper.SrcChainId, wrapper.DstChainId, tokenBasicName, wrapper.User, now)
+	checkFee.PolicyId = policyId
+	checkFee.Amount = feePay
+	checkFee.MinProxyFee = feeMin
+	if feePay.Cmp(feeMin) >= 0 {
+		checkFee.PayState = 1
+	} else {
+		checkFee.PayState = -1
+	}
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateFeePolicy(t *testing.T) {
+	policies := []*FeePolicy{
+		{Id: 1, Name: "discount", Version: 1, Discount: 50, Ind: 1},
+		{Id: 2, Name: "heco-bsc", Version: 1, SrcChainId: 7, DstChainId: 6, TokenBasicName: "USDT", Free: true, Ind: 1},
+		{Id: 3, Name: "heco-bsc", Version: 2, SrcChainId: 7, DstChainId: 6, TokenBasicName: "USDT", Free: true, StartTime: 100, EndTime: 200, Ind: 1},
+		{Id: 4, Name: "whitelist", Version: 1, User: "0xABCDEF", Free: true, Ind: 1},
+		{Id: 5, Name: "floor", Version: 1, DstChainId: 2, MinUsd: 5 * 100000000, Ind: 1},
+		{Id: 6, Name: "disabled", Version: 1, SrcChainId: 7, Free: true, Ind: 0},
+	}
+
+	var testdata = []struct {
+		srcChainId     uint64
+		dstChainId     uint64
+		tokenBasicName string
+		user           string
+		now            int64
+		expect         int64
+	}{
+		{srcChainId: 7, dstChainId: 6, tokenBasicName: "USDT", now: 150, expect: 3},
+		{srcChainId: 7, dstChainId: 6, tokenBasicName: "USDT", now: 50, expect: 2},
+		{srcChainId: 7, dstChainId: 6, tokenBasicName: "USDT", now: 250, expect: 2},
+		{srcChainId: 7, dstChainId: 6, tokenBasicName: "ETH", now: 150, expect: 1},
+		{srcChainId: 7, dstChainId: 6, tokenBasicName: "USDT", user: "abcdef", now: 150, expect: 4},
+		{srcChainId: 6, dstChainId: 2, tokenBasicName: "ETH", now: 150, expect: 5},
+		{srcChainId: 7, dstChainId: 2, tokenBasicName: "ETH", now: 150, expect: 5},
+	}
+
+	for _, v := range testdata {
+		policy := EvaluateFeePolicy(policies, v.srcChainId, v.dstChainId, v.tokenBasicName, v.user, v.now)
+		assert.Equal(t, v.expect, policy.Id)
+	}
+	assert.Nil(t, EvaluateFeePolicy(policies[5:], 7, 2, "ETH", "", 150))
+	// disabling the version in effect disables the policy
+	disabled := append(policies[:2:2], &FeePolicy{Id: 7, Name: "heco-bsc", Version: 2, Ind: 0})
+	assert.Equal(t, int64(1), EvaluateFeePolicy(disabled, 7, 6, "USDT", "", 150).Id)
+}
+
+func TestFeePolicyApply(t *testing.T) {
+	var testdata = []struct {
+		policy *FeePolicy
+		fee    float64
+		expect float64
+	}{
+		{policy: &FeePolicy{Free: true}, fee: 10, expect: 0},
+		{policy: &FeePolicy{Discount: 20}, fee: 10, expect: 8},
+		{policy: &FeePolicy{MinUsd: 5 * 100000000}, fee: 2, expect: 5},
+		{policy: &FeePolicy{MaxUsd: 5 * 100000000}, fee: 10, expect: 5},
+		{policy: &FeePolicy{Discount: 90, MinUsd: 2 * 100000000}, fee: 10, expect: 2},
+	}
+
+	for _, v := range testdata {
+		fee, _ := v.policy.Apply(big.NewFloat(v.fee)).Float64()
+		assert.InDelta(t, v.expect, fee, 1e-9)
+	}
+}
+
+func TestFeePolicyValidate(t *testing.T) {
+	var testdata = []struct {
+		policy *FeePolicy
+		valid  bool
+	}{
+		{policy: &FeePolicy{Name: "free", Free: true}, valid: true},
+		{policy: &FeePolicy{Name: "discount", Discount: 100}, valid: true},
+		{policy: &FeePolicy{Discount: 20}, valid: false},
+		{policy: &FeePolicy{Name: "discount", Discount: -1}, valid: false},
+		{policy: &FeePolicy{Name: "discount", Discount: 101}, valid: false},
+		{policy: &FeePolicy{Name: "limit", MinUsd: 5, MaxUsd: 2}, valid: false},
+		{policy: &FeePolicy{Name: "limit", MinUsd: -5}, valid: false},
+		{policy: &FeePolicy{Name: "time", StartTime: 200, EndTime: 100}, valid: false},
+		{policy: &FeePolicy{Name: "time", StartTime: 100, EndTime: 200}, valid: true},
+	}
+
+	for _, v := range testdata {
+		assert.Equal(t, v.valid, v.policy.Validate() == nil)
+	}
+}
+
+func TestCheckPaidFee(t *testing.T) {
+	// the least fee is 2 usd, the fee token is 1 usd with 6 decimals
+	chainFee := &ChainFee{ChainId: 2, MinFee: NewBigIntFromInt(2 * 100000000), TokenBasic: &TokenBasic{Price: 100000000}}
+	feeToken := &Token{Precision: 6, TokenBasic: &TokenBasic{Price: 100000000}}
+	policies := []*FeePolicy{{Id: 7, Name: "floor", Version: 1, SrcChainId: 6, MinUsd: 4 * 100000000, Ind: 1}}
+
+	var testdata = []struct {
+		srcChainId uint64
+		feeAmount  int64
+		feeToken   *Token
+		payState   int
+		minFee     float64
+		policyId   int64
+	}{
+		{srcChainId: 7, feeAmount: 3000000, feeToken: feeToken, payState: 1, minFee: 2},
+		{srcChainId: 7, feeAmount: 2000000, feeToken: feeToken, payState: 1, minFee: 2},
+		{srcChainId: 7, feeAmount: 1000000, feeToken: feeToken, payState: -1, minFee: 2},
+		{srcChainId: 6, feeAmount: 3000000, feeToken: feeToken, payState: -1, minFee: 4, policyId: 7},
+		{srcChainId: 7, feeAmount: 3000000, payState: -1},
+		{srcChainId: 7, feeAmount: 3000000, feeToken: &Token{Precision: 6}, payState: -1},
+	}
+
+	for i, v := range testdata {
+		checkFee := &CheckFee{Amount: new(big.Float), MinProxyFee: new(big.Float)}
+		wrapper := &WrapperTransactionWithToken{SrcChainId: v.srcChainId, DstChainId: 2, FeeToken: v.feeToken, FeeAmount: NewBigIntFromInt(v.feeAmount)}
+		CheckPaidFee(checkFee, wrapper, chainFee, policies, "ETH", 150)
+		assert.Equal(t, v.payState, checkFee.PayState, i)
+		assert.Equal(t, v.policyId, checkFee.PolicyId, i)
+		minFee, _ := checkFee.MinProxyFee.Float64()
+		assert.InDelta(t, v.minFee, minFee, 1e-9, i)
+	}
+}
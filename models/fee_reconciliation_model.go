@@ -0,0 +1,178 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+import (
+	"math/big"
+	"poly-bridge/basedef"
+	"sort"
+)
+
+const (
+	ReconciliationPeriodDay  = "day"
+	ReconciliationPeriodWeek = "week"
+)
+
+// TokenPriceHistory keeps every price of a token basic saved by the coin price listener.
+type TokenPriceHistory struct {
+	Id             int64  `gorm:"primaryKey;autoIncrement"`
+	TokenBasicName string `gorm:"index:idx_price_history;size:64;not null"`
+	Price          int64  `gorm:"type:bigint(20);not null"`
+	Time           int64  `gorm:"index:idx_price_history;type:bigint(20);not null"`
+}
+
+// FeeReconciliation compares the fee collected by a finished transfer with the fee paid to relay it.
+// All usd amounts are multiplied by PRICE_PRECISION, Ind is 1 when the prices of both sides are known.
+type FeeReconciliation struct {
+	Id                 int64   `gorm:"primaryKey;autoIncrement"`
+	SrcHash            string  `gorm:"uniqueIndex;size:66;not null"`
+	DstHash            string  `gorm:"size:66;not null"`
+	SrcChainId         uint64  `gorm:"type:bigint(20);not null"`
+	DstChainId         uint64  `gorm:"type:bigint(20);not null"`
+	FeeTokenBasicName  string  `gorm:"size:64;not null"`
+	FeeAmount          *BigInt `gorm:"type:varchar(64);not null"`
+	FeeUsd             *BigInt `gorm:"type:varchar(64);not null"`
+	CostTokenBasicName string  `gorm:"size:64;not null"`
+	CostAmount         *BigInt `gorm:"type:varchar(64);not null"`
+	CostUsd            *BigInt `gorm:"type:varchar(64);not null"`
+	Margin             *BigInt `gorm:"type:varchar(64);not null"`
+	Ind                uint64  `gorm:"type:bigint(20);not null"`
+	Time               uint64  `gorm:"index;type:bigint(20);not null"`
+}
+
+// TokenUsd converts an amount of token with the given precision to usd at the given price.
+func TokenUsd(amount *big.Int, precision uint64, price int64) *big.Int {
+	usd := new(big.Int).Mul(amount, big.NewInt(price))
+	return usd.Quo(usd, big.NewInt(basedef.Int64FromFigure(int(precision))))
+}
+
+// MakeFeeReconciliation reconciles a wrapper transaction with its destination transaction.
+// feeToken is the token the fee is paid with, costToken is the native token of the destination chain,
+// and the prices are the ones at the time of the source and destination transaction.
+func MakeFeeReconciliation(wrapper *WrapperTransaction, dst *DstTransaction, feeToken *Token, costToken *TokenBasic,
+	feePrice *TokenPriceHistory, costPrice *TokenPriceHistory) *FeeReconciliation {
+	reconciliation := &FeeReconciliation{
+		SrcHash:    wrapper.Hash,
+		DstHash:    dst.Hash,
+		SrcChainId: wrapper.SrcChainId,
+		DstChainId: dst.ChainId,
+		FeeAmount:  NewBigIntFromInt(0),
+		FeeUsd:     NewBigIntFromInt(0),
+		CostAmount: NewBigIntFromInt(0),
+		CostUsd:    NewBigIntFromInt(0),
+		Margin:     NewBigIntFromInt(0),
+		Time:       wrapper.Time,
+	}
+	if wrapper.FeeAmount != nil {
+		reconciliation.FeeAmount = wrapper.FeeAmount
+	}
+	if dst.Fee != nil {
+		reconciliation.CostAmount = dst.Fee
+	}
+	if feeToken != nil {
+		reconciliation.FeeTokenBasicName = feeToken.TokenBasicName
+	}
+	if costToken != nil {
+		reconciliation.CostTokenBasicName = costToken.Name
+	}
+	if feeToken == nil || costToken == nil || feePrice == nil || costPrice == nil {
+		return reconciliation
+	}
+	feeUsd := TokenUsd(&reconciliation.FeeAmount.Int, feeToken.Precision, feePrice.Price)
+	costUsd := TokenUsd(&reconciliation.CostAmount.Int, costToken.Precision, costPrice.Price)
+	reconciliation.FeeUsd = NewBigInt(feeUsd)
+	reconciliation.CostUsd = NewBigInt(costUsd)
+	reconciliation.Margin = NewBigInt(new(big.Int).Sub(feeUsd, costUsd))
+	reconciliation.Ind = 1
+	return reconciliation
+}
+
+type FeeReconciliationStat struct {
+	Period            int64
+	SrcChainId        uint64
+	DstChainId        uint64
+	FeeTokenBasicName string
+	Count             uint64
+	FeeUsd            *big.Int
+	CostUsd           *big.Int
+	Margin            *big.Int
+}
+
+// ReconciliationPeriodStart returns the start of the day or week (starting on monday) the time falls in.
+func ReconciliationPeriodStart(period string, time uint64) int64 {
+	const day = int64(24 * 60 * 60)
+	if period == ReconciliationPeriodWeek {
+		// 1970-01-01 is a thursday
+		return (int64(time)+3*day)/(7*day)*(7*day) - 3*day
+	}
+	return int64(time) / day * day
+}
+
+// AggregateFeeReconciliations sums the reconciliations per period, chain pair and fee token.
+func AggregateFeeReconciliations(reconciliations []*FeeReconciliation, period string) []*FeeReconciliationStat {
+	type statKey struct {
+		period            int64
+		srcChainId        uint64
+		dstChainId        uint64
+		feeTokenBasicName string
+	}
+	key2Stats := make(map[statKey]*FeeReconciliationStat)
+	stats := make([]*FeeReconciliationStat, 0)
+	for _, reconciliation := range reconciliations {
+		if reconciliation.Ind != 1 {
+			continue
+		}
+		key := statKey{
+			period:            ReconciliationPeriodStart(period, reconciliation.Time),
+			srcChainId:        reconciliation.SrcChainId,
+			dstChainId:        reconciliation.DstChainId,
+			feeTokenBasicName: reconciliation.FeeTokenBasicName,
+		}
+		stat, ok := key2Stats[key]
+		if !ok {
+			stat = &FeeReconciliationStat{
+				Period:            key.period,
+				SrcChainId:        key.srcChainId,
+				DstChainId:        key.dstChainId,
+				FeeTokenBasicName: key.feeTokenBasicName,
+				FeeUsd:            new(big.Int),
+				CostUsd:           new(big.Int),
+				Margin:            new(big.Int),
+			}
+			key2Stats[key] = stat
+			stats = append(stats, stat)
+		}
+		stat.Count++
+		stat.FeeUsd.Add(stat.FeeUsd, &reconciliation.FeeUsd.Int)
+		stat.CostUsd.Add(stat.CostUsd, &reconciliation.CostUsd.Int)
+		stat.Margin.Add(stat.Margin, &reconciliation.Margin.Int)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Period != stats[j].Period {
+			return stats[i].Period < stats[j].Period
+		}
+		if stats[i].SrcChainId != stats[j].SrcChainId {
+			return stats[i].SrcChainId < stats[j].SrcChainId
+		}
+		if stats[i].DstChainId != stats[j].DstChainId {
+			return stats[i].DstChainId < stats[j].DstChainId
+		}
+		return stats[i].FeeTokenBasicName < stats[j].FeeTokenBasicName
+	})
+	return stats
+}
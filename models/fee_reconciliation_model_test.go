@@ -0,0 +1,70 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeFeeReconciliation(t *testing.T) {
+	wrapper := &WrapperTransaction{
+		Hash:       "aa",
+		SrcChainId: 2,
+		Time:       1000,
+		FeeAmount:  NewBigInt(new(big.Int).Mul(big.NewInt(2), big.NewInt(1e18))),
+	}
+	dst := &DstTransaction{
+		Hash:    "bb",
+		ChainId: 6,
+		Time:    1100,
+		Fee:     NewBigInt(big.NewInt(5e17)),
+	}
+	feeToken := &Token{TokenBasicName: "ETH", Precision: 18}
+	costToken := &TokenBasic{Name: "BNB", Precision: 18}
+	feePrice := &TokenPriceHistory{TokenBasicName: "ETH", Price: 2000 * 100000000}
+	costPrice := &TokenPriceHistory{TokenBasicName: "BNB", Price: 300 * 100000000}
+
+	reconciliation := MakeFeeReconciliation(wrapper, dst, feeToken, costToken, feePrice, costPrice)
+	assert.Equal(t, uint64(1), reconciliation.Ind)
+	assert.Equal(t, "400000000000", reconciliation.FeeUsd.String())
+	assert.Equal(t, "15000000000", reconciliation.CostUsd.String())
+	assert.Equal(t, "385000000000", reconciliation.Margin.String())
+	assert.Equal(t, "ETH", reconciliation.FeeTokenBasicName)
+	assert.Equal(t, "BNB", reconciliation.CostTokenBasicName)
+
+	reconciliation = MakeFeeReconciliation(wrapper, dst, feeToken, costToken, feePrice, nil)
+	assert.Equal(t, uint64(0), reconciliation.Ind)
+	assert.Equal(t, int64(0), reconciliation.Margin.Int64())
+}
+
+func TestAggregateFeeReconciliations(t *testing.T) {
+	// 2021-03-01 is a monday
+	monday := uint64(1614556800)
+	day := uint64(24 * 60 * 60)
+	reconciliations := []*FeeReconciliation{
+		{SrcChainId: 2, DstChainId: 6, FeeTokenBasicName: "ETH", FeeUsd: NewBigIntFromInt(10), CostUsd: NewBigIntFromInt(4), Margin: NewBigIntFromInt(6), Ind: 1, Time: monday + 10},
+		{SrcChainId: 2, DstChainId: 6, FeeTokenBasicName: "ETH", FeeUsd: NewBigIntFromInt(3), CostUsd: NewBigIntFromInt(5), Margin: NewBigIntFromInt(-2), Ind: 1, Time: monday + 2*day},
+		{SrcChainId: 6, DstChainId: 2, FeeTokenBasicName: "BNB", FeeUsd: NewBigIntFromInt(1), CostUsd: NewBigIntFromInt(1), Margin: NewBigIntFromInt(0), Ind: 1, Time: monday + day},
+		{SrcChainId: 2, DstChainId: 6, FeeTokenBasicName: "ETH", FeeUsd: NewBigIntFromInt(0), CostUsd: NewBigIntFromInt(0), Margin: NewBigIntFromInt(0), Ind: 0, Time: monday},
+	}
+
+	var testdata = []struct {
+		period  string
+		expects []int64
+		counts  []uint64
+	}{
+		{period: ReconciliationPeriodDay, expects: []int64{6, 0, -2}, counts: []uint64{1, 1, 1}},
+		{period: ReconciliationPeriodWeek, expects: []int64{4, 0}, counts: []uint64{2, 1}},
+	}
+
+	for _, v := range testdata {
+		stats := AggregateFeeReconciliations(reconciliations, v.period)
+		assert.Equal(t, len(v.expects), len(stats))
+		for i, stat := range stats {
+			assert.Equal(t, v.expects[i], stat.Margin.Int64())
+			assert.Equal(t, v.counts[i], stat.Count)
+		}
+		assert.Equal(t, int64(monday), stats[0].Period)
+	}
+}
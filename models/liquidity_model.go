@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+import (
+	"github.com/shopspring/decimal"
+	"sort"
+)
+
+// TokenLiquidity is the balance of the lock proxy in a token at Time. TotalSupply is only recorded for the
+// mintable wrapped tokens. The index idx_token_liquidity_latest finds the latest record of every token without
+// scanning the history.
+type TokenLiquidity struct {
+	Id             int64   `gorm:"primaryKey;autoIncrement;index:idx_token_liquidity_latest,priority:3"`
+	ChainId        uint64  `gorm:"index:idx_token_liquidity;index:idx_token_liquidity_latest,priority:1;type:bigint(20);not null"`
+	Hash           string  `gorm:"index:idx_token_liquidity;index:idx_token_liquidity_latest,priority:2;size:66;not null"`
+	TokenBasicName string  `gorm:"size:64;not null"`
+	Precision      uint64  `gorm:"type:bigint(20);not null"`
+	Balance        *BigInt `gorm:"type:varchar(64);not null"`
+	Mintable       bool    `gorm:"not null"`
+	TotalSupply    *BigInt `gorm:"type:varchar(64);not null"`
+	Time           int64   `gorm:"index:idx_token_liquidity;index;type:bigint(20);not null"`
+}
+
+func (liquidity *TokenLiquidity) amount(value *BigInt) decimal.Decimal {
+	if value == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(&value.Int, -int32(liquidity.Precision))
+}
+
+// Circulating is the amount of a mintable token held outside of the lock proxy.
+func (liquidity *TokenLiquidity) Circulating() decimal.Decimal {
+	if !liquidity.Mintable {
+		return decimal.Zero
+	}
+	return liquidity.amount(liquidity.TotalSupply).Sub(liquidity.amount(liquidity.Balance))
+}
+
+type LiquidityReq struct {
+	ChainId uint64
+	Hash    string
+}
+
+type LiquidityHistoryReq struct {
+	ChainId   uint64
+	Hash      string
+	StartTime int64
+	EndTime   int64
+}
+
+type TokenLiquidityRsp struct {
+	ChainId                  uint64
+	Hash                     string
+	TokenBasicName           string
+	Balance                  string
+	BalanceWithPrecision     string
+	Mintable                 bool
+	TotalSupply              string
+	TotalSupplyWithPrecision string
+	Time                     int64
+}
+
+func MakeTokenLiquidityRsp(liquidity *TokenLiquidity) *TokenLiquidityRsp {
+	tokenLiquidityRsp := &TokenLiquidityRsp{
+		ChainId:        liquidity.ChainId,
+		Hash:           liquidity.Hash,
+		TokenBasicName: liquidity.TokenBasicName,
+		Balance:        liquidity.amount(liquidity.Balance).String(),
+		Mintable:       liquidity.Mintable,
+		Time:           liquidity.Time,
+	}
+	if liquidity.Balance != nil {
+		tokenLiquidityRsp.BalanceWithPrecision = liquidity.Balance.String()
+	}
+	if liquidity.Mintable && liquidity.TotalSupply != nil {
+		tokenLiquidityRsp.TotalSupply = liquidity.amount(liquidity.TotalSupply).String()
+		tokenLiquidityRsp.TotalSupplyWithPrecision = liquidity.TotalSupply.String()
+	}
+	return tokenLiquidityRsp
+}
+
+// TokenBasicSupplyRsp compares the amount locked in the lock proxies with the amount of wrapped tokens in circulation,
+// a drift other than zero means the supply is out of balance.
+type TokenBasicSupplyRsp struct {
+	TokenBasicName string
+	Locked         string
+	Circulating    string
+	Drift          string
+}
+
+type LiquidityRsp struct {
+	Time        int64
+	Liquidities []*TokenLiquidityRsp
+	Supplies    []*TokenBasicSupplyRsp
+}
+
+func MakeLiquidityRsp(liquidities []*TokenLiquidity) *LiquidityRsp {
+	liquidityRsp := &LiquidityRsp{
+		Liquidities: make([]*TokenLiquidityRsp, 0),
+		Supplies:    make([]*TokenBasicSupplyRsp, 0),
+	}
+	locked := make(map[string]decimal.Decimal)
+	circulating := make(map[string]decimal.Decimal)
+	for _, liquidity := range liquidities {
+		if liquidity.Time > liquidityRsp.Time {
+			liquidityRsp.Time = liquidity.Time
+		}
+		liquidityRsp.Liquidities = append(liquidityRsp.Liquidities, MakeTokenLiquidityRsp(liquidity))
+		if liquidity.Mintable {
+			circulating[liquidity.TokenBasicName] = circulating[liquidity.TokenBasicName].Add(liquidity.Circulating())
+		} else {
+			locked[liquidity.TokenBasicName] = locked[liquidity.TokenBasicName].Add(liquidity.amount(liquidity.Balance))
+		}
+	}
+	for name, amount := range circulating {
+		liquidityRsp.Supplies = append(liquidityRsp.Supplies, &TokenBasicSupplyRsp{
+			TokenBasicName: name,
+			Locked:         locked[name].String(),
+			Circulating:    amount.String(),
+			Drift:          locked[name].Sub(amount).String(),
+		})
+	}
+	sort.Slice(liquidityRsp.Supplies, func(i, j int) bool {
+		return liquidityRsp.Supplies[i].TokenBasicName < liquidityRsp.Supplies[j].TokenBasicName
+	})
+	return liquidityRsp
+}
+
+type LiquidityHistoryRsp struct {
+	Liquidities []*TokenLiquidityRsp
+}
+
+func MakeLiquidityHistoryRsp(liquidities []*TokenLiquidity) *LiquidityHistoryRsp {
+	liquidityHistoryRsp := &LiquidityHistoryRsp{
+		Liquidities: make([]*TokenLiquidityRsp, 0),
+	}
+	for _, liquidity := range liquidities {
+		liquidityHistoryRsp.Liquidities = append(liquidityHistoryRsp.Liquidities, MakeTokenLiquidityRsp(liquidity))
+	}
+	return liquidityHistoryRsp
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/schema"
+)
+
+func TestMakeLiquidityRsp(t *testing.T) {
+	liquidities := []*TokenLiquidity{
+		{ChainId: 2, Hash: "dac17f958d2ee523a2206206994597c13d831ec7", TokenBasicName: "USDT", Precision: 6, Balance: NewBigIntFromInt(150000000), Time: 100},
+		{ChainId: 6, Hash: "55d398326f99059ff775485246999027b3197955", TokenBasicName: "USDT", Precision: 18, Balance: NewBigIntFromInt(0), Time: 100},
+		{ChainId: 7, Hash: "a71edc38d189767582c38a3145b5873052c3e47a", TokenBasicName: "USDT", Precision: 6, Mintable: true,
+			Balance: NewBigIntFromInt(10000000), TotalSupply: NewBigIntFromInt(150000000), Time: 101},
+		{ChainId: 2, Hash: "0000000000000000000000000000000000000000", TokenBasicName: "ETH", Precision: 18, Balance: NewBigIntFromInt(1), Time: 100},
+	}
+	rsp := MakeLiquidityRsp(liquidities)
+	assert.Equal(t, int64(101), rsp.Time)
+	assert.Equal(t, 4, len(rsp.Liquidities))
+	assert.Equal(t, "150", rsp.Liquidities[0].Balance)
+	assert.Equal(t, "150000000", rsp.Liquidities[0].BalanceWithPrecision)
+	assert.Equal(t, "", rsp.Liquidities[0].TotalSupply)
+	assert.Equal(t, "150", rsp.Liquidities[2].TotalSupply)
+	assert.Equal(t, []*TokenBasicSupplyRsp{
+		{TokenBasicName: "USDT", Locked: "150", Circulating: "140", Drift: "10"},
+	}, rsp.Supplies)
+}
+
+func TestTokenLiquidityLatestIndex(t *testing.T) {
+	liquiditySchema, err := schema.Parse(&TokenLiquidity{}, &sync.Map{}, schema.NamingStrategy{})
+	assert.Nil(t, err)
+	index, ok := liquiditySchema.ParseIndexes()["idx_token_liquidity_latest"]
+	assert.True(t, ok)
+	columns := make([]string, 0)
+	for _, field := range index.Fields {
+		columns = append(columns, field.DBName)
+	}
+	assert.Equal(t, []string{"chain_id", "hash", "id"}, columns)
+}
@@ -0,0 +1,77 @@
+package models
+
+import "math/big"
+
+// NFTZeroAddress is the from address of the minted NFTs and the to address of the burned ones.
+const NFTZeroAddress = "0000000000000000000000000000000000000000"
+
+// NFTOwnership is the balance of an NFT owner indexed from the ERC721 Transfer and the ERC1155 TransferSingle
+// and TransferBatch events of the registered assets, Asset and Owner are lower case hex without 0x.
+// An ERC721 token has only one owner with quantity 1.
+type NFTOwnership struct {
+	Id       int64   `gorm:"primaryKey;autoIncrement"`
+	ChainId  uint64  `gorm:"uniqueIndex:idx_nft_ownership;index:idx_nft_owner;type:bigint(20);not null"`
+	Asset    string  `gorm:"uniqueIndex:idx_nft_ownership;index:idx_nft_owner;type:varchar(66);not null"`
+	TokenId  string  `gorm:"uniqueIndex:idx_nft_ownership;type:varchar(80);not null"`
+	Owner    string  `gorm:"uniqueIndex:idx_nft_ownership;index:idx_nft_owner;type:varchar(66);not null"`
+	Quantity *BigInt `gorm:"type:varchar(80);default:'1';not null"`
+	Height   uint64  `gorm:"type:bigint(20);not null"`
+}
+
+// NFTOwnershipChanges merges the transfers of a block into the last owners of the ERC721 tokens and the
+// balance changes of the ERC1155 owners. The owner of a burned ERC721 token is NFTZeroAddress, and the
+// zero address has no ERC1155 balance.
+func NFTOwnershipChanges(transfers []*NFTTransferEvent) (owners []*NFTOwnership, balances []*NFTOwnership) {
+	owners = make([]*NFTOwnership, 0)
+	balances = make([]*NFTOwnership, 0)
+	ownerIndexes := make(map[string]int)
+	balanceIndexes := make(map[string]int)
+	addBalance := func(transfer *NFTTransferEvent, owner string, quantity *big.Int) {
+		if owner == NFTZeroAddress {
+			return
+		}
+		key := transfer.Asset + ":" + transfer.TokenId.String() + ":" + owner
+		if index, ok := balanceIndexes[key]; ok {
+			balances[index].Quantity.Add(&balances[index].Quantity.Int, quantity)
+			return
+		}
+		balanceIndexes[key] = len(balances)
+		balances = append(balances, &NFTOwnership{
+			ChainId:  transfer.ChainId,
+			Asset:    transfer.Asset,
+			TokenId:  transfer.TokenId.String(),
+			Owner:    owner,
+			Quantity: NewBigInt(new(big.Int).Set(quantity)),
+			Height:   transfer.Height,
+		})
+	}
+	for _, transfer := range transfers {
+		if transfer.TokenId == nil {
+			continue
+		}
+		if transfer.Standard == TokenTypeErc721 {
+			ownership := &NFTOwnership{
+				ChainId:  transfer.ChainId,
+				Asset:    transfer.Asset,
+				TokenId:  transfer.TokenId.String(),
+				Owner:    transfer.To,
+				Quantity: NewBigIntFromInt(1),
+				Height:   transfer.Height,
+			}
+			key := ownership.Asset + ":" + ownership.TokenId
+			if index, ok := ownerIndexes[key]; ok {
+				owners[index] = ownership
+				continue
+			}
+			ownerIndexes[key] = len(owners)
+			owners = append(owners, ownership)
+			continue
+		}
+		if transfer.Standard != TokenTypeErc1155 || transfer.Quantity == nil || transfer.Quantity.Sign() == 0 {
+			continue
+		}
+		addBalance(transfer, transfer.From, new(big.Int).Neg(transfer.Quantity))
+		addBalance(transfer, transfer.To, transfer.Quantity)
+	}
+	return owners, balances
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNFTOwnershipChanges(t *testing.T) {
+	alice := "9bef1ae7304d3d2f344ea00e796ada18ce1beb03"
+	bob := "1111111111111111111111111111111111111111"
+	erc721 := func(from, to string, tokenId int64) *NFTTransferEvent {
+		return &NFTTransferEvent{Standard: TokenTypeErc721, ChainId: 2, Asset: "a721", TokenId: big.NewInt(tokenId), From: from, To: to, Height: 100}
+	}
+	erc1155 := func(from, to string, tokenId, quantity int64) *NFTTransferEvent {
+		return &NFTTransferEvent{Standard: TokenTypeErc1155, ChainId: 2, Asset: "a1155", TokenId: big.NewInt(tokenId), From: from, To: to,
+			Quantity: big.NewInt(quantity), Height: 100}
+	}
+
+	owners, balances := NFTOwnershipChanges([]*NFTTransferEvent{
+		erc721(NFTZeroAddress, alice, 1),
+		erc721(alice, bob, 1),
+		erc721(alice, NFTZeroAddress, 2),
+		erc1155(NFTZeroAddress, alice, 7, 10),
+		erc1155(alice, bob, 7, 3),
+		erc1155(bob, bob, 7, 1),
+		erc1155(alice, bob, 8, 0),
+		{Standard: TokenTypeErc1155, Asset: "a1155", From: alice, To: bob, Quantity: big.NewInt(1)},
+	})
+
+	assert.Equal(t, 2, len(owners))
+	assert.Equal(t, "1", owners[0].TokenId)
+	assert.Equal(t, bob, owners[0].Owner)
+	assert.Equal(t, "1", owners[0].Quantity.String())
+	assert.Equal(t, NFTZeroAddress, owners[1].Owner)
+
+	assert.Equal(t, 2, len(balances))
+	assert.Equal(t, alice, balances[0].Owner)
+	assert.Equal(t, "7", balances[0].Quantity.String())
+	assert.Equal(t, bob, balances[1].Owner)
+	assert.Equal(t, "3", balances[1].Quantity.String())
+	assert.Equal(t, uint64(100), balances[1].Height)
+}
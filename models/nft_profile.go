@@ -1,11 +1,32 @@
 package models
 
 type NFTProfile struct {
-	TokenBasicName string `gorm:"primaryKey;size:64;not null"`
-	NftTokenId     string `gorm:"primaryKey;type:varchar(64);not null"`
-	Name           string `gorm:"size:64;not null"`
-	Url            string `gorm:"size:64;not null"`
-	Image          string `gorm:"size:64;not null"`
-	Description    string `gorm:"type:varchar(256)"`
-	Text           string `gorm:"type:text"`
+	TokenBasicName string                 `gorm:"primaryKey;size:64;not null"`
+	NftTokenId     string                 `gorm:"primaryKey;type:varchar(80);not null"`
+	Name           string                 `gorm:"size:128;not null"`
+	Url            string                 `gorm:"size:1024;not null"`
+	Image          string                 `gorm:"size:1024;not null"`
+	AnimationUrl   string                 `gorm:"size:1024;not null"`
+	ExternalUrl    string                 `gorm:"size:1024;not null"`
+	Description    string                 `gorm:"type:varchar(256)"`
+	ContentHash    string                 `gorm:"size:64;not null"` // sha256 of the metadata
+	Text           string                 `gorm:"type:text"`
+	Time           int64                  `gorm:"index;type:bigint(20);not null"` // the last time the profile is fetched
+	Attributes     []*NFTProfileAttribute `gorm:"foreignKey:TokenBasicName,NftTokenId;references:TokenBasicName,NftTokenId"`
+}
+
+// NFTProfileAttribute is a trait in the attributes of the NFT metadata.
+type NFTProfileAttribute struct {
+	Id             int64  `gorm:"primaryKey;autoIncrement"`
+	TokenBasicName string `gorm:"index:idx_nft_profile_attribute;size:64;not null"`
+	NftTokenId     string `gorm:"index:idx_nft_profile_attribute;type:varchar(80);not null"`
+	TraitType      string `gorm:"size:128;not null"`
+	Value          string `gorm:"size:256;not null"`
+	DisplayType    string `gorm:"size:32;not null"`
+}
+
+// NFTIndexCursor is the id of the last transfer of a table the nft indexer has indexed.
+type NFTIndexCursor struct {
+	Name   string `gorm:"primaryKey;size:64;not null"` // the transfer table
+	Cursor int64  `gorm:"type:bigint(20);not null"`
 }
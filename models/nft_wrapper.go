@@ -0,0 +1,54 @@
+package models
+
+import (
+	"math/big"
+	"poly-bridge/basedef"
+)
+
+// NFTWrapperLock is the PolyWrapperLock notification of the NFT wrappers of NEO and Ontology, whose states are the
+// method, fromAsset, sender, toChainId, toAddress, tokenId, feeToken, fee and id as in the event of the EVM NFT
+// wrapper. FeeToken is the reversed hex of the notification.
+type NFTWrapperLock struct {
+	Sender    string
+	ToChainId uint64
+	ToAddress string
+	FeeToken  string
+	Fee       *big.Int
+	Id        uint64
+}
+
+// WrapperTransaction returns the wrapper transaction of the lock in the tx txHash of the source chain.
+func (lock *NFTWrapperLock) WrapperTransaction(txHash string, srcChainId uint64, tt uint64, height uint64) *WrapperTransaction {
+	return &WrapperTransaction{
+		Hash:         txHash,
+		User:         lock.Sender,
+		DstChainId:   lock.ToChainId,
+		DstUser:      lock.ToAddress,
+		FeeTokenHash: basedef.HexStringReverse(lock.FeeToken),
+		FeeAmount:    NewBigInt(lock.Fee),
+		ServerId:     lock.Id,
+		Status:       basedef.STATE_SOURCE_DONE,
+		Time:         tt,
+		BlockHeight:  height,
+		SrcChainId:   srcChainId,
+		Standard:     TokenTypeErc721,
+	}
+}
+
+// SetERC721Transfer marks the transfer locked by the NFT proxy of NEO or Ontology as an ERC721 transfer, the
+// amount of the transfer is the token id.
+func (srcTransaction *SrcTransaction) SetERC721Transfer() {
+	srcTransaction.Standard = TokenTypeErc721
+	srcTransaction.SrcTransfer.Standard = TokenTypeErc721
+	srcTransaction.SrcTransfer.TokenId = NewBigInt(new(big.Int).Set(&srcTransaction.SrcTransfer.Amount.Int))
+	srcTransaction.SrcTransfer.Quantity = NewBigIntFromInt(1)
+}
+
+// SetERC721Transfer marks the transfer unlocked by the NFT proxy of NEO or Ontology as an ERC721 transfer, the
+// amount of the transfer is the token id.
+func (dstTransaction *DstTransaction) SetERC721Transfer() {
+	dstTransaction.Standard = TokenTypeErc721
+	dstTransaction.DstTransfer.Standard = TokenTypeErc721
+	dstTransaction.DstTransfer.TokenId = NewBigInt(new(big.Int).Set(&dstTransaction.DstTransfer.Amount.Int))
+	dstTransaction.DstTransfer.Quantity = NewBigIntFromInt(1)
+}
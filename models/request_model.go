@@ -231,10 +231,14 @@ func MakeTokenMapsRsp(tokenMaps []*TokenMap) *TokenMapsRsp {
 	return tokenMapsRsp
 }
 
+// GetFeeReq quotes the fee paid in the token Hash. The route and the fee policy follow the token transferred,
+// which is SwapTokenHash when the fee is paid in another token than the one transferred, and Hash otherwise.
 type GetFeeReq struct {
-	SrcChainId uint64
-	Hash       string
-	DstChainId uint64
+	SrcChainId    uint64
+	Hash          string
+	DstChainId    uint64
+	User          string // optional, sender of the transfer
+	SwapTokenHash string // optional, token transferred
 }
 
 type GetFeeRsp struct {
@@ -244,9 +248,10 @@ type GetFeeRsp struct {
 	UsdtAmount               string
 	TokenAmount              string
 	TokenAmountWithPrecision string
+	PolicyId                 int64
 }
 
-func MakeGetFeeRsp(srcChainId uint64, hash string, dstChainId uint64, usdtAmount *big.Float, tokenAmount *big.Float, tokenAmountWithPrecision *big.Float) *GetFeeRsp {
+func MakeGetFeeRsp(srcChainId uint64, hash string, dstChainId uint64, usdtAmount *big.Float, tokenAmount *big.Float, tokenAmountWithPrecision *big.Float, policyId int64) *GetFeeRsp {
 	getFeeRsp := &GetFeeRsp{
 		SrcChainId:               srcChainId,
 		Hash:                     hash,
@@ -254,6 +259,7 @@ func MakeGetFeeRsp(srcChainId uint64, hash string, dstChainId uint64, usdtAmount
 		UsdtAmount:               usdtAmount.String(),
 		TokenAmount:              tokenAmount.String(),
 		TokenAmountWithPrecision: tokenAmountWithPrecision.String(),
+		PolicyId:                 policyId,
 	}
 	{
 		aaa, _ := usdtAmount.Float64()
@@ -276,6 +282,27 @@ func MakeGetFeeRsp(srcChainId uint64, hash string, dstChainId uint64, usdtAmount
 	return getFeeRsp
 }
 
+type GetFeesReq struct {
+	SrcChainId    uint64 // quote all the routes of the token Hash when Fees is empty
+	Hash          string
+	User          string // optional, sender of the transfer
+	SwapTokenHash string // optional, token transferred
+	Fees          []*GetFeeReq
+}
+
+type GetFeesRsp struct {
+	TotalCount uint64
+	Fees       []*GetFeeRsp
+}
+
+func MakeGetFeesRsp(getFeeRsps []*GetFeeRsp) *GetFeesRsp {
+	getFeesRsp := &GetFeesRsp{
+		TotalCount: uint64(len(getFeeRsps)),
+		Fees:       getFeeRsps,
+	}
+	return getFeesRsp
+}
+
 type CheckFeeReq struct {
 	Hash    string
 	ChainId uint64
@@ -287,6 +314,7 @@ type CheckFeeRsp struct {
 	PayState    int
 	Amount      string
 	MinProxyFee string
+	PolicyId    int64
 }
 
 type CheckFeesReq struct {
@@ -315,6 +343,7 @@ func MakeCheckFeeRsp(checkFee *CheckFee) *CheckFeeRsp {
 		PayState:    checkFee.PayState,
 		Amount:      checkFee.Amount.String(),
 		MinProxyFee: checkFee.MinProxyFee.String(),
+		PolicyId:    checkFee.PolicyId,
 	}
 	{
 		aaa, _ := checkFee.Amount.Float64()
@@ -329,6 +358,113 @@ func MakeCheckFeeRsp(checkFee *CheckFee) *CheckFeeRsp {
 	return checkFeeRsp
 }
 
+const (
+	PreCheckTokenMap  = "tokenmap"
+	PreCheckChainFee  = "chainfee"
+	PreCheckPrice     = "price"
+	PreCheckLiquidity = "liquidity"
+	PreCheckAddress   = "address"
+	PreCheckFee       = "fee"
+)
+
+// PreCheckReq describes a transfer before it is signed. Amount and FeeAmount are in token units without precision,
+// e.g. "0.01" for 0.01 ETH as the TokenAmount of the quoted fee, not the raw amounts with precision the wrapper
+// transactions and /checkfee/ carry. The fee is paid in the transferred token when FeeTokenHash is empty.
+type PreCheckReq struct {
+	SrcChainId   uint64
+	Hash         string
+	DstChainId   uint64
+	DstAddress   string
+	Amount       string
+	FeeTokenHash string
+	FeeAmount    string
+	User         string
+}
+
+// PreCheck is the result of a check, Skipped is set with the reason when the check can not be made, which passes.
+type PreCheck struct {
+	Name    string
+	Pass    bool
+	Skipped bool
+	Reason  string
+}
+
+type PreCheckRsp struct {
+	Pass         bool
+	DstTokenHash string
+	Fee          *GetFeeRsp
+	Checks       []*PreCheck
+}
+
+func MakePreCheckRsp(dstTokenHash string, fee *GetFeeRsp, checks []*PreCheck) *PreCheckRsp {
+	preCheckRsp := &PreCheckRsp{
+		Pass:         true,
+		DstTokenHash: dstTokenHash,
+		Fee:          fee,
+		Checks:       checks,
+	}
+	for _, check := range checks {
+		if !check.Pass {
+			preCheckRsp.Pass = false
+		}
+	}
+	return preCheckRsp
+}
+
+type FeeReconciliationReq struct {
+	Period     string
+	StartTime  uint64
+	EndTime    uint64
+	SrcChainId uint64
+	DstChainId uint64
+}
+
+type FeeReconciliationStatRsp struct {
+	Period            int64
+	SrcChainId        uint64
+	DstChainId        uint64
+	FeeTokenBasicName string
+	Count             uint64
+	FeeUsd            string
+	CostUsd           string
+	Margin            string
+}
+
+type FeeReconciliationRsp struct {
+	Period     string
+	StartTime  uint64
+	EndTime    uint64
+	TotalCount uint64
+	Stats      []*FeeReconciliationStatRsp
+}
+
+func MakeFeeReconciliationRsp(period string, startTime uint64, endTime uint64, stats []*FeeReconciliationStat) *FeeReconciliationRsp {
+	feeReconciliationRsp := &FeeReconciliationRsp{
+		Period:     period,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		TotalCount: uint64(len(stats)),
+	}
+	for _, stat := range stats {
+		feeReconciliationRsp.Stats = append(feeReconciliationRsp.Stats, MakeFeeReconciliationStatRsp(stat))
+	}
+	return feeReconciliationRsp
+}
+
+func MakeFeeReconciliationStatRsp(stat *FeeReconciliationStat) *FeeReconciliationStatRsp {
+	precision := decimal.NewFromInt(basedef.PRICE_PRECISION)
+	return &FeeReconciliationStatRsp{
+		Period:            stat.Period,
+		SrcChainId:        stat.SrcChainId,
+		DstChainId:        stat.DstChainId,
+		FeeTokenBasicName: stat.FeeTokenBasicName,
+		Count:             stat.Count,
+		FeeUsd:            decimal.NewFromBigInt(stat.FeeUsd, 0).Div(precision).String(),
+		CostUsd:           decimal.NewFromBigInt(stat.CostUsd, 0).Div(precision).String(),
+		Margin:            decimal.NewFromBigInt(stat.Margin, 0).Div(precision).String(),
+	}
+}
+
 type WrapperTransactionReq struct {
 	Hash string
 }
@@ -662,7 +798,8 @@ func MakeCurveTransactionRsp(transaction1 *SrcPolyDstRelation, transaction2 *Src
 }
 
 type TransactionsOfAddressReq struct {
-	State     int // -1 表示查全部
+	State     int    // -1 表示查全部
+	ChainId   uint64 // optional, chain of the addresses, addresses are parsed in the format of every chain if not set
 	Addresses []string
 	PageSize  int
 	PageNo    int
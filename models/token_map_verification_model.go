@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+import (
+	"encoding/hex"
+	"fmt"
+	"poly-bridge/basedef"
+	"strings"
+)
+
+const (
+	TokenMapVerifyMatch uint8 = iota
+	TokenMapVerifyMismatch
+	TokenMapVerifyFailed
+	TokenMapVerifyUnverified
+)
+
+// TokenMapVerification is the latest result of checking a token map against the lock proxy of its source chain.
+type TokenMapVerification struct {
+	Id           int64  `gorm:"primaryKey;autoIncrement"`
+	SrcChainId   uint64 `gorm:"uniqueIndex:idx_token_map_verification;type:bigint(20);not null"`
+	SrcTokenHash string `gorm:"uniqueIndex:idx_token_map_verification;size:66;not null"`
+	DstChainId   uint64 `gorm:"uniqueIndex:idx_token_map_verification;type:bigint(20);not null"`
+	DstTokenHash string `gorm:"uniqueIndex:idx_token_map_verification;size:66;not null"`
+	Standard     uint8  `gorm:"type:int(8);not null"`
+	BoundHash    string `gorm:"size:66;not null"`
+	Status       uint8  `gorm:"index;type:int(8);not null"`
+	Error        string `gorm:"size:256"`
+	Time         int64  `gorm:"type:bigint(20);not null"`
+}
+
+// MakeTokenMapVerification compares the asset bound in the lock proxy with the token map. Hashes of some chains
+// like neo are saved reversed, so the reversed bound hash is accepted too.
+func MakeTokenMapVerification(tokenMap *TokenMap, bound []byte, err error, now int64) *TokenMapVerification {
+	verification := &TokenMapVerification{
+		SrcChainId:   tokenMap.SrcChainId,
+		SrcTokenHash: tokenMap.SrcTokenHash,
+		DstChainId:   tokenMap.DstChainId,
+		DstTokenHash: tokenMap.DstTokenHash,
+		Standard:     tokenMap.Standard,
+		Time:         now,
+	}
+	if err != nil {
+		verification.Status = TokenMapVerifyFailed
+		verification.Error = err.Error()
+		if len(verification.Error) > 256 {
+			verification.Error = verification.Error[:256]
+		}
+		return verification
+	}
+	verification.BoundHash = hex.EncodeToString(bound)
+	dstTokenHash := strings.ToLower(strings.TrimPrefix(tokenMap.DstTokenHash, "0x"))
+	if verification.BoundHash == dstTokenHash || hex.EncodeToString(basedef.HexReverse(bound)) == dstTokenHash {
+		verification.Status = TokenMapVerifyMatch
+	} else {
+		verification.Status = TokenMapVerifyMismatch
+	}
+	return verification
+}
+
+// MakeUnverifiedTokenMapVerification records a token map which can not be checked, such as the maps from the chains
+// without an ethereum like lock proxy, so that it is listed as not verified instead of missing.
+func MakeUnverifiedTokenMapVerification(tokenMap *TokenMap, reason string, now int64) *TokenMapVerification {
+	verification := MakeTokenMapVerification(tokenMap, nil, fmt.Errorf("%s", reason), now)
+	verification.Status = TokenMapVerifyUnverified
+	return verification
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeTokenMapVerification(t *testing.T) {
+	bound, _ := hex.DecodeString("b9478391eec218defa96f7b9a7938cf44e7a2fd5")
+
+	var testdata = []struct {
+		dstTokenHash string
+		bound        []byte
+		err          error
+		expect       uint8
+	}{
+		{dstTokenHash: "b9478391eec218defa96f7b9a7938cf44e7a2fd5", bound: bound, expect: TokenMapVerifyMatch},
+		{dstTokenHash: "0xB9478391EEC218DEFA96F7B9A7938CF44E7A2FD5", bound: bound, expect: TokenMapVerifyMatch},
+		{dstTokenHash: "d52f7a4ef48c93a7b9f796fade18c2ee918347b9", bound: bound, expect: TokenMapVerifyMatch},
+		{dstTokenHash: "1111111111111111111111111111111111111111", bound: bound, expect: TokenMapVerifyMismatch},
+		{dstTokenHash: "b9478391eec218defa96f7b9a7938cf44e7a2fd5", bound: nil, expect: TokenMapVerifyMismatch},
+		{dstTokenHash: "b9478391eec218defa96f7b9a7938cf44e7a2fd5", err: fmt.Errorf("all node is not working"), expect: TokenMapVerifyFailed},
+	}
+	for _, item := range testdata {
+		tokenMap := &TokenMap{SrcChainId: 2, SrcTokenHash: "0000000000000000000000000000000000000000", DstChainId: 6, DstTokenHash: item.dstTokenHash}
+		verification := MakeTokenMapVerification(tokenMap, item.bound, item.err, 100)
+		assert.Equal(t, item.expect, verification.Status, item.dstTokenHash)
+	}
+}
+
+func TestMakeUnverifiedTokenMapVerification(t *testing.T) {
+	tokenMap := &TokenMap{SrcChainId: 4, SrcTokenHash: "17da3881ab2d050fea414c80b3fa8324d756f60e", DstChainId: 2, DstTokenHash: "b9478391eec218defa96f7b9a7938cf44e7a2fd5"}
+	verification := MakeUnverifiedTokenMapVerification(tokenMap, "chain 4 can not be verified", 100)
+	assert.Equal(t, TokenMapVerifyUnverified, verification.Status)
+	assert.Equal(t, "chain 4 can not be verified", verification.Error)
+	assert.Equal(t, tokenMap.DstTokenHash, verification.DstTokenHash)
+}
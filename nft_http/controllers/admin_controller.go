@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"poly-bridge/adminauth"
+	"poly-bridge/models"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/logs"
+)
+
+// AdminController serves the admin api of the NFT service, the requests are checked with the adminkeys of the
+// app config, which are "name:key" pairs split by commas.
+type AdminController struct {
+	beego.Controller
+	operator string
+}
+
+func (c *AdminController) Prepare() {
+	operator, ok := adminauth.Operator(beego.AppConfig.String("adminkeys"), c.Ctx.Input.Header("X-Api-Key"))
+	if !ok {
+		c.Data["json"] = models.MakeErrorRsp("api key is invalid!")
+		c.Ctx.ResponseWriter.WriteHeader(401)
+		c.ServeJSON()
+		c.StopRun()
+	}
+	c.operator = operator
+}
+
+// ReloadAssets reloads the NFT assets, fee tokens and fetchers from the token tables.
+func (c *AdminController) ReloadAssets() {
+	rsp, err := reloadAssets()
+	if err != nil {
+		logs.Error("%s reload assets err: %v", c.operator, err)
+		customOutput(&c.Controller, ErrCodeRequest, err.Error())
+		return
+	}
+	logs.Info("%s reload assets, total %d", c.operator, rsp.TotalCount)
+	output(&c.Controller, rsp)
+}
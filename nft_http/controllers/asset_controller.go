@@ -33,7 +33,7 @@ func (c *AssetController) Assets() {
 	}
 
 	assets := make([]*models.Token, 0)
-	db.Where("chain_id = ? and standard = ? and property = ?", req.ChainId, models.TokenTypeErc721, 1).
+	readDB().Where("chain_id = ? and standard in ? and property = ?", req.ChainId, models.NFTStandards, 1).
 		Preload("TokenBasic").
 		Preload("TokenMaps").
 		Preload("TokenMaps.DstToken").
@@ -50,7 +50,7 @@ func (c *AssetController) Asset() {
 	}
 
 	asset := new(models.Token)
-	res := db.Where("hash = ? and chain_id = ? and standard = ? and property = ?", req.Hash, req.ChainId, models.TokenTypeErc721, 1).
+	res := readDB().Where("hash = ? and chain_id = ? and standard in ? and property = ?", req.Hash, req.ChainId, models.NFTStandards, 1).
 		Preload("TokenBasic").
 		Preload("TokenMaps").
 		Preload("TokenMaps.DstToken").
@@ -0,0 +1,215 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/nft_http/meta"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"gorm.io/gorm"
+)
+
+const defaultReloadSlot = 60
+
+var (
+	assetsLock sync.RWMutex
+	reloadLock sync.Mutex
+	// fetcher type and base uri of the registered assets
+	registeredFetchers = make(map[string]string)
+)
+
+// loadAssets reads the enabled NFT assets and the fee tokens of the chains.
+func loadAssets(db *gorm.DB) ([]*models.Token, map[uint64]*models.Token, error) {
+	nftAssets := make([]*models.Token, 0)
+	if err := db.Where("standard in ? and property=?", models.NFTStandards, 1).
+		Preload("TokenBasic").
+		Find(&nftAssets).Error; err != nil {
+		return nil, nil, err
+	}
+
+	feeTokenList := make([]*models.Token, 0)
+	if err := db.Where("hash=?", nativeHash).
+		Preload("TokenBasic").
+		Find(&feeTokenList).Error; err != nil {
+		return nil, nil, err
+	}
+	nftFeeTokens := make(map[uint64]*models.Token)
+	for _, v := range feeTokenList {
+		nftFeeTokens[v.ChainId] = v
+	}
+	return nftAssets, nftFeeTokens, nil
+}
+
+func setAssets(nftAssets []*models.Token, nftFeeTokens map[uint64]*models.Token) {
+	assetsLock.Lock()
+	defer assetsLock.Unlock()
+	assets = nftAssets
+	feeTokens = nftFeeTokens
+}
+
+// nftAssets returns the assets loaded last, the slice is replaced rather than changed by the reloads.
+func nftAssets() []*models.Token {
+	assetsLock.RLock()
+	defer assetsLock.RUnlock()
+	return assets
+}
+
+func nftFeeToken(chainId uint64) *models.Token {
+	assetsLock.RLock()
+	defer assetsLock.RUnlock()
+	return feeTokens[chainId]
+}
+
+// registerFetcher registers the fetcher of the asset unless it is registered with the same base uri already.
+func registerFetcher(asset *models.Token) {
+	if asset.TokenBasic == nil {
+		return
+	}
+	fetcherTyp := meta.FetcherType(asset.TokenBasic.MetaFetcherType)
+	registration := fmt.Sprintf("%d:%s", fetcherTyp, asset.TokenBasic.Meta)
+	if registeredFetchers[asset.TokenBasicName] == registration {
+		return
+	}
+	fetcher.Register(fetcherTyp, asset.TokenBasicName, asset.TokenBasic.Meta)
+	registeredFetchers[asset.TokenBasicName] = registration
+}
+
+type AssetsReloadRsp struct {
+	TotalCount int
+	Added      []string
+	Removed    []string
+}
+
+// reloadAssets loads the assets and fee tokens again, registers the fetchers of the new assets and removes
+// the fetchers of the assets which are disabled.
+func reloadAssets() (*AssetsReloadRsp, error) {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
+	loadedAssets, loadedFeeTokens, err := loadAssets(db)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &AssetsReloadRsp{
+		TotalCount: len(loadedAssets),
+		Added:      make([]string, 0),
+		Removed:    make([]string, 0),
+	}
+
+	loaded := make(map[string]bool)
+	for _, asset := range loadedAssets {
+		loaded[assetKey(asset.ChainId, asset.Hash)] = true
+	}
+	current := make(map[string]bool)
+	for _, asset := range nftAssets() {
+		key := assetKey(asset.ChainId, asset.Hash)
+		current[key] = true
+		if !loaded[key] {
+			rsp.Removed = append(rsp.Removed, fmt.Sprintf("%s@%d", asset.TokenBasicName, asset.ChainId))
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, asset := range loadedAssets {
+		names[asset.TokenBasicName] = true
+		registerFetcher(asset)
+		if current[assetKey(asset.ChainId, asset.Hash)] {
+			continue
+		}
+		rsp.Added = append(rsp.Added, fmt.Sprintf("%s@%d", asset.TokenBasicName, asset.ChainId))
+		if _, _, err := selectNodeAndWrapper(asset.ChainId); err != nil {
+			logs.Error("reload asset %s select node of chain %d err: %v", asset.TokenBasicName, asset.ChainId, err)
+		}
+	}
+	for name := range registeredFetchers {
+		if !names[name] {
+			fetcher.Unregister(name)
+			delete(registeredFetchers, name)
+		}
+	}
+
+	setAssets(loadedAssets, loadedFeeTokens)
+	if indexer != nil {
+		indexer.SetAssets(loadedAssets)
+	}
+	sort.Strings(rsp.Added)
+	sort.Strings(rsp.Removed)
+	if len(rsp.Added) > 0 || len(rsp.Removed) > 0 {
+		logs.Info("reload assets, added %v, removed %v", rsp.Added, rsp.Removed)
+	}
+	return rsp, nil
+}
+
+// AssetWatcher reloads the assets every reload slot, so the collections enabled or disabled in the token
+// tables are served without a restart.
+type AssetWatcher struct {
+	reloadSlot int64
+	exit       chan bool
+}
+
+func NewAssetWatcher(cfg *conf.NFTAssetsConfig) *AssetWatcher {
+	watcher := &AssetWatcher{
+		reloadSlot: defaultReloadSlot,
+		exit:       make(chan bool, 0),
+	}
+	if cfg != nil && cfg.ReloadSlot > 0 {
+		watcher.reloadSlot = cfg.ReloadSlot
+	}
+	return watcher
+}
+
+func (s *AssetWatcher) Start() {
+	logs.Info("start nft asset watcher.")
+	go s.run()
+}
+
+func (s *AssetWatcher) Stop() {
+	s.exit <- true
+	logs.Info("stop nft asset watcher.")
+}
+
+func (s *AssetWatcher) run() {
+	ticker := time.NewTicker(time.Second * time.Duration(s.reloadSlot))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.round()
+		case <-s.exit:
+			return
+		}
+	}
+}
+
+func (s *AssetWatcher) round() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("nft asset watcher recover info: %s", string(debug.Stack()))
+		}
+	}()
+	if _, err := reloadAssets(); err != nil {
+		logs.Error("nft asset watcher reload err: %v", err)
+	}
+}
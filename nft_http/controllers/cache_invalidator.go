@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/nft_http/meta/utils"
+	"runtime/debug"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+)
+
+const (
+	defaultInvalidateSlot  = 10
+	invalidateBatchSize    = 1000
+	invalidateSrcTransfers = "src_transfers"
+	invalidateDstTransfers = "dst_transfers"
+	// the transfers committed late are found again in the overlap behind the cursor
+	invalidateOverlap = 1000
+)
+
+type lockedToken struct {
+	Id         int64
+	ChainId    uint64
+	Asset      string
+	Amount     string
+	DstChainId uint64
+	DstAsset   string
+}
+
+// CacheInvalidator removes the cached items of the NFTs locked or unlocked since its last round, and marks the
+// home pages of their chains stale, so the bridged tokens are not shown as available.
+type CacheInvalidator struct {
+	invalidateSlot int64
+	cursors        map[string]*utils.IdCursor
+	exit           chan bool
+}
+
+func NewCacheInvalidator(cfg *conf.NFTCacheConfig) *CacheInvalidator {
+	invalidator := &CacheInvalidator{
+		invalidateSlot: defaultInvalidateSlot,
+		cursors:        make(map[string]*utils.IdCursor),
+		exit:           make(chan bool, 0),
+	}
+	if cfg != nil && cfg.InvalidateSlot > 0 {
+		invalidator.invalidateSlot = cfg.InvalidateSlot
+	}
+	// the caches are empty at the start, only the later transfers invalidate them
+	for _, table := range []string{invalidateSrcTransfers, invalidateDstTransfers} {
+		var cursor int64
+		readDB().Table(table).Select("coalesce(max(id), 0)").Row().Scan(&cursor)
+		invalidator.cursors[table] = utils.NewIdCursor(cursor, invalidateOverlap)
+	}
+	return invalidator
+}
+
+func (s *CacheInvalidator) Start() {
+	logs.Info("start nft cache invalidator.")
+	go s.run()
+}
+
+func (s *CacheInvalidator) Stop() {
+	s.exit <- true
+	logs.Info("stop nft cache invalidator.")
+}
+
+func (s *CacheInvalidator) run() {
+	ticker := time.NewTicker(time.Second * time.Duration(s.invalidateSlot))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.round()
+		case <-s.exit:
+			return
+		}
+	}
+}
+
+func (s *CacheInvalidator) round() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("nft cache invalidator recover info: %s", string(debug.Stack()))
+		}
+	}()
+	chains := make(map[uint64]bool)
+	for table := range s.cursors {
+		if err := s.invalidate(table, chains); err != nil {
+			logs.Error("nft cache invalidator %s err: %v", table, err)
+		}
+	}
+	for chainId := range chains {
+		InvalidateHomePageCache(chainId)
+		if !cachePolicy.StaleWhileRevalidate {
+			continue
+		}
+		for _, cache := range homePageCaches(chainId) {
+			refreshHomePageAsync(chainId, cache.Size)
+		}
+	}
+}
+
+// invalidate removes the items of the transfers after the cursor of the table, and collects their chains. The
+// overlap behind the cursor is read again for the transfers committed after the ones of higher ids.
+func (s *CacheInvalidator) invalidate(table string, chains map[uint64]bool) error {
+	columns := "id, chain_id, asset, amount"
+	if table == invalidateSrcTransfers {
+		columns += ", dst_chain_id, dst_asset"
+	}
+	cursor := s.cursors[table]
+	from := cursor.Rewind()
+	for {
+		transfers := make([]*lockedToken, 0)
+		res := readDB().Table(table).Select(columns).
+			Where("standard in ? and id > ?", models.NFTStandards, from).
+			Order("id asc").Limit(invalidateBatchSize).Find(&transfers)
+		if res.Error != nil {
+			return res.Error
+		}
+		if len(transfers) == 0 {
+			return nil
+		}
+		for _, transfer := range transfers {
+			if !cursor.Read(transfer.Id) {
+				continue
+			}
+			InvalidateItemCache(transfer.ChainId, transfer.Asset, transfer.Amount)
+			chains[transfer.ChainId] = true
+			if transfer.DstAsset != "" {
+				InvalidateItemCache(transfer.DstChainId, transfer.DstAsset, transfer.Amount)
+				chains[transfer.DstChainId] = true
+			}
+		}
+		from = transfers[len(transfers)-1].Id
+		if len(transfers) < invalidateBatchSize {
+			return nil
+		}
+	}
+}
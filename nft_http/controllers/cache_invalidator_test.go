@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheInvalidator(t *testing.T) {
+	useTestCollections(t)
+	useTestCache(t)
+	previousPolicy := cachePolicy
+	cachePolicy = &CachePolicy{HomeTTL: time.Minute, ItemTTL: time.Minute}
+	defer func() { cachePolicy = previousPolicy }()
+
+	fake := useFakeDB(t)
+	fake.on("max(id), 0) FROM `src_transfers`", []string{"max"}, []driver.Value{10})
+	fake.on("max(id), 0) FROM `dst_transfers`", []string{"max"}, []driver.Value{20})
+	invalidator := NewCacheInvalidator(nil)
+	assert.Equal(t, map[string]int64{invalidateSrcTransfers: 10, invalidateDstTransfers: 20}, lastIds(invalidator))
+
+	// the transfers after the cursors, a lock from chain 2 to chain 6 and an unlock on chain 7
+	fake.on("FROM `src_transfers` WHERE", []string{"id", "chain_id", "asset", "amount", "dst_chain_id", "dst_asset"},
+		[]driver.Value{11, 2, indexedAddress(testSealEth), "5", 6, indexedAddress(testSealBsc)},
+	).args = []interface{}{10}
+	dstTransfers := fake.on("FROM `dst_transfers` WHERE", []string{"id", "chain_id", "asset", "amount"},
+		[]driver.Value{21, 7, indexedAddress(testKittyEth), "9"},
+		[]driver.Value{23, 7, indexedAddress(testKittyEth), "8"},
+	)
+	dstTransfers.args = []interface{}{20}
+
+	cacheItems := func() {
+		SetItemCache(2, testSealEth, "5", &Item{TokenId: "5"})
+		SetItemCache(6, testSealBsc, "5", &Item{TokenId: "5"})
+		SetItemCache(7, testKittyEth, "9", &Item{TokenId: "9"})
+		SetItemCache(2, testKittyEth, "5", &Item{TokenId: "5"})
+		for _, chainId := range []uint64{2, 6, 8} {
+			for _, size := range []int{3, 5} {
+				SetHomePageCache(chainId, &CacheHomeRsp{Rsp: new(HomeRsp), Size: size, Time: time.Now()})
+			}
+		}
+	}
+	cacheItems()
+	invalidator.round()
+
+	for _, v := range []struct {
+		chainId uint64
+		asset   string
+		tokenId string
+		cached  bool
+	}{
+		{2, testSealEth, "5", false},
+		{6, testSealBsc, "5", false},
+		{7, testKittyEth, "9", false},
+		{2, testKittyEth, "5", true},
+	} {
+		_, ok := GetItemCache(v.chainId, v.asset, v.tokenId)
+		assert.Equal(t, v.cached, ok, "%d %s %s", v.chainId, v.asset, v.tokenId)
+	}
+	for _, chainId := range []uint64{2, 6, 8} {
+		for _, size := range []int{3, 5} {
+			cache, ok := GetHomePageCache(chainId, size)
+			assert.True(t, ok, "the stale home pages are kept for the stale while revalidate mode")
+			assert.Equal(t, chainId == 8, cache.Fresh(), "%d %d", chainId, size)
+		}
+	}
+	assert.Equal(t, map[string]int64{invalidateSrcTransfers: 11, invalidateDstTransfers: 23}, lastIds(invalidator))
+
+	// the next round reads the overlap behind the cursors again, the transfers read already do not invalidate again
+	// but the one committed late does
+	dstTransfers.rows = [][]driver.Value{
+		{21, 7, indexedAddress(testKittyEth), "9"},
+		{22, 2, indexedAddress(testKittyEth), "5"},
+		{23, 7, indexedAddress(testKittyEth), "8"},
+	}
+	cacheItems()
+	invalidator.round()
+	for _, v := range []struct {
+		chainId uint64
+		asset   string
+		tokenId string
+		cached  bool
+	}{
+		{2, testSealEth, "5", true},
+		{7, testKittyEth, "9", true},
+		{2, testKittyEth, "5", false},
+	} {
+		_, ok := GetItemCache(v.chainId, v.asset, v.tokenId)
+		assert.Equal(t, v.cached, ok, "%d %s %s", v.chainId, v.asset, v.tokenId)
+	}
+	for _, chainId := range []uint64{2, 6} {
+		cache, _ := GetHomePageCache(chainId, 3)
+		assert.Equal(t, chainId == 6, cache.Fresh(), chainId)
+	}
+	assert.Equal(t, map[string]int64{invalidateSrcTransfers: 11, invalidateDstTransfers: 23}, lastIds(invalidator))
+}
+
+func lastIds(invalidator *CacheInvalidator) map[string]int64 {
+	ids := make(map[string]int64)
+	for table, cursor := range invalidator.cursors {
+		ids[table] = cursor.Last
+	}
+	return ids
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"poly-bridge/models"
+	"strings"
+
+	"github.com/astaxie/beego"
+)
+
+type CollectionController struct {
+	beego.Controller
+}
+
+// Collections lists the statistics of the NFT collections computed by the collection counter.
+func (c *CollectionController) Collections() {
+	stats, updateTime := collectionCounter.List()
+	output(&c.Controller, new(CollectionsRsp).instance(stats, updateTime))
+}
+
+// Activity pages the bridged transfers of a collection with its statistics.
+func (c *CollectionController) Activity() {
+	assetName := c.Ctx.Input.Param(":asset")
+	pageSize, _ := c.GetInt("pagesize", 10)
+	pageNo, _ := c.GetInt("pageno", 0)
+	if pageSize <= 0 || pageNo < 0 {
+		customInput(&c.Controller, ErrCodeRequest, errMap[ErrCodeRequest])
+		return
+	}
+	if !checkPageSize(&c.Controller, pageSize) {
+		return
+	}
+
+	conds := make([]string, 0)
+	args := make([]interface{}, 0)
+	for _, asset := range nftAssets() {
+		if asset.TokenBasicName == assetName {
+			conds = append(conds, "(tr.chain_id = ? and tr.asset = ?)")
+			args = append(args, asset.ChainId, indexedAddress(asset.Hash))
+		}
+	}
+	if len(conds) == 0 {
+		notExist(&c.Controller)
+		return
+	}
+	where := "wp.standard in ? and (" + strings.Join(conds, " or ") + ")"
+	args = append([]interface{}{models.NFTStandards}, args...)
+
+	relations := make([]*TransactionBriefRelation, 0)
+	readDB().Raw("select wp.*, tr.amount as token_id, tr.quantity as quantity, tr.asset as src_asset "+
+		"from wrapper_transactions wp "+
+		"inner join src_transfers as tr on wp.hash=tr.tx_hash "+
+		"where "+where+" "+
+		"order by wp.time desc "+
+		"limit ? offset ?", append(args, pageSize, pageSize*pageNo)...).
+		Find(&relations)
+
+	var transactionNum int64
+	readDB().Table("wrapper_transactions as wp").
+		Joins("inner join src_transfers as tr on wp.hash=tr.tx_hash").
+		Where(where, args...).
+		Count(&transactionNum)
+
+	list := make([]*TransactionBriefRsp, 0)
+	for _, v := range relations {
+		list = append(list, new(TransactionBriefRsp).instance(assetName, v))
+	}
+	transactions := new(TransactionBriefsRsp).instance(pageSize, pageNo, getPageNo(int(transactionNum), pageSize), int(transactionNum), list)
+	output(&c.Controller, new(CollectionActivityRsp).instance(collectionCounter.Get(assetName), transactions))
+}
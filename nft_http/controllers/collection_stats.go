@@ -0,0 +1,304 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const defaultStatsSlot = 300
+
+type ChainPairCount struct {
+	SrcChainId uint64
+	DstChainId uint64
+	Count      int64
+}
+
+// CollectionStats is the statistics of an NFT collection on all of its chains. Locked is the quantity of the
+// tokens held by the NFT lock proxies, Activity24h and Activity7d count the bridged transfers in the last day and week.
+type CollectionStats struct {
+	AssetName   string
+	Standard    uint8
+	Bridged     int64
+	ChainPairs  []*ChainPairCount
+	Holders     int64
+	Locked      string
+	Activity24h int64
+	Activity7d  int64
+}
+
+type transferCount struct {
+	ChainId    uint64
+	Asset      string
+	DstChainId uint64
+	Count      int64
+}
+
+// CollectionCounter computes the statistics of the NFT collections every stats slot, the requests read the
+// statistics of the last round.
+type CollectionCounter struct {
+	statsSlot  int64
+	lock       sync.RWMutex
+	stats      []*CollectionStats
+	updateTime int64
+	exit       chan bool
+}
+
+func NewCollectionCounter(cfg *conf.NFTStatsConfig) *CollectionCounter {
+	counter := &CollectionCounter{
+		statsSlot: defaultStatsSlot,
+		stats:     make([]*CollectionStats, 0),
+		exit:      make(chan bool, 0),
+	}
+	if cfg != nil && cfg.StatsSlot > 0 {
+		counter.statsSlot = cfg.StatsSlot
+	}
+	return counter
+}
+
+func (s *CollectionCounter) Start() {
+	logs.Info("start nft collection counter.")
+	go s.run()
+}
+
+func (s *CollectionCounter) Stop() {
+	s.exit <- true
+	logs.Info("stop nft collection counter.")
+}
+
+func (s *CollectionCounter) run() {
+	ticker := time.NewTicker(time.Second * time.Duration(s.statsSlot))
+	defer ticker.Stop()
+	for {
+		s.round()
+		select {
+		case <-ticker.C:
+		case <-s.exit:
+			return
+		}
+	}
+}
+
+func (s *CollectionCounter) round() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("nft collection counter recover info: %s", string(debug.Stack()))
+		}
+	}()
+	stats, err := s.count()
+	if err != nil {
+		logs.Error("nft collection counter count err: %v", err)
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stats = stats
+	s.updateTime = time.Now().Unix()
+}
+
+// List returns the statistics of all the collections and the time they are computed.
+func (s *CollectionCounter) List() ([]*CollectionStats, int64) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.stats, s.updateTime
+}
+
+func (s *CollectionCounter) Get(assetName string) *CollectionStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, v := range s.stats {
+		if v.AssetName == assetName {
+			return v
+		}
+	}
+	return nil
+}
+
+func (s *CollectionCounter) count() ([]*CollectionStats, error) {
+	collections := make(map[string]*CollectionStats)
+	names := make([]string, 0)
+	tokens := make(map[string]*models.Token)
+	for _, asset := range nftAssets() {
+		tokens[assetKey(asset.ChainId, asset.Hash)] = asset
+		if _, ok := collections[asset.TokenBasicName]; ok {
+			continue
+		}
+		collections[asset.TokenBasicName] = &CollectionStats{
+			AssetName:  asset.TokenBasicName,
+			Standard:   asset.Standard,
+			ChainPairs: make([]*ChainPairCount, 0),
+			Locked:     "0",
+		}
+		names = append(names, asset.TokenBasicName)
+	}
+	collectionOf := func(chainId uint64, asset string) *CollectionStats {
+		token, ok := tokens[assetKey(chainId, asset)]
+		if !ok {
+			return nil
+		}
+		return collections[token.TokenBasicName]
+	}
+
+	bridged := make([]*transferCount, 0)
+	if err := readDB().Model(&models.SrcTransfer{}).
+		Select("chain_id, asset, dst_chain_id, count(*) as count").
+		Where("standard in ?", models.NFTStandards).
+		Group("chain_id, asset, dst_chain_id").
+		Find(&bridged).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range bridged {
+		collection := collectionOf(v.ChainId, v.Asset)
+		if collection == nil {
+			continue
+		}
+		collection.Bridged += v.Count
+		collection.ChainPairs = addChainPair(collection.ChainPairs, v.ChainId, v.DstChainId, v.Count)
+	}
+
+	now := time.Now().Unix()
+	for _, activity := range []struct {
+		since int64
+		set   func(*CollectionStats, int64)
+	}{
+		{now - 86400, func(c *CollectionStats, n int64) { c.Activity24h += n }},
+		{now - 86400*7, func(c *CollectionStats, n int64) { c.Activity7d += n }},
+	} {
+		counts := make([]*transferCount, 0)
+		if err := readDB().Model(&models.SrcTransfer{}).
+			Select("chain_id, asset, count(*) as count").
+			Where("standard in ? and time >= ?", models.NFTStandards, activity.since).
+			Group("chain_id, asset").
+			Find(&counts).Error; err != nil {
+			return nil, err
+		}
+		for _, v := range counts {
+			if collection := collectionOf(v.ChainId, v.Asset); collection != nil {
+				activity.set(collection, v.Count)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if err := s.countOwnerships(collections[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	stats := make([]*CollectionStats, 0, len(names))
+	for _, name := range names {
+		collection := collections[name]
+		sort.Slice(collection.ChainPairs, func(i, j int) bool {
+			if collection.ChainPairs[i].SrcChainId != collection.ChainPairs[j].SrcChainId {
+				return collection.ChainPairs[i].SrcChainId < collection.ChainPairs[j].SrcChainId
+			}
+			return collection.ChainPairs[i].DstChainId < collection.ChainPairs[j].DstChainId
+		})
+		stats = append(stats, collection)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Bridged > stats[j].Bridged
+	})
+	return stats, nil
+}
+
+// countOwnerships counts the holders and the locked tokens of the collection from the NFT ownership index,
+// the lock proxies are not holders.
+func (s *CollectionCounter) countOwnerships(collection *CollectionStats) error {
+	assetConds := make([]string, 0)
+	assetArgs := make([]interface{}, 0)
+	lockedConds := make([]string, 0)
+	lockedArgs := make([]interface{}, 0)
+	proxies := []string{models.NFTZeroAddress}
+	for _, asset := range nftAssets() {
+		if asset.TokenBasicName != collection.AssetName {
+			continue
+		}
+		hash := indexedAddress(asset.Hash)
+		assetConds = append(assetConds, "(chain_id = ? and asset = ?)")
+		assetArgs = append(assetArgs, asset.ChainId, hash)
+		cfg, ok := chainConfig[asset.ChainId]
+		if !ok {
+			continue
+		}
+		for _, proxy := range []string{cfg.NFTProxyContract, cfg.NFT1155ProxyContract} {
+			if proxy == "" {
+				continue
+			}
+			proxies = append(proxies, indexedAddress(proxy))
+			lockedConds = append(lockedConds, "(chain_id = ? and asset = ? and owner = ?)")
+			lockedArgs = append(lockedArgs, asset.ChainId, hash, indexedAddress(proxy))
+		}
+	}
+	if len(assetConds) == 0 {
+		return nil
+	}
+
+	if err := readDB().Model(&models.NFTOwnership{}).
+		Select("count(distinct owner)").
+		Where(strings.Join(assetConds, " or "), assetArgs...).
+		Where("owner not in ?", proxies).
+		Row().Scan(&collection.Holders); err != nil {
+		return err
+	}
+
+	if len(lockedConds) == 0 {
+		return nil
+	}
+	var locked sql.NullString
+	if err := readDB().Model(&models.NFTOwnership{}).
+		Select("sum(cast(quantity as decimal(65,0)))").
+		Where(strings.Join(lockedConds, " or "), lockedArgs...).
+		Row().Scan(&locked); err != nil {
+		return err
+	}
+	if locked.Valid {
+		collection.Locked = locked.String
+	}
+	return nil
+}
+
+func addChainPair(pairs []*ChainPairCount, srcChainId, dstChainId uint64, count int64) []*ChainPairCount {
+	for _, pair := range pairs {
+		if pair.SrcChainId == srcChainId && pair.DstChainId == dstChainId {
+			pair.Count += count
+			return pairs
+		}
+	}
+	return append(pairs, &ChainPairCount{SrcChainId: srcChainId, DstChainId: dstChainId, Count: count})
+}
+
+// indexedAddress is the address in the format of the transfers and the ownership index, lower case hex without 0x.
+func indexedAddress(hash string) string {
+	return strings.ToLower(common.HexToAddress(hash).String()[2:])
+}
+
+func assetKey(chainId uint64, hash string) string {
+	return fmt.Sprintf("%d:%s", chainId, indexedAddress(hash))
+}
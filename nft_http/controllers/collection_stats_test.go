@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSealEth   = "0xaa00000000000000000000000000000000000001"
+	testSealBsc   = "0xaa00000000000000000000000000000000000002"
+	testKittyEth  = "0xbb00000000000000000000000000000000000001"
+	testProxyEth  = "0xcc00000000000000000000000000000000000001"
+	testProxy1155 = "0xcc00000000000000000000000000000000000002"
+)
+
+func useTestCollections(t *testing.T) {
+	useTestAssets(t, []*models.Token{
+		{ChainId: 2, Hash: testSealEth, TokenBasicName: "seal", Standard: models.TokenTypeErc721},
+		{ChainId: 6, Hash: testSealBsc, TokenBasicName: "seal", Standard: models.TokenTypeErc721},
+		{ChainId: 2, Hash: testKittyEth, TokenBasicName: "kitty", Standard: models.TokenTypeErc1155},
+	}, &conf.ChainListenConfig{ChainId: 2, NFTProxyContract: testProxyEth, NFT1155ProxyContract: testProxy1155})
+}
+
+func TestCollectionCounterCount(t *testing.T) {
+	useTestCollections(t)
+	fake := useFakeDB(t)
+	fake.on("time >=", []string{"chain_id", "asset", "count"},
+		[]driver.Value{2, indexedAddress(testKittyEth), 4},
+		[]driver.Value{6, indexedAddress(testSealBsc), 1},
+	)
+	fake.on("dst_chain_id", []string{"chain_id", "asset", "dst_chain_id", "count"},
+		[]driver.Value{6, indexedAddress(testSealBsc), 2, 1},
+		[]driver.Value{2, indexedAddress(testKittyEth), 6, 2},
+		[]driver.Value{2, indexedAddress(testSealEth), 7, 2},
+		[]driver.Value{2, indexedAddress(testSealEth), 6, 3},
+		[]driver.Value{9, indexedAddress(testSealEth), 2, 10},
+	)
+	fake.on("count(distinct owner)", []string{"count"}, []driver.Value{7}).args = []interface{}{indexedAddress(testSealEth)}
+	fake.on("count(distinct owner)", []string{"count"}, []driver.Value{3}).args = []interface{}{indexedAddress(testKittyEth)}
+	fake.on("sum(cast(quantity", []string{"sum"}, []driver.Value{[]byte("12")}).args = []interface{}{indexedAddress(testKittyEth)}
+	fake.on("sum(cast(quantity", []string{"sum"}, []driver.Value{nil})
+
+	stats, err := NewCollectionCounter(nil).count()
+	assert.NoError(t, err)
+	assert.Equal(t, []*CollectionStats{
+		{
+			AssetName: "seal",
+			Standard:  models.TokenTypeErc721,
+			Bridged:   6,
+			ChainPairs: []*ChainPairCount{
+				{SrcChainId: 2, DstChainId: 6, Count: 3},
+				{SrcChainId: 2, DstChainId: 7, Count: 2},
+				{SrcChainId: 6, DstChainId: 2, Count: 1},
+			},
+			Holders:     7,
+			Locked:      "0",
+			Activity24h: 1,
+			Activity7d:  1,
+		},
+		{
+			AssetName:   "kitty",
+			Standard:    models.TokenTypeErc1155,
+			Bridged:     2,
+			ChainPairs:  []*ChainPairCount{{SrcChainId: 2, DstChainId: 6, Count: 2}},
+			Holders:     3,
+			Locked:      "12",
+			Activity24h: 4,
+			Activity7d:  4,
+		},
+	}, stats)
+
+	// the lock proxies are not holders, and only the assets of the chains with a config have locked tokens
+	for _, call := range fake.executed("count(distinct owner)") {
+		assert.Contains(t, call.args, indexedAddress(testProxyEth))
+		assert.Contains(t, call.args, indexedAddress(testProxy1155))
+		assert.Contains(t, call.args, models.NFTZeroAddress)
+	}
+	locked := fake.executed("sum(cast(quantity")
+	assert.Equal(t, 2, len(locked))
+	assert.NotContains(t, locked[0].args, indexedAddress(testSealBsc))
+}
+
+func TestCollections(t *testing.T) {
+	previous := collectionCounter
+	defer func() { collectionCounter = previous }()
+	collectionCounter = NewCollectionCounter(nil)
+	collectionCounter.stats = []*CollectionStats{{AssetName: "seal", Bridged: 3, ChainPairs: []*ChainPairCount{}, Locked: "0"}}
+	collectionCounter.updateTime = 1650000000
+
+	c := new(CollectionController)
+	rsp := new(CollectionsRsp)
+	assert.Equal(t, http.StatusOK, serveTest(t, &c.Controller, c.Collections, nil, "", nil, rsp))
+	assert.Equal(t, &CollectionsRsp{UpdateTime: 1650000000, Collections: collectionCounter.stats}, rsp)
+}
+
+func TestCollectionActivity(t *testing.T) {
+	useTestCollections(t)
+	previous := collectionCounter
+	defer func() { collectionCounter = previous }()
+	collectionCounter = NewCollectionCounter(nil)
+	collectionCounter.stats = []*CollectionStats{{AssetName: "seal", Bridged: 5, ChainPairs: []*ChainPairCount{}, Locked: "0"}}
+
+	fake := useFakeDB(t)
+	fake.on("limit ? offset ?", []string{"hash", "src_chain_id", "dst_chain_id", "time", "token_id", "quantity", "src_asset"},
+		[]driver.Value{"tx3", 2, 6, 1650000003, "3", "", indexedAddress(testSealEth)},
+		[]driver.Value{"tx2", 6, 2, 1650000002, "2", "1", indexedAddress(testSealBsc)},
+	)
+	fake.on("count(1)", []string{"count"}, []driver.Value{5})
+
+	c := new(CollectionController)
+	rsp := new(CollectionActivityRsp)
+	code := serveTest(t, &c.Controller, c.Activity, nil, "pagesize=2&pageno=1", map[string]string{":asset": "seal"}, rsp)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, collectionCounter.stats[0], rsp.Stats)
+	assert.Equal(t, 2, rsp.PageSize)
+	assert.Equal(t, 1, rsp.PageNo)
+	assert.Equal(t, 3, rsp.TotalPage)
+	assert.Equal(t, 5, rsp.TotalCount)
+	assert.Equal(t, 2, len(rsp.Transactions))
+	assert.Equal(t, &TransactionBriefRsp{Hash: "tx3", SrcChainId: 2, DstChainId: 6, Time: 1650000003, TokenId: "3", Quantity: "1", AssetName: "seal"}, rsp.Transactions[0])
+
+	pages := fake.executed("limit ? offset ?")
+	assert.Equal(t, 1, len(pages))
+	args := pages[0].args
+	assert.Equal(t, []interface{}{2, 2}, args[len(args)-2:])
+	assert.Contains(t, args, indexedAddress(testSealEth))
+	assert.Contains(t, args, indexedAddress(testSealBsc))
+	assert.NotContains(t, args, indexedAddress(testKittyEth))
+
+	for _, v := range []struct {
+		query string
+		asset string
+		code  int
+	}{
+		{"pagesize=0", "seal", ErrCodeRequest},
+		{"pageno=-1", "seal", ErrCodeRequest},
+		{"pagesize=11", "seal", ErrCodeRequest},
+		{"", "unknown", ErrCodeNotExist},
+	} {
+		c := new(CollectionController)
+		code := serveTest(t, &c.Controller, c.Activity, nil, v.query, map[string]string{":asset": v.asset}, nil)
+		assert.Equal(t, v.code, code, v.query)
+	}
+}
@@ -5,24 +5,44 @@ import (
 	"github.com/astaxie/beego"
 	"github.com/astaxie/beego/context"
 	"github.com/stretchr/testify/assert"
+	"os"
 	"poly-bridge/conf"
+	"poly-bridge/dbrouter"
 	"testing"
 )
 
 var (
 	configFile = "./../../conf/config_testnet.json"
+	// testnetAvailable is false when the database of the testnet config can not be connected,
+	// the tests reading the testnet are skipped then
+	testnetAvailable = false
 )
 
 func TestMain(m *testing.M) {
 	cfg := conf.NewConfig(configFile)
+	if cfg != nil && cfg.DBConfig != nil {
+		if cfg.DBConfig.Timeout <= 0 {
+			cfg.DBConfig.Timeout = 3
+		}
+		if router, err := dbrouter.Open(cfg.DBConfig); err == nil {
+			router.Close()
+			testnetAvailable = true
+			Initialize(cfg)
+		}
+	}
+	os.Exit(m.Run())
+}
 
-	Initialize(cfg)
-	m.Run()
+func skipWithoutTestnet(t *testing.T) {
+	if !testnetAvailable {
+		t.Skip("the testnet database is not available")
+	}
 }
 
 // todo(fuk): these test case are debug only! delete them after test.
 
 func TestInfoController_Home(t *testing.T) {
+	skipWithoutTestnet(t)
 	req := &HomeReq{
 		ChainId: 79,
 		Size:    10,
@@ -36,6 +56,7 @@ func TestInfoController_Home(t *testing.T) {
 }
 
 func TestItemController_Items(t *testing.T) {
+	skipWithoutTestnet(t)
 	req := ItemsOfAddressReq{
 		ChainId:  2,
 		Asset:    "a85c9fc8f2c9060d674e0ca97f703a0a30619305",
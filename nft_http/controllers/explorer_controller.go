@@ -23,12 +23,12 @@ func (c *ExplorerController) Transactions() {
 	relations := make([]*TransactionBriefRelation, 0)
 	limit := req.PageSize
 	offset := req.PageSize * req.PageNo
-	db.Raw("select wp.*, tr.amount as token_id, tr.asset as src_asset "+
+	readDB().Raw("select wp.*, tr.amount as token_id, tr.quantity as quantity, tr.asset as src_asset "+
 		"from wrapper_transactions wp "+
 		"left join src_transfers as tr on wp.hash=tr.tx_hash "+
-		"where wp.standard=? "+
+		"where wp.standard in ? "+
 		"order by wp.time desc "+
-		"limit ? offset ?", models.TokenTypeErc721, limit, offset).
+		"limit ? offset ?", models.NFTStandards, limit, offset).
 		Find(&relations)
 
 	transactionNum := txCounter.Number()
@@ -58,19 +58,19 @@ func (c *ExplorerController) TransactionsOfAddress() {
 	relations := make([]*TransactionBriefRelation, 0)
 	limit := req.PageSize
 	offset := req.PageSize * req.PageNo
-	db.Raw("select wp.*, tr.amount as token_id, tr.asset as src_asset "+
+	readDB().Raw("select wp.*, tr.amount as token_id, tr.quantity as quantity, tr.asset as src_asset "+
 		"from wrapper_transactions wp "+
 		"left join src_transfers as tr on wp.hash=tr.tx_hash "+
-		"where wp.standard=? and (wp.user in ? or wp.dst_user in ?) "+
+		"where wp.standard in ? and (wp.user in ? or wp.dst_user in ?) "+
 		"order by wp.time desc "+
 		"limit ? offset ?",
-		models.TokenTypeErc721, req.Addresses, req.Addresses, limit, offset).
+		models.NFTStandards, req.Addresses, req.Addresses, limit, offset).
 		Find(&relations)
 
 	var transactionNum int64
-	db.Model(&models.SrcTransfer{}).
+	readDB().Model(&models.SrcTransfer{}).
 		Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-		Where("src_transfers.standard = ? and (`from` in ? or src_transfers.dst_user in ?)", models.TokenTypeErc721, req.Addresses, req.Addresses).
+		Where("src_transfers.standard in ? and (`from` in ? or src_transfers.dst_user in ?)", models.NFTStandards, req.Addresses, req.Addresses).
 		Count(&transactionNum)
 	totalPage := (int(transactionNum) + req.PageSize - 1) / req.PageSize
 	totalCnt := int(transactionNum)
@@ -93,7 +93,7 @@ func (c *ExplorerController) TransactionDetail() {
 	}
 
 	relation := new(TransactionDetailRelation)
-	res := db.Table("src_transactions").
+	res := readDB().Table("src_transactions").
 		Select("src_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash, src_transactions.chain_id as chain_id, src_transfers.asset as token_hash").
 		Where("src_transactions.hash = ?", req.Hash).
 		Joins("left join src_transfers on src_transactions.hash = src_transfers.tx_hash").
@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"poly-bridge/conf"
+	"poly-bridge/dbrouter"
+	"poly-bridge/models"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/astaxie/beego"
+	beecontext "github.com/astaxie/beego/context"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeResult is the result of the queries containing match, the first matching result is used.
+type fakeResult struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
+	// args, when set, has to be one of the arguments of the query as well
+	args []interface{}
+}
+
+type fakeCall struct {
+	query string
+	args  []interface{}
+}
+
+// fakeDB answers the queries of gorm with the results registered by the test, and records the calls.
+type fakeDB struct {
+	lock    sync.Mutex
+	results []*fakeResult
+	calls   []*fakeCall
+}
+
+var (
+	fakeDBOnce sync.Once
+	fakeDBs    sync.Map
+)
+
+func (db *fakeDB) on(match string, columns []string, rows ...[]driver.Value) *fakeResult {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	result := &fakeResult{match: match, columns: columns, rows: rows}
+	db.results = append(db.results, result)
+	return result
+}
+
+// executed returns the calls of the queries containing match.
+func (db *fakeDB) executed(match string) []*fakeCall {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	calls := make([]*fakeCall, 0)
+	for _, call := range db.calls {
+		if strings.Contains(call.query, match) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (db *fakeDB) record(query string, args []driver.NamedValue) {
+	call := &fakeCall{query: query, args: make([]interface{}, 0, len(args))}
+	for _, arg := range args {
+		call.args = append(call.args, arg.Value)
+	}
+	db.calls = append(db.calls, call)
+}
+
+func (db *fakeDB) query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.record(query, args)
+	for _, result := range db.results {
+		if !strings.Contains(query, result.match) || !hasArgs(args, result.args) {
+			continue
+		}
+		return &fakeRows{columns: result.columns, rows: result.rows}, nil
+	}
+	return &fakeRows{}, nil
+}
+
+func hasArgs(args []driver.NamedValue, expect []interface{}) bool {
+	for _, v := range expect {
+		found := false
+		for _, arg := range args {
+			if fmt.Sprint(arg.Value) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	db, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("fake db %s does not exist", name)
+	}
+	return &fakeConn{db: db.(*fakeDB)}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare is not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *fakeConn) Commit() error { return nil }
+
+func (c *fakeConn) Rollback() error { return nil }
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.db.query(query, args)
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.lock.Lock()
+	defer c.db.lock.Unlock()
+	c.db.record(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) CheckNamedValue(value *driver.NamedValue) error {
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+// useFakeDB routes the database of the controllers to a new fake database until the test ends.
+func useFakeDB(t *testing.T) *fakeDB {
+	fakeDBOnce.Do(func() {
+		sql.Register("fakedb", fakeDriver{})
+	})
+	fake := new(fakeDB)
+	fakeDBs.Store(t.Name(), fake)
+	sqlDB, err := sql.Open("fakedb", t.Name())
+	assert.NoError(t, err)
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	previousRouter, previousDB := dbRouter, db
+	dbRouter, db = dbrouter.New(gormDB), gormDB
+	t.Cleanup(func() {
+		dbRouter, db = previousRouter, previousDB
+		fakeDBs.Delete(t.Name())
+		sqlDB.Close()
+	})
+	return fake
+}
+
+// useTestAssets replaces the NFT assets and the chain configs until the test ends.
+func useTestAssets(t *testing.T, testAssets []*models.Token, chains ...*conf.ChainListenConfig) {
+	assetsLock.RLock()
+	previousAssets, previousFeeTokens := assets, feeTokens
+	assetsLock.RUnlock()
+	previousChains := chainConfig
+	setAssets(testAssets, make(map[uint64]*models.Token))
+	chainConfig = make(map[uint64]*conf.ChainListenConfig)
+	for _, chain := range chains {
+		chainConfig[chain.ChainId] = chain
+	}
+	t.Cleanup(func() {
+		setAssets(previousAssets, previousFeeTokens)
+		chainConfig = previousChains
+	})
+}
+
+// useTestCache replaces the cache of the home pages and the items with an empty one until the test ends.
+func useTestCache(t *testing.T) {
+	cache, err := lru.NewARC(100)
+	assert.NoError(t, err)
+	previous := lruDB
+	lruDB = cache
+	t.Cleanup(func() { lruDB = previous })
+}
+
+// useTestChain reads the NFTs of the chain from chain until the test ends.
+func useTestChain(t *testing.T, chainId uint64, chain NFTChain, wrapper common.Address) {
+	sdkLock.Lock()
+	previousChain, chainOk := sdks[chainId]
+	previousWrapper, wrapperOk := wrapperAddrs[chainId]
+	sdks[chainId], wrapperAddrs[chainId] = chain, wrapper
+	sdkLock.Unlock()
+	t.Cleanup(func() {
+		sdkLock.Lock()
+		defer sdkLock.Unlock()
+		delete(sdks, chainId)
+		delete(wrapperAddrs, chainId)
+		if chainOk {
+			sdks[chainId] = previousChain
+		}
+		if wrapperOk {
+			wrapperAddrs[chainId] = previousWrapper
+		}
+	})
+}
+
+// serveTest runs the action of the controller on a request with the body, the url query and the path params,
+// and decodes the json response into rsp. It returns the status of the response.
+func serveTest(t *testing.T, c *beego.Controller, action func(), body interface{}, query string, params map[string]string, rsp interface{}) int {
+	recorder := httptest.NewRecorder()
+	ctx := beecontext.NewContext()
+	ctx.Reset(recorder, httptest.NewRequest("POST", "/?"+query, nil))
+	if body != nil {
+		data, err := json.Marshal(body)
+		assert.NoError(t, err)
+		ctx.Input.RequestBody = data
+	}
+	for k, v := range params {
+		ctx.Input.SetParam(k, v)
+	}
+	c.Init(ctx, "", "", nil)
+	action()
+	if rsp != nil {
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), rsp), recorder.Body.String())
+	}
+	return recorder.Code
+}
@@ -22,6 +22,7 @@ import (
 	"math/big"
 	"poly-bridge/basedef"
 	"poly-bridge/models"
+	"time"
 
 	"github.com/astaxie/beego"
 )
@@ -31,12 +32,12 @@ type FeeController struct {
 }
 
 func (c *FeeController) GetFee() {
-	var req models.GetFeeReq
+	var req GetFeeReq
 	if !input(&c.Controller, &req) {
 		return
 	}
 	token := new(models.Token)
-	res := db.Where("hash = ? and chain_id = ?", req.Hash, req.SrcChainId).
+	res := readDB().Where("hash = ? and chain_id = ?", req.Hash, req.SrcChainId).
 		Preload("TokenBasic").
 		First(token)
 	if res.RowsAffected == 0 {
@@ -46,21 +47,144 @@ func (c *FeeController) GetFee() {
 		return
 	}
 	chainFee := new(models.ChainFee)
-	res = db.Where("chain_id = ?", req.DstChainId).Preload("TokenBasic").First(chainFee)
+	res = readDB().Where("chain_id = ?", req.DstChainId).
+		Preload("TokenBasic").
+		Preload("AssetFees").
+		First(chainFee)
 	if res.RowsAffected == 0 {
 		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have fee", req.DstChainId))
 		c.Ctx.ResponseWriter.WriteHeader(400)
 		c.ServeJSON()
 		return
 	}
+	// the route and the fee policy follow the asset transferred, as CheckFee does
+	assetName, dstAsset := "", ""
+	standard := models.TokenTypeErc721
+	if token.Standard == models.TokenTypeErc1155 {
+		standard = models.TokenTypeErc1155
+	}
+	if req.Asset != "" {
+		if asset := selectNFTAsset(req.Asset); asset != nil {
+			assetName = asset.TokenBasicName
+			standard = asset.Standard
+		}
+		tokenMap := new(models.TokenMap)
+		res = readDB().Where("src_chain_id = ? and src_token_hash = ? and dst_chain_id = ?", req.SrcChainId, req.Asset, req.DstChainId).
+			Find(tokenMap)
+		if res.RowsAffected > 0 {
+			dstAsset = tokenMap.DstTokenHash
+		}
+	}
+	chainFee = nftChainFee(chainFee, standard, req.SrcChainId, dstAsset)
 	proxyFee := new(big.Float).SetInt(&chainFee.ProxyFee.Int)
 	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.FEE_PRECISION))
 	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
 	usdtFee := new(big.Float).Mul(proxyFee, new(big.Float).SetInt64(chainFee.TokenBasic.Price))
 	usdtFee = new(big.Float).Quo(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+	policyId := int64(0)
+	feePolicies := make([]*models.FeePolicy, 0)
+	readDB().Find(&feePolicies)
+	feePolicy := models.EvaluateFeePolicy(feePolicies, req.SrcChainId, req.DstChainId, assetName, req.User, time.Now().Unix())
+	if feePolicy != nil {
+		usdtFee = feePolicy.Apply(usdtFee)
+		policyId = feePolicy.Id
+	}
 	tokenFee := new(big.Float).Mul(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
 	tokenFee = new(big.Float).Quo(tokenFee, new(big.Float).SetInt64(token.TokenBasic.Price))
 	tokenFeeWithPrecision := new(big.Float).Mul(tokenFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
-	c.Data["json"] = models.MakeGetFeeRsp(req.SrcChainId, req.Hash, req.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision)
+	c.Data["json"] = models.MakeGetFeeRsp(req.SrcChainId, req.Hash, req.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision, policyId)
 	c.ServeJSON()
 }
+
+// CheckFee checks the fees paid by the NFT wrapper transactions against the fees of unlocking their assets,
+// it reads from the primary since the relayer checks the transactions right after they are listened.
+func (c *FeeController) CheckFee() {
+	var req models.CheckFeesReq
+	if !input(&c.Controller, &req) {
+		return
+	}
+
+	hash2ChainId := make(map[string]uint64)
+	requestHashes := make([]string, 0)
+	for _, check := range req.Checks {
+		hash2ChainId[check.Hash] = check.ChainId
+		requestHashes = append(requestHashes, check.Hash, basedef.HexStringReverse(check.Hash))
+	}
+	srcTransactions := make([]*models.SrcTransaction, 0)
+	db.Where("(`key` in ? or `hash` in ?) and standard in ?", requestHashes, requestHashes, models.NFTStandards).
+		Find(&srcTransactions)
+	key2TxHash := make(map[string]string)
+	for _, tx := range srcTransactions {
+		// the keys of different chains may be the same
+		if chainId, ok := hash2ChainId[tx.Key]; ok && chainId == tx.ChainId {
+			key2TxHash[tx.Key] = tx.Hash
+		}
+		key2TxHash[tx.Hash] = tx.Hash
+		key2TxHash[basedef.HexStringReverse(tx.Hash)] = tx.Hash
+	}
+	txHashes := make([]string, 0)
+	for _, hash := range key2TxHash {
+		txHashes = append(txHashes, hash)
+	}
+
+	wrapperTransactions := make([]*models.WrapperTransactionWithToken, 0)
+	db.Table("wrapper_transactions").Where("hash in ?", txHashes).
+		Preload("FeeToken").
+		Preload("FeeToken.TokenBasic").
+		Find(&wrapperTransactions)
+	txHash2Wrapper := make(map[string]*models.WrapperTransactionWithToken)
+	for _, wrapper := range wrapperTransactions {
+		txHash2Wrapper[wrapper.Hash] = wrapper
+	}
+	srcTransfers := make([]*models.SrcTransfer, 0)
+	db.Where("tx_hash in ?", txHashes).Find(&srcTransfers)
+	txHash2Transfer := make(map[string]*models.SrcTransfer)
+	for _, transfer := range srcTransfers {
+		txHash2Transfer[transfer.TxHash] = transfer
+	}
+	chainFees := make([]*models.ChainFee, 0)
+	db.Preload("TokenBasic").Preload("AssetFees").Find(&chainFees)
+	chain2Fee := make(map[uint64]*models.ChainFee)
+	for _, chainFee := range chainFees {
+		chain2Fee[chainFee.ChainId] = chainFee
+	}
+	feePolicies := make([]*models.FeePolicy, 0)
+	db.Find(&feePolicies)
+	now := time.Now().Unix()
+
+	checkFees := make([]*models.CheckFee, 0)
+	for _, check := range req.Checks {
+		checkFee := &models.CheckFee{
+			Hash:        check.Hash,
+			ChainId:     check.ChainId,
+			Amount:      new(big.Float).SetInt64(0),
+			MinProxyFee: new(big.Float).SetInt64(0),
+		}
+		checkFees = append(checkFees, checkFee)
+		txHash, ok := key2TxHash[check.Hash]
+		if !ok {
+			continue
+		}
+		wrapper, ok := txHash2Wrapper[txHash]
+		if !ok {
+			checkFee.PayState = -1
+			continue
+		}
+		chainFee, ok := chain2Fee[wrapper.DstChainId]
+		if !ok {
+			checkFee.PayState = -1
+			continue
+		}
+		assetName, dstAsset := "", ""
+		if transfer, ok := txHash2Transfer[txHash]; ok {
+			dstAsset = transfer.DstAsset
+			if token := selectNFTAsset(transfer.Asset); token != nil {
+				assetName = token.TokenBasicName
+			}
+		}
+		chainFee = nftChainFee(chainFee, wrapper.Standard, wrapper.SrcChainId, dstAsset)
+
+		models.CheckPaidFee(checkFee, wrapper, chainFee, feePolicies, assetName, now)
+	}
+	output(&c.Controller, models.MakeCheckFeesRsp(checkFees))
+}
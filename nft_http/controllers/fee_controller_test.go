@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"poly-bridge/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFee(t *testing.T) {
+	useTestCollections(t)
+	fake := useFakeDB(t)
+	fake.on("FROM `src_transactions`", []string{"hash", "key", "chain_id", "standard"},
+		[]driver.Value{"1111", "1111", 6, models.TokenTypeErc721},
+		[]driver.Value{"2222", "2222", 6, models.TokenTypeErc721},
+		[]driver.Value{"3333", "3333", 6, models.TokenTypeErc721},
+		[]driver.Value{"5555", "5555", 6, models.TokenTypeErc721},
+	)
+	// the fee token is 1 usd with 6 decimals
+	fake.on("FROM `wrapper_transactions`", []string{"hash", "src_chain_id", "dst_chain_id", "standard", "fee_token_hash", "fee_amount"},
+		[]driver.Value{"1111", 6, 2, models.TokenTypeErc721, "fee", []byte("5000000")},
+		[]driver.Value{"2222", 6, 2, models.TokenTypeErc721, "fee", []byte("3000000")},
+		[]driver.Value{"5555", 6, 9, models.TokenTypeErc721, "fee", []byte("5000000")},
+	)
+	fake.on("FROM `tokens`", []string{"hash", "chain_id", "precision", "token_basic_name"},
+		[]driver.Value{"fee", 6, 6, "USDT"},
+	)
+	fake.on("FROM `token_basics`", []string{"name", "price", "precision"},
+		[]driver.Value{"USDT", 100000000, 0},
+		[]driver.Value{"ETH", 100000000, 0},
+	)
+	fake.on("FROM `src_transfers`", []string{"tx_hash", "chain_id", "asset", "dst_chain_id", "dst_asset"},
+		[]driver.Value{"1111", 6, indexedAddress(testSealBsc), 2, ""},
+		[]driver.Value{"2222", 6, indexedAddress(testSealBsc), 2, ""},
+	)
+	// the least fee is 2 usd, and 4 usd for seal by the policy
+	fake.on("FROM `chain_fees`", []string{"chain_id", "token_basic_name", "min_fee", "max_fee", "proxy_fee"},
+		[]driver.Value{2, "ETH", []byte("200000000"), []byte("200000000"), []byte("200000000")},
+	)
+	fake.on("FROM `fee_policies`", []string{"id", "name", "version", "token_basic_name", "min_usd", "ind"},
+		[]driver.Value{7, "seal", 1, "seal", 400000000, 1},
+	)
+
+	req := &models.CheckFeesReq{Checks: []*models.CheckFeeReq{
+		{ChainId: 6, Hash: "1111"},
+		{ChainId: 6, Hash: "2222"},
+		{ChainId: 6, Hash: "3333"},
+		{ChainId: 6, Hash: "4444"},
+		{ChainId: 6, Hash: "5555"},
+	}}
+	c := new(FeeController)
+	rsp := new(models.CheckFeesRsp)
+	assert.Equal(t, http.StatusOK, serveTest(t, &c.Controller, c.CheckFee, req, "", nil, rsp))
+	assert.Equal(t, uint64(5), rsp.TotalCount)
+	for i, v := range []struct {
+		payState int
+		amount   string
+		minFee   string
+		policyId int64
+	}{
+		{payState: 1, amount: "5", minFee: "4", policyId: 7},
+		{payState: -1, amount: "3", minFee: "4", policyId: 7},
+		{payState: -1, amount: "0", minFee: "0"},
+		{payState: 0, amount: "0", minFee: "0"},
+		{payState: -1, amount: "0", minFee: "0"},
+	} {
+		checkFee := rsp.CheckFees[i]
+		assert.Equal(t, req.Checks[i].Hash, checkFee.Hash)
+		assert.Equal(t, v.payState, checkFee.PayState, checkFee.Hash)
+		assert.Equal(t, v.amount, checkFee.Amount, checkFee.Hash)
+		assert.Equal(t, v.minFee, checkFee.MinProxyFee, checkFee.Hash)
+		assert.Equal(t, v.policyId, checkFee.PolicyId, checkFee.Hash)
+	}
+}
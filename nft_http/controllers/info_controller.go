@@ -22,9 +22,12 @@ import (
 	"poly-bridge/models"
 	"poly-bridge/nft_http/meta"
 	"poly-bridge/utils/net"
+	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/logs"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -62,25 +65,39 @@ func (c *InfoController) Home() {
 		return
 	}
 
-	cache, ok := GetHomePageCache(req.ChainId)
-	if ok && cache != nil && cache.Time.Add(600*time.Second).After(time.Now()) {
+	cache, ok := GetHomePageCache(req.ChainId, req.Size)
+	if ok && cache.Fresh() {
+		output(&c.Controller, cache.Rsp)
+		return
+	}
+	if ok && cachePolicy.StaleWhileRevalidate {
+		refreshHomePageAsync(req.ChainId, req.Size)
 		output(&c.Controller, cache.Rsp)
 		return
 	}
 
-	sdk, wrapper, err := selectNodeAndWrapper(req.ChainId)
+	data, err := refreshHomePage(req.ChainId, req.Size)
 	if err != nil {
 		customInput(&c.Controller, ErrCodeRequest, "chain id not exist")
 		return
 	}
+	output(&c.Controller, data)
+}
+
+// refreshHomePage reads the home page of the chain in the page size and caches it.
+func refreshHomePage(chainId uint64, size int) (*HomeRsp, error) {
+	sdk, wrapper, err := selectNodeAndWrapper(chainId)
+	if err != nil {
+		return nil, err
+	}
 
-	chainAssets := selectAssetsByChainId(req.ChainId)
+	chainAssets := selectAssetsByChainId(chainId)
 	totalCnt := len(chainAssets)
 	list := make([]*AssetItems, 0)
 	for _, v := range chainAssets {
 		if v.TokenBasic.MetaFetcherType != meta.FetcherTypeUnknown {
 			addr := common.HexToAddress(v.Hash)
-			tokenUrls, _ := sdk.GetUnCrossChainNFTsByIndex(wrapper, addr, 0, req.Size)
+			tokenUrls, _ := sdk.GetUnCrossChainNFTsByIndex(wrapper, addr, 0, size)
 			if len(tokenUrls) == 0 {
 				continue
 			}
@@ -95,11 +112,34 @@ func (c *InfoController) Home() {
 	}
 
 	data := new(HomeRsp).instance(totalCnt, list)
-	SetHomePageCache(req.ChainId, &CacheHomeRsp{
+	SetHomePageCache(chainId, &CacheHomeRsp{
 		Rsp:  data,
+		Size: size,
 		Time: time.Now(),
 	})
-	output(&c.Controller, data)
+	return data, nil
+}
+
+var homePageRefreshing sync.Map
+
+// refreshHomePageAsync refreshes the home page of the chain in the background, once at a time for a chain and
+// a page size.
+func refreshHomePageAsync(chainId uint64, size int) {
+	key := formatHomePageCacheKey(chainId, size)
+	if _, loaded := homePageRefreshing.LoadOrStore(key, true); loaded {
+		return
+	}
+	go func() {
+		defer homePageRefreshing.Delete(key)
+		defer func() {
+			if r := recover(); r != nil {
+				logs.Error("refresh home page of chain %d recover info: %s", chainId, string(debug.Stack()))
+			}
+		}()
+		if _, err := refreshHomePage(chainId, size); err != nil {
+			logs.Error("refresh home page of chain %d err: %v", chainId, err)
+		}
+	}()
 }
 
 func SetBaseInfo(_mode string, _port int) {
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/nft_http/meta"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// testHomeChain lists the token ids from 1 to the length as the tokens not bridged, and counts the listings.
+type testHomeChain struct {
+	listed int32
+}
+
+func (c *testHomeChain) NFTBalance(asset, owner common.Address) (*big.Int, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (c *testHomeChain) GetNFTUrl(asset common.Address, tokenId *big.Int) (string, error) {
+	return "", fmt.Errorf("not supported")
+}
+
+func (c *testHomeChain) GetAndCheckTokenUrl(wrapper, asset, owner common.Address, tokenId *big.Int) (string, error) {
+	return "", fmt.Errorf("not supported")
+}
+
+func (c *testHomeChain) GetTokensByIndex(wrapper, asset, owner common.Address, start, length int) (map[string]string, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (c *testHomeChain) GetTokensById(wrapper, asset common.Address, tokenIdList []*big.Int) (map[string]string, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (c *testHomeChain) GetUnCrossChainNFTsByIndex(wrapper, asset common.Address, start, length int) (map[string]string, error) {
+	atomic.AddInt32(&c.listed, 1)
+	urls := make(map[string]string)
+	for i := start + 1; i <= start+length; i++ {
+		urls[fmt.Sprint(i)] = fmt.Sprintf("https://nft.example.com/%d", i)
+	}
+	return urls, nil
+}
+
+func (c *testHomeChain) ERC1155Balance(asset, owner common.Address, tokenId *big.Int) (*big.Int, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (c *testHomeChain) GetERC1155Url(asset common.Address, tokenId *big.Int) (string, error) {
+	return "", fmt.Errorf("not supported")
+}
+
+func (c *testHomeChain) listings() int {
+	return int(atomic.LoadInt32(&c.listed))
+}
+
+func useTestHomePage(t *testing.T, swr bool) *testHomeChain {
+	useTestCache(t)
+	useTestAssets(t, []*models.Token{
+		{ChainId: 2, Hash: testSealEth, TokenBasicName: "seal", TokenBasic: &models.TokenBasic{MetaFetcherType: int(meta.FetcherTypeStandard)}},
+	}, &conf.ChainListenConfig{ChainId: 2})
+	chain := new(testHomeChain)
+	useTestChain(t, 2, chain, common.HexToAddress(testProxyEth))
+
+	previousFetcher, previousPolicy := fetcher, cachePolicy
+	fetcher = meta.NewStoreFetcher(nil)
+	cachePolicy = &CachePolicy{HomeTTL: time.Minute, ItemTTL: time.Minute, StaleWhileRevalidate: swr}
+	t.Cleanup(func() {
+		fetcher, cachePolicy = previousFetcher, previousPolicy
+	})
+	return chain
+}
+
+func TestRefreshHomePage(t *testing.T) {
+	chain := useTestHomePage(t, false)
+
+	rsp, err := refreshHomePage(2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), rsp.TotalCount)
+	assert.Equal(t, 1, len(rsp.Assets))
+	assert.Equal(t, testSealEth, rsp.Assets[0].Asset.Hash)
+	tokenIds := make([]string, 0)
+	for _, item := range rsp.Assets[0].Items {
+		tokenIds = append(tokenIds, item.TokenId)
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, tokenIds)
+	assert.Equal(t, 1, chain.listings())
+
+	cache, ok := GetHomePageCache(2, 3)
+	assert.True(t, ok)
+	assert.True(t, cache.Fresh())
+	assert.Equal(t, rsp, cache.Rsp)
+	_, ok = GetHomePageCache(2, 5)
+	assert.False(t, ok, "the home pages of the page sizes are cached apart")
+
+	_, err = refreshHomePage(3, 3)
+	assert.Error(t, err)
+}
+
+func requestHome(t *testing.T, chainId uint64, size int) *HomeRsp {
+	c := new(InfoController)
+	rsp := new(HomeRsp)
+	assert.Equal(t, http.StatusOK, serveTest(t, &c.Controller, c.Home, &HomeReq{ChainId: chainId, Size: size}, "", nil, rsp))
+	return rsp
+}
+
+func TestHomeCache(t *testing.T) {
+	chain := useTestHomePage(t, false)
+
+	assert.Equal(t, 3, len(requestHome(t, 2, 3).Assets[0].Items))
+	assert.Equal(t, 3, len(requestHome(t, 2, 3).Assets[0].Items))
+	assert.Equal(t, 1, chain.listings(), "the fresh home page is served from the cache")
+
+	assert.Equal(t, 5, len(requestHome(t, 2, 5).Assets[0].Items))
+	assert.Equal(t, 2, chain.listings(), "a page size is not served with the home page of another")
+
+	// an invalidated home page is refreshed on the request without the stale while revalidate mode
+	InvalidateHomePageCache(2)
+	for _, size := range []int{3, 5} {
+		cache, ok := GetHomePageCache(2, size)
+		assert.True(t, ok)
+		assert.False(t, cache.Fresh())
+	}
+	assert.Equal(t, 3, len(requestHome(t, 2, 3).Assets[0].Items))
+	assert.Equal(t, 3, chain.listings())
+}
+
+func TestHomeStaleWhileRevalidate(t *testing.T) {
+	chain := useTestHomePage(t, true)
+
+	stale := &HomeRsp{TotalCount: 9, Assets: []*AssetItems{}}
+	SetHomePageCache(2, &CacheHomeRsp{Rsp: stale, Size: 3})
+	assert.Equal(t, stale, requestHome(t, 2, 3))
+	assert.Eventually(t, func() bool {
+		cache, ok := GetHomePageCache(2, 3)
+		return ok && cache.Fresh()
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, chain.listings())
+	assert.Equal(t, uint64(1), requestHome(t, 2, 3).TotalCount)
+}
@@ -18,8 +18,9 @@
 package controllers
 
 import (
+	"fmt"
 	"math/big"
-	"poly-bridge/chainsdk"
+	"poly-bridge/chainaddr"
 	"poly-bridge/models"
 	mcm "poly-bridge/nft_http/meta/common"
 	"sort"
@@ -29,6 +30,7 @@ import (
 	"github.com/astaxie/beego"
 	"github.com/astaxie/beego/logs"
 	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
 )
 
 type ItemController struct {
@@ -40,15 +42,22 @@ func (c *ItemController) Items() {
 	if !input(&c.Controller, &req) {
 		return
 	}
+	// the owner is the hash of the address as the contracts and the ownership index have it, the base58
+	// addresses of NEO and Ontology are not hex
+	ownerHash, err := chainaddr.ToHash(req.ChainId, req.Address)
+	if err != nil {
+		customInput(&c.Controller, ErrCodeRequest, err.Error())
+		return
+	}
 
 	if strings.Trim(req.TokenId, " ") != "" {
-		c.fetchSingleNFTItem(&req)
+		c.fetchSingleNFTItem(&req, ownerHash)
 	} else {
-		c.batchFetchNFTItems(&req)
+		c.batchFetchNFTItems(&req, common.HexToAddress(ownerHash))
 	}
 }
 
-func (c *ItemController) fetchSingleNFTItem(req *ItemsOfAddressReq) {
+func (c *ItemController) fetchSingleNFTItem(req *ItemsOfAddressReq, ownerHash string) {
 	// check params
 	tokenId, err := checkNumString(req.TokenId)
 	if err != nil {
@@ -66,20 +75,23 @@ func (c *ItemController) fetchSingleNFTItem(req *ItemsOfAddressReq) {
 		return
 	}
 
-	item, err := getSingleItem(sdk, wrapper, token, tokenId, req.Address)
+	item, err := getSingleItem(sdk, wrapper, token, tokenId, ownerHash)
 	if err != nil {
 		logs.Error("get single item err: %v", err)
 	}
 
 	items := make([]*Item, 0)
 	if item != nil {
+		if item.Quantity == "" {
+			item = item.withQuantity("1")
+		}
 		items = append(items, item)
 	}
 	data := new(ItemsOfAddressRsp).instance(req.PageSize, req.PageNo, 0, len(items), items)
 	output(&c.Controller, data)
 }
 
-func (c *ItemController) batchFetchNFTItems(req *ItemsOfAddressReq) {
+func (c *ItemController) batchFetchNFTItems(req *ItemsOfAddressReq, owner common.Address) {
 	// check params
 	if !checkPageSize(&c.Controller, req.PageSize) {
 		return
@@ -95,14 +107,35 @@ func (c *ItemController) batchFetchNFTItems(req *ItemsOfAddressReq) {
 		return
 	}
 
-	// get user balance and format page attribute
+	// page from the ownership index, and fall back to the chain data if the index misses tokens of the owner,
+	// which were transferred before their transfers were indexed
 	asset := common.HexToAddress(token.Hash)
-	owner := common.HexToAddress(req.Address)
+	indexedCnt := c.countIndexedNFTItems(token, owner)
+	// the tokens of an ERC1155 owner can not be listed from the chain
+	if token.Standard == models.TokenTypeErc1155 {
+		if indexedCnt > 0 {
+			c.batchFetchIndexedNFTItems(req, sdk, wrapper, token, owner, indexedCnt)
+			return
+		}
+		output(&c.Controller, new(ItemsOfAddressRsp).instance(req.PageSize, req.PageNo, 0, 0, nil))
+		return
+	}
+
+	// get user balance and format page attribute
 	bigTotalCnt, err := sdk.NFTBalance(asset, owner)
 	if err != nil {
+		if indexedCnt > 0 {
+			logs.Error("NFTBalance err: %v", err)
+			c.batchFetchIndexedNFTItems(req, sdk, wrapper, token, owner, indexedCnt)
+			return
+		}
 		customInput(&c.Controller, ErrCodeRequest, err.Error())
 		return
 	}
+	if indexedCnt > 0 && big.NewInt(indexedCnt).Cmp(bigTotalCnt) >= 0 {
+		c.batchFetchIndexedNFTItems(req, sdk, wrapper, token, owner, indexedCnt)
+		return
+	}
 	totalCnt := int(bigTotalCnt.Uint64())
 	totalPage := getPageNo(totalCnt, req.PageSize)
 
@@ -131,6 +164,11 @@ func (c *ItemController) batchFetchNFTItems(req *ItemsOfAddressReq) {
 	tokenIdUrlMap, err := sdk.GetTokensByIndex(wrapper, asset, owner, start, length)
 	if err != nil {
 		logs.Error("GetOwnerNFTsByIndex err: %v", err)
+		// the collection may not be enumerable, the indexed tokens are better than none
+		if indexedCnt > 0 {
+			c.batchFetchIndexedNFTItems(req, sdk, wrapper, token, owner, indexedCnt)
+			return
+		}
 		response(nil)
 		return
 	}
@@ -140,17 +178,82 @@ func (c *ItemController) batchFetchNFTItems(req *ItemsOfAddressReq) {
 	}
 
 	items := getItemsWithChainData(token.TokenBasicName, token.Hash, token.ChainId, tokenIdUrlMap)
-	response(items)
+	response(itemsWithQuantity(items, nil))
+}
+
+func indexedNFTOwnerships(token *models.Token, owner common.Address) *gorm.DB {
+	asset := common.HexToAddress(token.Hash)
+	return readDB().Model(&models.NFTOwnership{}).Where("chain_id = ? and asset = ? and owner = ?",
+		token.ChainId, strings.ToLower(asset.String()[2:]), strings.ToLower(owner.String()[2:]))
+}
+
+// countIndexedNFTItems returns the number of tokens of the owner indexed from the Transfer events.
+func (c *ItemController) countIndexedNFTItems(token *models.Token, owner common.Address) int64 {
+	var totalCnt int64
+	if err := indexedNFTOwnerships(token, owner).Count(&totalCnt).Error; err != nil {
+		logs.Error("count nft ownerships err: %v", err)
+		return 0
+	}
+	return totalCnt
+}
+
+// batchFetchIndexedNFTItems pages the totalCnt tokens of the owner indexed from the Transfer events, which also
+// works for the collections without ERC721Enumerable.
+func (c *ItemController) batchFetchIndexedNFTItems(
+	req *ItemsOfAddressReq,
+	sdk NFTChain,
+	wrapper common.Address,
+	token *models.Token,
+	owner common.Address,
+	totalCnt int64,
+) {
+	asset := common.HexToAddress(token.Hash)
+	totalPage := getPageNo(int(totalCnt), req.PageSize)
+	start := req.PageNo * req.PageSize
+	if int64(start) >= totalCnt {
+		customInput(&c.Controller, ErrCodeRequest, "start out of range")
+		return
+	}
+
+	ownershipList := make([]*models.NFTOwnership, 0)
+	indexedNFTOwnerships(token, owner).Order("length(token_id) asc, token_id asc").Limit(req.PageSize).Offset(start).Find(&ownershipList)
+	tokenIdList := make([]*big.Int, 0, len(ownershipList))
+	quantities := make(map[string]string)
+	for _, v := range ownershipList {
+		if tokenId, ok := new(big.Int).SetString(v.TokenId, 10); ok {
+			tokenIdList = append(tokenIdList, tokenId)
+			quantities[tokenId.String()] = quantityOf(v.Quantity)
+		}
+	}
+	var tokenIdUrlMap map[string]string
+	var err error
+	if token.Standard == models.TokenTypeErc1155 {
+		tokenIdUrlMap = getERC1155Urls(sdk, asset, tokenIdList)
+	} else if tokenIdUrlMap, err = sdk.GetTokensById(wrapper, asset, tokenIdList); err != nil {
+		logs.Error("GetTokensById err: %v", err)
+		tokenIdUrlMap = make(map[string]string)
+		for _, v := range tokenIdList {
+			tokenIdUrlMap[v.String()] = ""
+		}
+	}
+
+	items := itemsWithQuantity(getItemsWithChainData(token.TokenBasicName, token.Hash, token.ChainId, tokenIdUrlMap), quantities)
+	data := new(ItemsOfAddressRsp).instance(req.PageSize, req.PageNo, totalPage, int(totalCnt), items)
+	output(&c.Controller, data)
 }
 
 func getSingleItem(
-	sdk *chainsdk.EthereumSdkPro,
+	sdk NFTChain,
 	wrapper common.Address,
 	asset *models.Token,
 	tokenId *big.Int,
 	ownerHash string,
 ) (item *Item, err error) {
 
+	if asset.Standard == models.TokenTypeErc1155 {
+		return getSingleERC1155Item(sdk, asset, tokenId, ownerHash)
+	}
+
 	// get and output cache if exist
 	cache, ok := GetItemCache(asset.ChainId, asset.Hash, tokenId.String())
 	if ok {
@@ -180,6 +283,72 @@ func getSingleItem(
 	return
 }
 
+// getSingleERC1155Item gets the item with the balance of the owner, the wrapper contract can not check the
+// owner of an ERC1155 token.
+func getSingleERC1155Item(
+	sdk NFTChain,
+	asset *models.Token,
+	tokenId *big.Int,
+	ownerHash string,
+) (*Item, error) {
+	assetAddr := common.HexToAddress(asset.Hash)
+	quantity := ""
+	if ownerHash != "" {
+		balance, err := sdk.ERC1155Balance(assetAddr, common.HexToAddress(ownerHash), tokenId)
+		if err != nil {
+			return nil, err
+		}
+		if balance.Sign() == 0 {
+			return nil, fmt.Errorf("%s does not own token %s", ownerHash, tokenId.String())
+		}
+		quantity = balance.String()
+	}
+
+	item, ok := GetItemCache(asset.ChainId, asset.Hash, tokenId.String())
+	if !ok {
+		url, err := sdk.GetERC1155Url(assetAddr, tokenId)
+		if err != nil {
+			return nil, err
+		}
+		profile, _ := fetcher.Fetch(asset.TokenBasicName, &mcm.FetchRequestParams{
+			TokenId: tokenId.String(),
+			Url:     url,
+		})
+		item = new(Item).instance(asset.TokenBasicName, tokenId.String(), profile)
+		SetItemCache(asset.ChainId, asset.Hash, tokenId.String(), item)
+	}
+	if quantity == "" {
+		return item, nil
+	}
+	return item.withQuantity(quantity), nil
+}
+
+// getERC1155Urls reads the metadata urls of the tokens, the url of a token which fails is empty.
+func getERC1155Urls(sdk NFTChain, asset common.Address, tokenIdList []*big.Int) map[string]string {
+	tokenIdUrlMap := make(map[string]string)
+	for _, tokenId := range tokenIdList {
+		url, err := sdk.GetERC1155Url(asset, tokenId)
+		if err != nil {
+			logs.Error("GetERC1155Url %s err: %v", tokenId.String(), err)
+		}
+		tokenIdUrlMap[tokenId.String()] = url
+	}
+	return tokenIdUrlMap
+}
+
+// itemsWithQuantity sets the quantities of the items owned, the quantity of a token not in quantities is 1.
+func itemsWithQuantity(items []*Item, quantities map[string]string) []*Item {
+	list := make([]*Item, 0, len(items))
+	for _, item := range items {
+		quantity, ok := quantities[item.TokenId]
+		if !ok {
+			quantity = "1"
+		}
+		list = append(list, item.withQuantity(quantity))
+	}
+	return list
+}
+
 func getItemsWithChainData(name string, asset string, chainId uint64, tokenIdUrlMap map[string]string) []*Item {
 	list := make([]*Item, 0)
 
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"poly-bridge/basedef"
+	"poly-bridge/chainaddr"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/nft_http/meta"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemsOfNeoAddress(t *testing.T) {
+	const (
+		neoAsset   = "2f8a0b9f2bd3d5c1c43bd4cf2a7c0e7ba32fd1c8"
+		neoAddress = "AQzRMe3zyGS8W177xLJfewRRQZY2kddMun"
+	)
+	ownerHash, err := chainaddr.ToHash(basedef.NEO_CROSSCHAIN_ID, neoAddress)
+	assert.NoError(t, err)
+
+	useTestCache(t)
+	useTestAssets(t, []*models.Token{
+		{ChainId: basedef.NEO_CROSSCHAIN_ID, Hash: neoAsset, TokenBasicName: "seal", Standard: models.TokenTypeErc721},
+	}, &conf.ChainListenConfig{ChainId: basedef.NEO_CROSSCHAIN_ID})
+	contract := &testNFTContract{owners: map[string]string{
+		"1": ownerHash,
+		"2": "1111111111111111111111111111111111111111",
+		"3": ownerHash,
+	}}
+	useTestChain(t, basedef.NEO_CROSSCHAIN_ID, newContractNFTChain(basedef.NEO_CROSSCHAIN_ID, contract), emptyAddr)
+	previousFetcher := fetcher
+	fetcher = meta.NewStoreFetcher(nil)
+	defer func() { fetcher = previousFetcher }()
+	fake := useFakeDB(t)
+
+	tokenIds := func(rsp *ItemsOfAddressRsp) []string {
+		ids := make([]string, 0)
+		for _, item := range rsp.Items {
+			ids = append(ids, item.TokenId)
+		}
+		return ids
+	}
+
+	// the tokens are listed from the contract, as the ownership index does not have the owner
+	c := new(ItemController)
+	rsp := new(ItemsOfAddressRsp)
+	req := &ItemsOfAddressReq{ChainId: basedef.NEO_CROSSCHAIN_ID, Asset: neoAsset, Address: neoAddress, PageSize: 10}
+	assert.Equal(t, http.StatusOK, serveTest(t, &c.Controller, c.Items, req, "", nil, rsp))
+	assert.Equal(t, 2, rsp.TotalCount)
+	assert.Equal(t, []string{"1", "3"}, tokenIds(rsp))
+	counts := fake.executed("count(1)")
+	assert.Equal(t, 1, len(counts))
+	assert.Contains(t, counts[0].args, ownerHash)
+
+	// and from the ownership index when it has them all
+	fake.on("count(1)", []string{"count"}, []driver.Value{2})
+	fake.on("FROM `nft_ownerships`", []string{"chain_id", "asset", "owner", "token_id"},
+		[]driver.Value{basedef.NEO_CROSSCHAIN_ID, neoAsset, ownerHash, "1"},
+		[]driver.Value{basedef.NEO_CROSSCHAIN_ID, neoAsset, ownerHash, "3"},
+	).args = []interface{}{ownerHash}
+	c = new(ItemController)
+	rsp = new(ItemsOfAddressRsp)
+	assert.Equal(t, http.StatusOK, serveTest(t, &c.Controller, c.Items, req, "", nil, rsp))
+	assert.Equal(t, []string{"1", "3"}, tokenIds(rsp))
+	assert.Equal(t, 1, len(fake.executed("SELECT * FROM `nft_ownerships`")))
+
+	// a single token is checked against the owner hash, its uri is read only if the owner owns it
+	uris := contract.uris
+	c = new(ItemController)
+	rsp = new(ItemsOfAddressRsp)
+	single := &ItemsOfAddressReq{ChainId: basedef.NEO_CROSSCHAIN_ID, Asset: neoAsset, Address: neoAddress, TokenId: "3"}
+	assert.Equal(t, http.StatusOK, serveTest(t, &c.Controller, c.Items, single, "", nil, rsp))
+	assert.Equal(t, []string{"3"}, tokenIds(rsp))
+	assert.Equal(t, uris+1, contract.uris)
+
+	c = new(ItemController)
+	req = &ItemsOfAddressReq{ChainId: basedef.NEO_CROSSCHAIN_ID, Asset: neoAsset, Address: common.HexToAddress(ownerHash).Hex(), PageSize: 10}
+	assert.Equal(t, ErrCodeRequest, serveTest(t, &c.Controller, c.Items, req, "", nil, nil), "a hex address is not a neo address")
+}
@@ -2,21 +2,67 @@ package controllers
 
 import (
 	"fmt"
+	"poly-bridge/conf"
+	"strings"
 	"time"
 )
 
+const (
+	defaultHomeCacheTTL = 600
+	defaultItemCacheTTL = 3600
+)
+
+// CachePolicy is the freshness of the home page and item caches. A stale home page is served while it is
+// refreshed in the background if StaleWhileRevalidate is set, otherwise it is refreshed on the request.
+type CachePolicy struct {
+	HomeTTL              time.Duration
+	ItemTTL              time.Duration
+	StaleWhileRevalidate bool
+}
+
+var cachePolicy = NewCachePolicy(nil)
+
+func NewCachePolicy(cfg *conf.NFTCacheConfig) *CachePolicy {
+	policy := &CachePolicy{
+		HomeTTL: defaultHomeCacheTTL * time.Second,
+		ItemTTL: defaultItemCacheTTL * time.Second,
+	}
+	if cfg == nil {
+		return policy
+	}
+	if cfg.HomeTTL > 0 {
+		policy.HomeTTL = time.Duration(cfg.HomeTTL) * time.Second
+	}
+	if cfg.ItemTTL > 0 {
+		policy.ItemTTL = time.Duration(cfg.ItemTTL) * time.Second
+	}
+	policy.StaleWhileRevalidate = cfg.StaleWhileRevalidate
+	return policy
+}
+
 type CacheHomeRsp struct {
 	Rsp  *HomeRsp
+	Size int
+	Time time.Time
+}
+
+// Fresh reports whether the home page is in the ttl and not invalidated.
+func (c *CacheHomeRsp) Fresh() bool {
+	return c.Time.Add(cachePolicy.HomeTTL).After(time.Now())
+}
+
+type cacheItem struct {
+	Item *Item
 	Time time.Time
 }
 
 func SetHomePageCache(chainId uint64, rsp *CacheHomeRsp) {
-	key := formatHomePageCacheKey(chainId)
+	key := formatHomePageCacheKey(chainId, rsp.Size)
 	lruDB.Add(key, rsp)
 }
 
-func GetHomePageCache(chainId uint64) (*CacheHomeRsp, bool) {
-	key := formatHomePageCacheKey(chainId)
+func GetHomePageCache(chainId uint64, size int) (*CacheHomeRsp, bool) {
+	key := formatHomePageCacheKey(chainId, size)
 	data, ok := lruDB.Get(key)
 	if !ok {
 		return nil, false
@@ -28,9 +74,37 @@ func GetHomePageCache(chainId uint64) (*CacheHomeRsp, bool) {
 	return rsp, true
 }
 
+// homePageCaches returns the cached home pages of the chain in all the page sizes.
+func homePageCaches(chainId uint64) []*CacheHomeRsp {
+	prefix := formatHomePageCachePrefix(chainId)
+	caches := make([]*CacheHomeRsp, 0)
+	for _, key := range lruDB.Keys() {
+		if k, ok := key.(string); !ok || !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if data, ok := lruDB.Peek(key); ok {
+			if rsp, ok := data.(*CacheHomeRsp); ok {
+				caches = append(caches, rsp)
+			}
+		}
+	}
+	return caches
+}
+
+// InvalidateHomePageCache marks the home pages of the chain stale, they are still served in the stale while
+// revalidate mode until the refresh finishes.
+func InvalidateHomePageCache(chainId uint64) {
+	for _, cache := range homePageCaches(chainId) {
+		SetHomePageCache(chainId, &CacheHomeRsp{
+			Rsp:  cache.Rsp,
+			Size: cache.Size,
+		})
+	}
+}
+
 func SetItemCache(chainId uint64, asset string, tokenId string, item *Item) {
 	key := formatItemKey(chainId, asset, tokenId)
-	lruDB.Add(key, item)
+	lruDB.Add(key, &cacheItem{Item: item, Time: time.Now()})
 }
 
 func GetItemCache(chainId uint64, asset string, tokenId string) (*Item, bool) {
@@ -39,17 +113,30 @@ func GetItemCache(chainId uint64, asset string, tokenId string) (*Item, bool) {
 	if !ok {
 		return nil, false
 	}
-	item, ok := data.(*Item)
+	cache, ok := data.(*cacheItem)
 	if !ok {
 		return nil, false
 	}
-	return item, true
+	if cache.Time.Add(cachePolicy.ItemTTL).Before(time.Now()) {
+		lruDB.Remove(key)
+		return nil, false
+	}
+	return cache.Item, true
+}
+
+func InvalidateItemCache(chainId uint64, asset string, tokenId string) {
+	lruDB.Remove(formatItemKey(chainId, asset, tokenId))
+}
+
+func formatHomePageCacheKey(chainId uint64, size int) string {
+	return fmt.Sprintf("%s%d", formatHomePageCachePrefix(chainId), size)
 }
 
-func formatHomePageCacheKey(chainId uint64) string {
-	return fmt.Sprintf("homepage_%d", chainId)
+func formatHomePageCachePrefix(chainId uint64) string {
+	return fmt.Sprintf("homepage_%d_", chainId)
 }
 
+// formatItemKey formats the asset as the transfers do, so the items are invalidated by the transfers.
 func formatItemKey(chainId uint64, asset string, tokenId string) string {
-	return fmt.Sprintf("item_%d_%s_%s", chainId, asset, tokenId)
+	return fmt.Sprintf("item_%d_%s_%s", chainId, indexedAddress(asset), tokenId)
 }
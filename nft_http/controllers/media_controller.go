@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"poly-bridge/models"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/logs"
+)
+
+type MediaController struct {
+	beego.Controller
+}
+
+// Media serves the cached image of an NFT, or its thumbnail with thumb=1.
+func (c *MediaController) Media() {
+	if mediaStore == nil {
+		c.mediaError(http.StatusNotFound, "nft media is not enabled")
+		return
+	}
+	asset := c.GetString("asset")
+	tokenId := c.GetString("tokenid")
+	thumbnail, _ := c.GetBool("thumb", false)
+
+	profile := new(models.NFTProfile)
+	res := readDB().Select("image").
+		Where("token_basic_name = ? and nft_token_id = ?", asset, tokenId).
+		Find(profile)
+	if res.RowsAffected == 0 || profile.Image == "" {
+		c.mediaError(http.StatusNotFound, fmt.Sprintf("asset %s token %s does not have image", asset, tokenId))
+		return
+	}
+	data, contentType, err := mediaStore.Get(profile.Image, thumbnail)
+	if err != nil {
+		logs.Error("load nft media %s err: %v", profile.Image, err)
+		c.mediaError(http.StatusBadGateway, fmt.Sprintf("asset %s token %s image is not available", asset, tokenId))
+		return
+	}
+	c.Ctx.Output.Header("Content-Type", contentType)
+	c.Ctx.Output.Header("Cache-Control", "public, max-age=86400")
+	_ = c.Ctx.Output.Body(data)
+}
+
+func (c *MediaController) mediaError(status int, message string) {
+	c.Data["json"] = models.MakeErrorRsp(message)
+	c.Ctx.ResponseWriter.WriteHeader(status)
+	c.ServeJSON()
+}
+
+// mediaUrl is the url of the media route serving the image of the token.
+func mediaUrl(assetName string, tokenId string, thumbnail bool) string {
+	query := url.Values{}
+	query.Set("asset", assetName)
+	query.Set("tokenid", tokenId)
+	if thumbnail {
+		query.Set("thumb", "1")
+	}
+	return mediaBaseUrl + "?" + query.Encode()
+}
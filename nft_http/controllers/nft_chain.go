@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NFTChain reads the NFT items and balances of a chain. The addresses are the 20 bytes hashes of the chain as
+// they are stored in the token and transfer tables, so they fit in a common.Address on every chain.
+type NFTChain interface {
+	NFTBalance(asset, owner common.Address) (*big.Int, error)
+	GetNFTUrl(asset common.Address, tokenId *big.Int) (string, error)
+	GetAndCheckTokenUrl(wrapper, asset, owner common.Address, tokenId *big.Int) (string, error)
+	GetTokensByIndex(wrapper, asset, owner common.Address, start, length int) (map[string]string, error)
+	GetTokensById(wrapper, asset common.Address, tokenIdList []*big.Int) (map[string]string, error)
+	GetUnCrossChainNFTsByIndex(wrapper, asset common.Address, start, length int) (map[string]string, error)
+	ERC1155Balance(asset, owner common.Address, tokenId *big.Int) (*big.Int, error)
+	GetERC1155Url(asset common.Address, tokenId *big.Int) (string, error)
+}
+
+// nftContractReader reads the NFT contracts of a chain without the NFT wrapper queries, the asset and the owner
+// are the hex hashes of the token and transfer tables.
+type nftContractReader interface {
+	NFTBalance(asset, owner string) (*big.Int, error)
+	NFTOwner(asset string, tokenId *big.Int) (string, error)
+	NFTTokenUri(asset string, tokenId *big.Int) (string, error)
+	NFTTokenOfOwnerByIndex(asset, owner string, index int) (*big.Int, error)
+}
+
+// contractNFTChain is the NFTChain of NEO and Ontology, whose items are read from the NFT contracts token by
+// token as the chains do not have the NFT wrapper queries.
+type contractNFTChain struct {
+	chainId uint64
+	reader  nftContractReader
+}
+
+func newContractNFTChain(chainId uint64, reader nftContractReader) *contractNFTChain {
+	return &contractNFTChain{chainId: chainId, reader: reader}
+}
+
+func (c *contractNFTChain) NFTBalance(asset, owner common.Address) (*big.Int, error) {
+	return c.reader.NFTBalance(hashOf(asset), hashOf(owner))
+}
+
+func (c *contractNFTChain) GetNFTUrl(asset common.Address, tokenId *big.Int) (string, error) {
+	return c.reader.NFTTokenUri(hashOf(asset), tokenId)
+}
+
+// GetAndCheckTokenUrl returns an empty url if owner does not own the token, as the NFT wrapper does.
+func (c *contractNFTChain) GetAndCheckTokenUrl(wrapper, asset, owner common.Address, tokenId *big.Int) (string, error) {
+	tokenOwner, err := c.reader.NFTOwner(hashOf(asset), tokenId)
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(tokenOwner, hashOf(owner)) {
+		return "", nil
+	}
+	return c.reader.NFTTokenUri(hashOf(asset), tokenId)
+}
+
+func (c *contractNFTChain) GetTokensByIndex(wrapper, asset, owner common.Address, start, length int) (map[string]string, error) {
+	res := make(map[string]string)
+	for index := start; index < start+length; index++ {
+		tokenId, err := c.reader.NFTTokenOfOwnerByIndex(hashOf(asset), hashOf(owner), index)
+		if err != nil {
+			return nil, err
+		}
+		url, err := c.reader.NFTTokenUri(hashOf(asset), tokenId)
+		if err != nil {
+			return nil, err
+		}
+		res[tokenId.String()] = url
+	}
+	return res, nil
+}
+
+// GetTokensById skips the tokens whose url can not be read, as the NFT wrapper skips the tokens not minted.
+func (c *contractNFTChain) GetTokensById(wrapper, asset common.Address, tokenIdList []*big.Int) (map[string]string, error) {
+	if len(tokenIdList) == 0 {
+		return nil, fmt.Errorf("empty id list")
+	}
+	res := make(map[string]string)
+	for _, tokenId := range tokenIdList {
+		if url, err := c.reader.NFTTokenUri(hashOf(asset), tokenId); err == nil {
+			res[tokenId.String()] = url
+		}
+	}
+	return res, nil
+}
+
+func (c *contractNFTChain) GetUnCrossChainNFTsByIndex(wrapper, asset common.Address, start, length int) (map[string]string, error) {
+	return nil, fmt.Errorf("chain %d can not list the tokens of %s", c.chainId, hashOf(asset))
+}
+
+func (c *contractNFTChain) ERC1155Balance(asset, owner common.Address, tokenId *big.Int) (*big.Int, error) {
+	return nil, fmt.Errorf("chain %d does not have ERC1155 tokens", c.chainId)
+}
+
+func (c *contractNFTChain) GetERC1155Url(asset common.Address, tokenId *big.Int) (string, error) {
+	return "", fmt.Errorf("chain %d does not have ERC1155 tokens", c.chainId)
+}
+
+func hashOf(addr common.Address) string {
+	return hex.EncodeToString(addr.Bytes())
+}
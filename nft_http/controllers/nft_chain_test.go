@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testNFTContract struct {
+	owners map[string]string
+	uris   int
+}
+
+func (r *testNFTContract) NFTBalance(asset, owner string) (*big.Int, error) {
+	balance := int64(0)
+	for _, v := range r.owners {
+		if v == owner {
+			balance++
+		}
+	}
+	return big.NewInt(balance), nil
+}
+
+func (r *testNFTContract) NFTOwner(asset string, tokenId *big.Int) (string, error) {
+	owner, ok := r.owners[tokenId.String()]
+	if !ok {
+		return "", fmt.Errorf("token %s not exist", tokenId.String())
+	}
+	return owner, nil
+}
+
+func (r *testNFTContract) NFTTokenUri(asset string, tokenId *big.Int) (string, error) {
+	r.uris++
+	if _, ok := r.owners[tokenId.String()]; !ok {
+		return "", fmt.Errorf("token %s not exist", tokenId.String())
+	}
+	return "https://nft.example.com/" + asset + "/" + tokenId.String(), nil
+}
+
+func (r *testNFTContract) NFTTokenOfOwnerByIndex(asset, owner string, index int) (*big.Int, error) {
+	for i := 1; i <= len(r.owners); i++ {
+		if r.owners[fmt.Sprint(i)] != owner {
+			continue
+		}
+		if index == 0 {
+			return big.NewInt(int64(i)), nil
+		}
+		index--
+	}
+	return nil, fmt.Errorf("index out of range")
+}
+
+func TestContractNFTChain(t *testing.T) {
+	asset := common.HexToAddress("0x2f8a0b9f2bd3d5c1c43bd4cf2a7c0e7ba32fd1c8")
+	alice := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bob := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	chain := newContractNFTChain(4, &testNFTContract{owners: map[string]string{
+		"1": hashOf(alice),
+		"2": hashOf(bob),
+		"3": hashOf(alice),
+	}})
+	url := func(tokenId int64) string {
+		return fmt.Sprintf("https://nft.example.com/%s/%d", hashOf(asset), tokenId)
+	}
+
+	balance, err := chain.NFTBalance(asset, alice)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), balance.Int64())
+
+	tokens, err := chain.GetTokensByIndex(emptyAddr, asset, alice, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"1": url(1), "3": url(3)}, tokens)
+
+	tokens, err = chain.GetTokensById(emptyAddr, asset, []*big.Int{big.NewInt(2), big.NewInt(4)})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"2": url(2)}, tokens)
+
+	checked, err := chain.GetAndCheckTokenUrl(emptyAddr, asset, bob, big.NewInt(2))
+	assert.NoError(t, err)
+	assert.Equal(t, url(2), checked)
+	checked, err = chain.GetAndCheckTokenUrl(emptyAddr, asset, alice, big.NewInt(2))
+	assert.NoError(t, err)
+	assert.Equal(t, "", checked)
+
+	_, err = chain.GetERC1155Url(asset, big.NewInt(1))
+	assert.Error(t, err)
+}
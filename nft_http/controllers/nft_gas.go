@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"math/big"
+	"poly-bridge/chainsdk"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultEstimateTTL    = 600
+	defaultVerifyGasLimit = 300000
+)
+
+// estimateReceiver receives the tokens of the simulated unlocks, it is an account without code.
+var estimateReceiver = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+type gasEstimate struct {
+	gas  uint64
+	err  error
+	time time.Time
+}
+
+// NFTGasEstimator estimates the gas of unlocking the tokens of an NFT collection by simulating the unlock of a
+// token locked in the lock proxy of the destination chain, the estimates and the failures are cached for the ttl.
+type NFTGasEstimator struct {
+	ttl            time.Duration
+	verifyGasLimit uint64
+	lock           sync.Mutex
+	estimates      map[string]*gasEstimate
+	simulate       func(chainId uint64, asset string, fromChainId uint64) (uint64, error)
+}
+
+func NewNFTGasEstimator(cfg *conf.NFTFeeConfig) *NFTGasEstimator {
+	estimator := &NFTGasEstimator{
+		ttl:            defaultEstimateTTL * time.Second,
+		verifyGasLimit: defaultVerifyGasLimit,
+		estimates:      make(map[string]*gasEstimate),
+	}
+	estimator.simulate = estimator.simulateUnlock
+	if cfg == nil {
+		return estimator
+	}
+	if cfg.EstimateTTL > 0 {
+		estimator.ttl = time.Duration(cfg.EstimateTTL) * time.Second
+	}
+	if cfg.VerifyGasLimit > 0 {
+		estimator.verifyGasLimit = uint64(cfg.VerifyGasLimit)
+	}
+	return estimator
+}
+
+// Estimate returns the gas of the cross chain tx from the source chain unlocking the asset on the chain.
+func (e *NFTGasEstimator) Estimate(chainId uint64, asset string, fromChainId uint64) (uint64, error) {
+	key := fmt.Sprintf("%d:%s", fromChainId, assetKey(chainId, asset))
+	e.lock.Lock()
+	estimate, ok := e.estimates[key]
+	e.lock.Unlock()
+	if ok && estimate.time.Add(e.ttl).After(time.Now()) {
+		return estimate.gas, estimate.err
+	}
+
+	gas, err := e.simulate(chainId, asset, fromChainId)
+	if err == nil {
+		gas += e.verifyGasLimit
+	}
+	e.lock.Lock()
+	e.estimates[key] = &gasEstimate{gas: gas, err: err, time: time.Now()}
+	e.lock.Unlock()
+	return gas, err
+}
+
+// simulateUnlock simulates unlocking the token of the asset locked in the proxy last, with its token uri as the
+// unlock args carry the uri of the token.
+func (e *NFTGasEstimator) simulateUnlock(chainId uint64, asset string, fromChainId uint64) (uint64, error) {
+	cfg, ok := chainConfig[chainId]
+	if !ok || cfg.NFTProxyContract == "" {
+		return 0, fmt.Errorf("chain %d does not have nft proxy", chainId)
+	}
+	chain, _, err := selectNodeAndWrapper(chainId)
+	if err != nil {
+		return 0, err
+	}
+	sdk, ok := chain.(*chainsdk.EthereumSdkPro)
+	if !ok {
+		return 0, fmt.Errorf("chain %d can not estimate the nft unlock gas", chainId)
+	}
+	locked := new(models.NFTOwnership)
+	res := readDB().Where("chain_id = ? and asset = ? and owner = ?", chainId, indexedAddress(asset), indexedAddress(cfg.NFTProxyContract)).
+		Order("id desc").
+		Limit(1).
+		Find(locked)
+	if res.RowsAffected == 0 {
+		return 0, fmt.Errorf("no token of %s is locked in the proxy of chain %d", asset, chainId)
+	}
+	tokenId, ok := new(big.Int).SetString(locked.TokenId, 10)
+	if !ok {
+		return 0, fmt.Errorf("token id %s is invalid", locked.TokenId)
+	}
+	tokenUri, err := sdk.GetNFTUrl(common.HexToAddress(asset), tokenId)
+	if err != nil {
+		return 0, err
+	}
+	proxy := common.HexToAddress(cfg.NFTProxyContract)
+	return sdk.EstimateNFTUnlockGas(proxy, common.HexToAddress(asset), estimateReceiver, tokenId, tokenUri, fromChainId)
+}
+
+// nftChainFee selects the fee of unlocking the NFT asset on the chain of chainFee. Unless the asset has its
+// own fee, the fee of the standard is scaled from the configured gas limit to the estimated gas of the asset.
+func nftChainFee(chainFee *models.ChainFee, standard uint8, srcChainId uint64, dstAsset string) *models.ChainFee {
+	fee := chainFee.Select(standard, dstAsset)
+	if standard != models.TokenTypeErc721 || dstAsset == "" || chainFee.HasAssetFee(dstAsset) {
+		return fee
+	}
+	baseGasLimit := nftGasLimits[chainFee.ChainId]
+	if baseGasLimit <= 0 {
+		return fee
+	}
+	gas, err := gasEstimator.Estimate(chainFee.ChainId, dstAsset, srcChainId)
+	if err != nil {
+		logs.Debug("estimate unlock gas of %s on chain %d err: %v", dstAsset, chainFee.ChainId, err)
+		return fee
+	}
+	return fee.Scale(int64(gas), baseGasLimit)
+}
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"poly-bridge/chainsdk"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSimulation struct {
+	calls int
+	gas   uint64
+	err   error
+}
+
+func (s *testSimulation) simulate(chainId uint64, asset string, fromChainId uint64) (uint64, error) {
+	s.calls++
+	return s.gas, s.err
+}
+
+func TestNFTGasEstimator(t *testing.T) {
+	estimator := NewNFTGasEstimator(&conf.NFTFeeConfig{EstimateTTL: 1, VerifyGasLimit: 1000})
+	assert.Equal(t, time.Second, estimator.ttl)
+	simulation := &testSimulation{gas: 50000}
+	estimator.simulate = simulation.simulate
+
+	gas, err := estimator.Estimate(2, testSealEth, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(51000), gas, "the verification gas is added to the simulated unlock")
+	assert.Equal(t, 1, simulation.calls)
+
+	// the estimates are cached for a destination asset and a source chain
+	simulation.gas = 80000
+	gas, _ = estimator.Estimate(2, testSealEth, 6)
+	assert.Equal(t, uint64(51000), gas)
+	gas, _ = estimator.Estimate(2, testSealEth[2:], 6)
+	assert.Equal(t, uint64(51000), gas)
+	assert.Equal(t, 1, simulation.calls)
+	gas, _ = estimator.Estimate(2, testSealEth, 7)
+	assert.Equal(t, uint64(81000), gas)
+	assert.Equal(t, 2, simulation.calls)
+
+	// the failures are cached as well
+	simulation.err = fmt.Errorf("execution reverted")
+	_, err = estimator.Estimate(2, testKittyEth, 6)
+	assert.Error(t, err)
+	_, err = estimator.Estimate(2, testKittyEth, 6)
+	assert.Error(t, err)
+	assert.Equal(t, 3, simulation.calls)
+
+	// and simulated again after the ttl
+	estimator.lock.Lock()
+	for _, estimate := range estimator.estimates {
+		estimate.time = estimate.time.Add(-estimator.ttl)
+	}
+	estimator.lock.Unlock()
+	simulation.err = nil
+	gas, err = estimator.Estimate(2, testKittyEth, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(81000), gas)
+	gas, _ = estimator.Estimate(2, testSealEth, 6)
+	assert.Equal(t, uint64(81000), gas)
+	assert.Equal(t, 5, simulation.calls)
+
+	assert.Equal(t, defaultEstimateTTL*time.Second, NewNFTGasEstimator(nil).ttl)
+	assert.Equal(t, uint64(defaultVerifyGasLimit), NewNFTGasEstimator(nil).verifyGasLimit)
+}
+
+func TestNFTChainFee(t *testing.T) {
+	previousEstimator, previousLimit := gasEstimator, nftGasLimits[2]
+	defer func() {
+		gasEstimator = previousEstimator
+		nftGasLimits[2] = previousLimit
+	}()
+	gasEstimator = NewNFTGasEstimator(&conf.NFTFeeConfig{VerifyGasLimit: 50000})
+	simulation := &testSimulation{gas: 150000}
+	gasEstimator.simulate = simulation.simulate
+	nftGasLimits[2] = 100000
+
+	chainFee := &models.ChainFee{
+		ChainId:  2,
+		MinFee:   models.NewBigIntFromInt(100),
+		MaxFee:   models.NewBigIntFromInt(200),
+		ProxyFee: models.NewBigIntFromInt(300),
+		AssetFees: []*models.AssetFee{
+			{ChainId: 2, Hash: indexedAddress(testKittyEth), MinFee: models.NewBigIntFromInt(7), MaxFee: models.NewBigIntFromInt(7), ProxyFee: models.NewBigIntFromInt(7), Ind: 1},
+		},
+	}
+	fee := nftChainFee(chainFee, models.TokenTypeErc721, 6, testSealEth)
+	assert.Equal(t, int64(200), fee.MinFee.Int64(), "the fee is scaled to the estimated gas")
+	assert.Equal(t, int64(600), fee.ProxyFee.Int64())
+
+	for _, v := range []struct {
+		standard uint8
+		asset    string
+		minFee   int64
+	}{
+		{models.TokenTypeErc721, "", 100},
+		{models.TokenTypeErc1155, testSealEth, 100},
+		{models.TokenTypeErc721, testKittyEth[2:], 7},
+	} {
+		fee := nftChainFee(chainFee, v.standard, 6, v.asset)
+		assert.Equal(t, v.minFee, fee.MinFee.Int64(), v.asset)
+	}
+	assert.Equal(t, 1, simulation.calls)
+
+	simulation.err = fmt.Errorf("execution reverted")
+	fee = nftChainFee(chainFee, models.TokenTypeErc721, 7, testSealEth)
+	assert.Equal(t, int64(100), fee.MinFee.Int64(), "the fee is not scaled without an estimate")
+}
+
+func TestEstimateNFTUnlockGasWithoutFailover(t *testing.T) {
+	unbound := "0x3333333333333333333333333333333333333333"
+	var blockNumberCalls int32
+	// the proxy unbound is not bound to any chain, the calls of other contracts revert
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage
+			Method string
+			Params []json.RawMessage
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		result := "0x10"
+		switch req.Method {
+		case "eth_blockNumber":
+			atomic.AddInt32(&blockNumberCalls, 1)
+		case "eth_call":
+			var msg struct{ To string }
+			_ = json.Unmarshal(req.Params[0], &msg)
+			if strings.ToLower(msg.To) != unbound {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"execution reverted"}}`, req.Id)
+				return
+			}
+			// the abi encoded empty bytes
+			result = fmt.Sprintf("0x%064x%064x", 32, 0)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"%s"}`, req.Id, result)
+	}))
+	defer node.Close()
+	sdk := chainsdk.NewEthereumSdkPro([]string{node.URL}, 3600, 2)
+
+	for _, proxy := range []string{unbound, "0x4444444444444444444444444444444444444444"} {
+		_, err := sdk.EstimateNFTUnlockGas(common.HexToAddress(proxy), common.HexToAddress(testSealEth), estimateReceiver, big.NewInt(1), "", 6)
+		assert.Error(t, err, proxy)
+		assert.NotNil(t, sdk.GetLatest(), proxy)
+	}
+	_, err := sdk.EstimateNFTUnlockGas(common.HexToAddress(unbound), common.HexToAddress(testSealEth), estimateReceiver, big.NewInt(1), "", 6)
+	assert.Contains(t, err.Error(), "is not bound")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&blockNumberCalls))
+}
@@ -28,13 +28,24 @@ type AssetItems struct {
 }
 
 type Item struct {
-	AssetName string
-	TokenId   string
-	Name      string
-	Url       string
-	Image     string
-	Desc      string
-	Meta      string
+	AssetName    string
+	TokenId      string
+	Name         string
+	Url          string
+	Image        string
+	Thumbnail    string
+	AnimationUrl string
+	ExternalUrl  string
+	Desc         string
+	Meta         string
+	Quantity     string
+	Attributes   []*ItemAttribute
+}
+
+type ItemAttribute struct {
+	TraitType   string
+	Value       string
+	DisplayType string
 }
 
 func (i *Item) instance(assetName string, tokenId string, profile *models.NFTProfile) *Item {
@@ -46,7 +57,22 @@ func (i *Item) instance(assetName string, tokenId string, profile *models.NFTPro
 	i.Name = profile.Name
 	i.Url = profile.Url
 	i.Image = profile.Image
+	i.Thumbnail = profile.Image
+	if profile.Image != "" && mediaBaseUrl != "" {
+		i.Image = mediaUrl(assetName, tokenId, false)
+		i.Thumbnail = mediaUrl(assetName, tokenId, true)
+	}
+	i.AnimationUrl = profile.AnimationUrl
+	i.ExternalUrl = profile.ExternalUrl
 	i.Desc = profile.Description
+	i.Attributes = make([]*ItemAttribute, 0, len(profile.Attributes))
+	for _, attribute := range profile.Attributes {
+		i.Attributes = append(i.Attributes, &ItemAttribute{
+			TraitType:   attribute.TraitType,
+			Value:       attribute.Value,
+			DisplayType: attribute.DisplayType,
+		})
+	}
 
 	// todo(fuk): useless field
 	//i.Meta = profile.Text
@@ -130,6 +156,14 @@ type AssetReq struct {
 	Hash    string
 }
 
+type GetFeeReq struct {
+	SrcChainId uint64
+	Hash       string
+	DstChainId uint64
+	Asset      string // optional, nft asset on the source chain
+	User       string // optional, sender of the transfer
+}
+
 //
 //type NFTAssetRsp struct {
 //	Hash       string
@@ -242,6 +276,7 @@ type TransactionBriefRelation struct {
 	models.WrapperTransaction
 	SrcAsset string
 	TokenId  string
+	Quantity string
 }
 
 type TransactionBriefRsp struct {
@@ -252,6 +287,7 @@ type TransactionBriefRsp struct {
 	DstChainId  uint64
 	Time        uint64
 	TokenId     string
+	Quantity    string
 	AssetName   string
 	From        string
 	To          string
@@ -266,6 +302,7 @@ func (s *TransactionBriefRsp) instance(assetName string, r *TransactionBriefRela
 	s.DstChainId = r.DstChainId
 	s.Time = r.Time
 	s.TokenId = r.TokenId
+	s.Quantity = nftQuantity(r.Quantity)
 	s.From = r.User
 	s.To = r.DstUser
 	return s
@@ -366,6 +403,7 @@ func (s *TransactionDetailRsp) instance(r *TransactionDetailRelation) *Transacti
 			s.SrcTransaction.AssetHash = token.Hash
 			s.Transaction.AssetName = token.TokenBasicName
 			s.Transaction.TokenId = r.SrcTransaction.SrcTransfer.Amount.String()
+			s.Transaction.Quantity = quantityOf(r.SrcTransaction.SrcTransfer.Quantity)
 		}
 
 		s.SrcTransaction.Hash = r.SrcTransaction.Hash
@@ -374,7 +412,7 @@ func (s *TransactionDetailRsp) instance(r *TransactionDetailRelation) *Transacti
 		s.SrcTransaction.Status = r.SrcTransaction.State
 		s.SrcTransaction.ChainId = r.SrcTransaction.ChainId
 
-		feeToken := feeTokens[s.SrcTransaction.ChainId]
+		feeToken := nftFeeToken(s.SrcTransaction.ChainId)
 		precision := decimal.NewFromInt(basedef.Int64FromFigure(int(feeToken.TokenBasic.Precision)))
 		{
 			bbb := decimal.NewFromBigInt(&r.SrcTransaction.Fee.Int, 0)
@@ -401,7 +439,7 @@ func (s *TransactionDetailRsp) instance(r *TransactionDetailRelation) *Transacti
 		s.DstTransaction.Status = r.DstTransaction.State
 		s.DstTransaction.ChainId = r.DstTransaction.ChainId
 
-		feeToken := feeTokens[s.DstTransaction.ChainId]
+		feeToken := nftFeeToken(s.DstTransaction.ChainId)
 		precision := decimal.NewFromInt(basedef.Int64FromFigure(int(feeToken.TokenBasic.Precision)))
 		{
 			bbb := decimal.NewFromBigInt(&r.SrcTransaction.Fee.Int, 0)
@@ -438,6 +476,7 @@ type TransactionRsp struct {
 	DstChainId       uint64
 	DstUser          string
 	TokenId          string
+	Quantity         string
 	ServerId         uint64
 	FeeToken         *models.TokenRsp
 	FeeAmount        string
@@ -478,6 +517,7 @@ func (s *TransactionRsp) instance(
 	s.ServerId = transaction.WrapperTransaction.ServerId
 	s.FeeAmount = transaction.WrapperTransaction.FeeAmount.String()
 	s.TokenId = transaction.SrcTransaction.SrcTransfer.Amount.String()
+	s.Quantity = quantityOf(transaction.SrcTransaction.SrcTransfer.Quantity)
 	s.DstUser = transaction.SrcTransaction.SrcTransfer.DstUser
 	s.State = transaction.WrapperTransaction.Status
 
@@ -638,3 +678,48 @@ func property2Disable(property int64) int64 {
 		return 1
 	}
 }
+
+// nftQuantity is the quantity of an NFT transfer, the ERC721 transfers and the transfers indexed before
+// the quantity column have no quantity, which is 1.
+func nftQuantity(quantity string) string {
+	if quantity == "" || quantity == "null" || quantity == "0" {
+		return "1"
+	}
+	return quantity
+}
+
+func quantityOf(quantity *models.BigInt) string {
+	if quantity == nil {
+		return "1"
+	}
+	return nftQuantity(quantity.String())
+}
+
+// withQuantity copies the item which may be cached and shared by the owners.
+func (i *Item) withQuantity(quantity string) *Item {
+	item := *i
+	item.Quantity = quantity
+	return &item
+}
+
+type CollectionsRsp struct {
+	UpdateTime  int64
+	Collections []*CollectionStats
+}
+
+func (s *CollectionsRsp) instance(stats []*CollectionStats, updateTime int64) *CollectionsRsp {
+	s.UpdateTime = updateTime
+	s.Collections = stats
+	return s
+}
+
+type CollectionActivityRsp struct {
+	Stats *CollectionStats
+	*TransactionBriefsRsp
+}
+
+func (s *CollectionActivityRsp) instance(stats *CollectionStats, transactions *TransactionBriefsRsp) *CollectionActivityRsp {
+	s.Stats = stats
+	s.TransactionBriefsRsp = transactions
+	return s
+}
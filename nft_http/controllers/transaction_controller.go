@@ -37,14 +37,14 @@ func (c *TransactionController) TransactionsOfAddress() {
 
 	wrapTxs := make([]*models.WrapperTransaction, 0)
 	if req.State == -1 {
-		db.Model(&models.WrapperTransaction{}).
-			Where("standard = ? and (user in ? or dst_user in ? )", models.TokenTypeErc721, req.Addresses, req.Addresses).
+		readDB().Model(&models.WrapperTransaction{}).
+			Where("standard in ? and (user in ? or dst_user in ? )", models.NFTStandards, req.Addresses, req.Addresses).
 			Limit(req.PageSize).Offset(req.PageSize * req.PageNo).
 			Order("time desc").
 			Find(&wrapTxs)
 	} else {
-		db.Model(&models.WrapperTransaction{}).
-			Where("standard = ? and status = ? and (user in ? or dst_user in ?)", models.TokenTypeErc721, req.State, req.Addresses, req.Addresses).
+		readDB().Model(&models.WrapperTransaction{}).
+			Where("standard in ? and status = ? and (user in ? or dst_user in ?)", models.NFTStandards, req.State, req.Addresses, req.Addresses).
 			Limit(req.PageSize).Offset(req.PageSize * req.PageNo).
 			Order("time desc").
 			Find(&wrapTxs)
@@ -54,14 +54,14 @@ func (c *TransactionController) TransactionsOfAddress() {
 
 	// get transaction number
 	var transactionNum int64
-	db.Model(&models.SrcTransfer{}).
+	readDB().Model(&models.SrcTransfer{}).
 		Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-		Where("src_transfers.standard = ? and (`from` in ? or src_transfers.dst_user in ?)", models.TokenTypeErc721, req.Addresses, req.Addresses).
+		Where("src_transfers.standard in ? and (`from` in ? or src_transfers.dst_user in ?)", models.NFTStandards, req.Addresses, req.Addresses).
 		Count(&transactionNum)
 
 	// get chains
 	chains := make([]*models.Chain, 0)
-	db.Model(&models.Chain{}).Find(&chains)
+	readDB().Model(&models.Chain{}).Find(&chains)
 	chainsMap := make(map[uint64]*models.Chain)
 	for _, chain := range chains {
 		chainsMap[*chain.ChainId] = chain
@@ -81,8 +81,8 @@ func (c *TransactionController) TransactionOfHash() {
 	}
 
 	wrapTx := new(models.WrapperTransaction)
-	res := db.Model(&models.WrapperTransaction{}).
-		Where("standard = ? and hash = ?", models.TokenTypeErc721, req.Hash).
+	res := readDB().Model(&models.WrapperTransaction{}).
+		Where("standard in ? and hash = ?", models.NFTStandards, req.Hash).
 		Find(&wrapTx)
 
 	if res.RowsAffected == 0 {
@@ -98,7 +98,7 @@ func (c *TransactionController) TransactionOfHash() {
 	}
 
 	chains := make([]*models.Chain, 0)
-	db.Model(&models.Chain{}).Find(&chains)
+	readDB().Model(&models.Chain{}).Find(&chains)
 	chainsMap := make(map[uint64]*models.Chain)
 	for _, chain := range chains {
 		chainsMap[*chain.ChainId] = chain
@@ -118,15 +118,15 @@ func (c *TransactionController) TransactionsOfState() {
 	}
 
 	transactions := make([]*models.WrapperTransaction, 0)
-	db.Where("standard = ? and status = ?", models.TokenTypeErc721, req.State).
+	readDB().Where("standard in ? and status = ?", models.NFTStandards, req.State).
 		Limit(req.PageSize).
 		Offset(req.PageSize * req.PageNo).
 		Order("time asc").
 		Find(&transactions)
 
 	var transactionNum int64
-	db.Model(&models.WrapperTransaction{}).
-		Where("standard = ? and status = ?", models.TokenTypeErc721, req.State).
+	readDB().Model(&models.WrapperTransaction{}).
+		Where("standard in ? and status = ?", models.NFTStandards, req.State).
 		Count(&transactionNum)
 
 	totalPage := (int(transactionNum) + req.PageSize - 1) / req.PageSize
@@ -152,7 +152,7 @@ func findSrcPolyDstRelation(wrapTxs []*models.WrapperTransaction) []*SrcPolyDstR
 
 	srcTxs := make([]*models.SrcTransaction, 0)
 	srcTxsMap := make(map[string]*models.SrcTransaction)
-	db.Model(&models.SrcTransaction{}).
+	readDB().Model(&models.SrcTransaction{}).
 		Where("src_transactions.hash in ?", srcTxHashs).
 		Joins("left join src_transfers on src_transfers.tx_hash = src_transactions.hash").
 		Preload("SrcTransfer").
@@ -164,7 +164,7 @@ func findSrcPolyDstRelation(wrapTxs []*models.WrapperTransaction) []*SrcPolyDstR
 	polyTxs := make([]*models.PolyTransaction, 0)
 	polyMap := make(map[string]*models.PolyTransaction)
 	polyDstTxHashs := make([]string, 0)
-	db.Model(&models.PolyTransaction{}).
+	readDB().Model(&models.PolyTransaction{}).
 		Where("src_hash in ?", srcTxHashs).
 		Find(&polyTxs)
 	for _, v := range polyTxs {
@@ -174,7 +174,7 @@ func findSrcPolyDstRelation(wrapTxs []*models.WrapperTransaction) []*SrcPolyDstR
 
 	dstTxs := make([]*models.DstTransaction, 0)
 	dstTxsMap := make(map[string]*models.DstTransaction)
-	db.Model(&models.DstTransaction{}).
+	readDB().Model(&models.DstTransaction{}).
 		Where("dst_transactions.poly_hash in ?", polyDstTxHashs).
 		Joins("left join dst_transfers on dst_transfers.tx_hash = dst_transactions.hash").
 		Preload("DstTransfer").
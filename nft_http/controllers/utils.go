@@ -22,66 +22,72 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"poly-bridge/basedef"
 	"poly-bridge/chainsdk"
 	"poly-bridge/conf"
+	"poly-bridge/dbrouter"
 	"poly-bridge/models"
+	"poly-bridge/nft_http/media"
 	"poly-bridge/nft_http/meta"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/astaxie/beego"
 	"github.com/astaxie/beego/logs"
 	"github.com/ethereum/go-ethereum/common"
 	lru "github.com/hashicorp/golang-lru"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 var (
+	dbRouter     *dbrouter.Router
 	db           *gorm.DB
 	chainConfig  = make(map[uint64]*conf.ChainListenConfig)
 	txCounter    *TransactionCounter
-	sdks         = make(map[uint64]*chainsdk.EthereumSdkPro)
+	sdks         = make(map[uint64]NFTChain)
+	sdkLock      sync.Mutex
 	assets       = make([]*models.Token, 0)
 	wrapperAddrs = make(map[uint64]common.Address)
 	fetcher      *meta.StoreFetcher
+	indexer      *meta.Indexer
 	feeTokens    = make(map[uint64]*models.Token)
 	lruDB        *lru.ARCCache
+	mediaStore   *media.Store
+	mediaBaseUrl string
+
+	collectionCounter *CollectionCounter
+	cacheInvalidator  *CacheInvalidator
+	assetWatcher      *AssetWatcher
+	gasEstimator      *NFTGasEstimator
+	// gas limits of the NFT fees of the chains, which the estimated gas scales the fees from
+	nftGasLimits = make(map[uint64]int64)
 )
 
-func NewDB(cfg *conf.DBConfig) *gorm.DB {
-	user := cfg.User
-	password := cfg.Password
-	url := cfg.URL
-	scheme := cfg.Scheme
-	Logger := logger.Default
-	if cfg.Debug {
-		Logger = Logger.LogMode(logger.Info)
-	}
-	format := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8", user, password, url, scheme)
-	db, err := gorm.Open(mysql.Open(format), &gorm.Config{Logger: Logger})
+func NewDB(cfg *conf.DBConfig) *dbrouter.Router {
+	router, err := dbrouter.Open(cfg)
 	if err != nil {
 		panic(err)
 	}
 
-	db.Where("standard = ? and property=?", models.TokenTypeErc721, 1).
-		Preload("TokenBasic").
-		Find(&assets)
-	for _, v := range assets {
+	loadedAssets, loadedFeeTokens, err := loadAssets(router.Primary())
+	if err != nil {
+		panic(err)
+	}
+	for _, v := range loadedAssets {
 		logs.Info("load asset %s, chainid %d, hash %s", v.TokenBasicName, v.ChainId, v.Hash)
 	}
-
-	feeTokenList := make([]*models.Token, 0)
-	db.Where("hash=?", nativeHash).
-		Preload("TokenBasic").
-		Find(&feeTokenList)
-	for _, v := range feeTokenList {
-		feeTokens[v.ChainId] = v
+	for _, v := range loadedFeeTokens {
 		logs.Info("load chainid %d feeToken %s", v.ChainId, v.TokenBasicName)
 	}
-	return db
+	setAssets(loadedAssets, loadedFeeTokens)
+	return router
+}
+
+// readDB returns the database for the read only queries, which may be a replica of db.
+func readDB() *gorm.DB {
+	return dbRouter.Reader()
 }
 
 func Initialize(c *conf.Config) {
@@ -89,7 +95,8 @@ func Initialize(c *conf.Config) {
 		chainConfig[v.ChainId] = v
 	}
 
-	db = NewDB(c.DBConfig)
+	dbRouter = NewDB(c.DBConfig)
+	db = dbRouter.Primary()
 
 	arcLRU, err := lru.NewARC(5000)
 	if err != nil {
@@ -98,17 +105,46 @@ func Initialize(c *conf.Config) {
 	lruDB = arcLRU
 
 	fetcher = meta.NewStoreFetcher(db)
-	for _, asset := range assets {
-		if asset.TokenBasic == nil {
-			continue
+	for _, asset := range nftAssets() {
+		registerFetcher(asset)
+	}
+
+	if c.NFTIndexerConfig != nil {
+		// create the sdks before the indexer reads the chains concurrently with the requests
+		for _, asset := range nftAssets() {
+			if _, _, err := selectNodeAndWrapper(asset.ChainId); err != nil {
+				logs.Error("nft indexer select node of chain %d err: %v", asset.ChainId, err)
+			}
 		}
-		fetcherTyp := meta.FetcherType(asset.TokenBasic.MetaFetcherType)
-		baseUri := asset.TokenBasic.Meta
-		assetName := asset.TokenBasic.Name
-		fetcher.Register(fetcherTyp, assetName, baseUri)
+		indexer = meta.NewIndexer(c.NFTIndexerConfig, fetcher, db, nftAssets(), readTokenURI)
+		indexer.Start()
 	}
 
+	for _, v := range c.FeeListenConfig {
+		nftGasLimits[v.ChainId] = v.GetStandardGasLimit(models.TokenTypeErc721)
+	}
+	gasEstimator = NewNFTGasEstimator(c.NFTFeeConfig)
+
+	if c.NFTMediaConfig != nil {
+		cfg := c.NFTMediaConfig
+		store, err := media.NewStore(cfg.Dir, cfg.ThumbnailSize, cfg.MaxSize, media.NewLoader("", cfg.MaxSize))
+		if err != nil {
+			panic(err)
+		}
+		mediaStore = store
+		mediaBaseUrl = cfg.BaseUrl
+	}
+
+	cachePolicy = NewCachePolicy(c.NFTCacheConfig)
+	cacheInvalidator = NewCacheInvalidator(c.NFTCacheConfig)
+	cacheInvalidator.Start()
+
 	txCounter = NewTransactionCounter()
+	collectionCounter = NewCollectionCounter(c.NFTStatsConfig)
+	collectionCounter.Start()
+
+	assetWatcher = NewAssetWatcher(c.NFTAssetsConfig)
+	assetWatcher.Start()
 }
 
 type TransactionCounter struct {
@@ -123,8 +159,8 @@ func NewTransactionCounter() *TransactionCounter {
 }
 
 func (s *TransactionCounter) refresh() {
-	db.Model(&models.WrapperTransaction{}).
-		Where("standard = ?", models.TokenTypeErc721).
+	readDB().Model(&models.WrapperTransaction{}).
+		Where("standard in ?", models.NFTStandards).
 		Count(&s.Count)
 
 	s.LastTime = time.Now().Unix()
@@ -140,7 +176,10 @@ func (s *TransactionCounter) Number() int64 {
 	return s.Count
 }
 
-func selectNodeAndWrapper(chainId uint64) (*chainsdk.EthereumSdkPro, common.Address, error) {
+func selectNodeAndWrapper(chainId uint64) (NFTChain, common.Address, error) {
+	sdkLock.Lock()
+	defer sdkLock.Unlock()
+
 	cfg, ok := chainConfig[chainId]
 	if !ok {
 		return nil, emptyAddr, fmt.Errorf("chain id %d invalid", chainId)
@@ -152,7 +191,7 @@ func selectNodeAndWrapper(chainId uint64) (*chainsdk.EthereumSdkPro, common.Addr
 		if len(urls) == 0 {
 			return nil, emptyAddr, fmt.Errorf("chainId %d not exist", chainId)
 		}
-		pro = chainsdk.NewEthereumSdkPro(urls, cfg.ListenSlot, chainId)
+		pro = newNFTChain(urls, cfg.ListenSlot, chainId)
 		sdks[chainId] = pro
 	}
 
@@ -166,8 +205,33 @@ func selectNodeAndWrapper(chainId uint64) (*chainsdk.EthereumSdkPro, common.Addr
 
 var emptyAddr = common.Address{}
 
+// newNFTChain reads the NFTs of NEO and Ontology from their NFT contracts, the other chains are EVM chains.
+func newNFTChain(urls []string, slot uint64, chainId uint64) NFTChain {
+	switch chainId {
+	case basedef.NEO_CROSSCHAIN_ID:
+		return newContractNFTChain(chainId, chainsdk.NewNeoSdkPro(urls, slot, chainId))
+	case basedef.ONT_CROSSCHAIN_ID:
+		return newContractNFTChain(chainId, chainsdk.NewOntologySdkPro(urls, slot, chainId))
+	default:
+		return chainsdk.NewEthereumSdkPro(urls, slot, chainId)
+	}
+}
+
+func readTokenURI(chainId uint64, asset string, tokenId *big.Int) (string, error) {
+	sdkLock.Lock()
+	sdk, ok := sdks[chainId]
+	sdkLock.Unlock()
+	if !ok {
+		return "", fmt.Errorf("chainId %d not exist", chainId)
+	}
+	if token := selectNFTAsset(asset); token != nil && token.Standard == models.TokenTypeErc1155 {
+		return sdk.GetERC1155Url(common.HexToAddress(asset), tokenId)
+	}
+	return sdk.GetNFTUrl(common.HexToAddress(asset), tokenId)
+}
+
 func selectNFTAsset(addr string) *models.Token {
-	for _, v := range assets {
+	for _, v := range nftAssets() {
 		origin := common.HexToAddress(v.Hash)
 		src := common.HexToAddress(addr)
 		if bytes.Equal(origin.Bytes(), src.Bytes()) {
@@ -179,7 +243,7 @@ func selectNFTAsset(addr string) *models.Token {
 
 func selectAssetsByChainId(chainId uint64) []*models.Token {
 	res := make([]*models.Token, 0)
-	for _, v := range assets {
+	for _, v := range nftAssets() {
 		if v.ChainId == chainId {
 			res = append(res, v)
 		}
@@ -277,7 +341,7 @@ func getPageNo(totalNo, pageSize int) int {
 
 func findFeeToken(cid uint64, hash string) *models.Token {
 	feeTokens := make([]*models.Token, 0)
-	db.Model(&models.Token{}).
+	readDB().Model(&models.Token{}).
 		Where("hash = ?", nativeHash).
 		Preload("TokenBasic").
 		Find(&feeTokens)
@@ -291,7 +355,7 @@ func findFeeToken(cid uint64, hash string) *models.Token {
 }
 
 func findAsset(cid uint64, hash string) *models.Token {
-	for _, v := range assets {
+	for _, v := range nftAssets() {
 		if v.ChainId == cid && hash == v.Hash {
 			return v
 		}
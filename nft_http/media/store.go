@@ -0,0 +1,197 @@
+package media
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"poly-bridge/nft_http/meta/standard"
+	"poly-bridge/nft_http/meta/utils"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultThumbnailSize = 256
+	DefaultMaxSize       = 10 << 20
+	// images with more pixels are not decoded for the thumbnails
+	maxPixels = 40000000
+)
+
+var (
+	ErrNotImage      = errors.New("not an image")
+	ErrTooLarge      = errors.New("image is too large")
+	ErrForbiddenHost = utils.ErrForbiddenHost
+)
+
+// Loader reads the image of the url.
+type Loader func(url string) ([]byte, error)
+
+// Store keeps the NFT images and their thumbnails on the local disk, the files are named by the sha256 of the
+// image url, so an image is loaded from its host only once.
+type Store struct {
+	dir           string
+	thumbnailSize int
+	maxSize       int64
+	loader        Loader
+}
+
+func NewStore(dir string, thumbnailSize int, maxSize int64, loader Loader) (*Store, error) {
+	if thumbnailSize <= 0 {
+		thumbnailSize = DefaultThumbnailSize
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Store{
+		dir:           dir,
+		thumbnailSize: thumbnailSize,
+		maxSize:       maxSize,
+		loader:        loader,
+	}, nil
+}
+
+// Get returns the image of the url and its content type. The thumbnail is a png which fits in the thumbnail
+// size, the image itself is the thumbnail if it can not be decoded.
+func (s *Store) Get(url string, thumbnail bool) ([]byte, string, error) {
+	path := s.path(url, thumbnail)
+	if data, err := ioutil.ReadFile(path); err == nil {
+		return data, http.DetectContentType(data), nil
+	}
+	origin, err := s.origin(url)
+	if err != nil {
+		return nil, "", err
+	}
+	if !thumbnail {
+		return origin, http.DetectContentType(origin), nil
+	}
+	data, err := makeThumbnail(origin, s.thumbnailSize)
+	if err != nil {
+		return origin, http.DetectContentType(origin), nil
+	}
+	if err = s.write(path, data); err != nil {
+		return nil, "", err
+	}
+	return data, "image/png", nil
+}
+
+func (s *Store) origin(url string) ([]byte, error) {
+	path := s.path(url, false)
+	if data, err := ioutil.ReadFile(path); err == nil {
+		return data, nil
+	}
+	data, err := s.loader(url)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrTooLarge
+	}
+	if !strings.HasPrefix(http.DetectContentType(data), "image/") {
+		return nil, ErrNotImage
+	}
+	if err = s.write(path, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *Store) path(url string, thumbnail bool) string {
+	hash := sha256.Sum256([]byte(url))
+	key := hex.EncodeToString(hash[:])
+	if thumbnail {
+		key += ".thumb"
+	}
+	return filepath.Join(s.dir, key[:2], key)
+}
+
+// write stores the file by renaming a temporary file, so a file being written is never read.
+func (s *Store) write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(filepath.Dir(path), "tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func makeThumbnail(data []byte, size int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err = png.Encode(buf, Resize(img, size)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// NewLoader loads the images from the public http hosts and the data uris, ipfs uris are read from the gateway.
+// At most maxSize bytes are read.
+func NewLoader(gateway string, maxSize int64) Loader {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	client := utils.NewPublicClient(15 * time.Second)
+	return func(url string) ([]byte, error) {
+		if strings.HasPrefix(url, "data:") {
+			return standard.Load(url, "")
+		}
+		resolved := standard.ResolveURI(url, gateway)
+		u, err := neturl.Parse(resolved)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("uri %s is not supported", url)
+		}
+		resp, err := client.Get(resolved)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("request %s, status %d", resolved, resp.StatusCode)
+		}
+		data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > maxSize {
+			return nil, ErrTooLarge
+		}
+		return data, nil
+	}
+}
@@ -0,0 +1,98 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	buf := new(bytes.Buffer)
+	assert.NoError(t, png.Encode(buf, img))
+	return buf.Bytes()
+}
+
+func TestResize(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 100))
+	resized := Resize(img, 200)
+	assert.Equal(t, 200, resized.Bounds().Dx())
+	assert.Equal(t, 50, resized.Bounds().Dy())
+
+	resized = Resize(image.NewNRGBA(image.Rect(0, 0, 10, 300)), 30)
+	assert.Equal(t, 1, resized.Bounds().Dx())
+	assert.Equal(t, 30, resized.Bounds().Dy())
+
+	small := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	assert.Equal(t, image.Image(small), Resize(small, 30))
+}
+
+func TestStore_Get(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nft-media")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	loads := 0
+	origin := pngImage(t, 64, 32)
+	store, err := NewStore(dir, 16, 1<<20, func(url string) ([]byte, error) {
+		loads++
+		switch url {
+		case "https://img.example.com/1.png":
+			return origin, nil
+		case "https://img.example.com/1.json":
+			return []byte(`{"name":"a"}`), nil
+		}
+		return nil, fmt.Errorf("not found")
+	})
+	assert.NoError(t, err)
+
+	data, contentType, err := store.Get("https://img.example.com/1.png", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, origin, data)
+
+	data, contentType, err = store.Get("https://img.example.com/1.png", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	thumbnail, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 16, thumbnail.Bounds().Dx())
+	assert.Equal(t, 8, thumbnail.Bounds().Dy())
+
+	_, _, err = store.Get("https://img.example.com/1.png", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, loads)
+
+	_, _, err = store.Get("https://img.example.com/1.json", false)
+	assert.Equal(t, ErrNotImage, err)
+	_, _, err = store.Get("https://img.example.com/2.png", false)
+	assert.Error(t, err)
+}
+
+func TestLoaderForbiddenHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	loader := NewLoader("", 0)
+	_, err := loader(server.URL + "/1.png")
+	assert.True(t, errors.Is(err, ErrForbiddenHost), err)
+	_, err = loader("ftp://img.example.com/1.png")
+	assert.Error(t, err)
+}
@@ -1,6 +1,17 @@
 package params
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
 type FetchRequestParams struct {
 	TokenId string //*models.BigInt
 	Url     string
 }
+
+// ContentHash is the hex sha256 of the metadata, which changes when the metadata is updated.
+func ContentHash(raw []byte) string {
+	hash := sha256.Sum256(raw)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,252 @@
+package meta
+
+import (
+	"bytes"
+	"math/big"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	. "poly-bridge/nft_http/meta/common"
+	"poly-bridge/nft_http/meta/utils"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultIndexSlot   = 60
+	defaultRefreshSlot = 86400
+	defaultBatchSize   = 100
+	// the transfers committed late are found again in the overlap behind the cursor
+	cursorOverlap = 1000
+)
+
+// TokenURIReader reads the token uri of the NFT on its chain.
+type TokenURIReader func(chainId uint64, asset string, tokenId *big.Int) (string, error)
+
+type transferredToken struct {
+	Id      int64
+	ChainId uint64
+	Asset   string
+	Amount  string
+}
+
+// Indexer discovers the token ids from the NFT transfers of the cross chain transactions and stores their profiles
+// before they are requested, the stored profiles are fetched again on schedule.
+type Indexer struct {
+	fetcher     *StoreFetcher
+	db          *gorm.DB
+	reader      TokenURIReader
+	assetsLock  sync.RWMutex
+	assets      []*models.Token
+	indexSlot   int64
+	refreshSlot int64
+	batchSize   int
+	cursors     map[string]*utils.IdCursor
+	exit        chan bool
+}
+
+func NewIndexer(cfg *conf.NFTIndexerConfig, fetcher *StoreFetcher, db *gorm.DB, assets []*models.Token, reader TokenURIReader) *Indexer {
+	indexer := &Indexer{
+		fetcher:     fetcher,
+		db:          db,
+		reader:      reader,
+		assets:      assets,
+		indexSlot:   cfg.IndexSlot,
+		refreshSlot: cfg.RefreshSlot,
+		batchSize:   cfg.BatchSize,
+		cursors:     make(map[string]*utils.IdCursor),
+		exit:        make(chan bool, 0),
+	}
+	if indexer.indexSlot <= 0 {
+		indexer.indexSlot = defaultIndexSlot
+	}
+	if indexer.refreshSlot <= 0 {
+		indexer.refreshSlot = defaultRefreshSlot
+	}
+	if indexer.batchSize <= 0 {
+		indexer.batchSize = defaultBatchSize
+	}
+	indexer.loadCursors()
+	return indexer
+}
+
+// loadCursors restores the cursors saved before the restart, the tables without one are indexed from the start.
+func (idx *Indexer) loadCursors() {
+	saved := map[string]int64{"src_transfers": 0, "dst_transfers": 0}
+	cursors := make([]*models.NFTIndexCursor, 0)
+	if res := idx.db.Find(&cursors); res.Error != nil {
+		logs.Error("nft indexer load cursors err: %v", res.Error)
+	}
+	for _, cursor := range cursors {
+		if _, ok := saved[cursor.Name]; ok {
+			saved[cursor.Name] = cursor.Cursor
+		}
+	}
+	for table, cursor := range saved {
+		idx.cursors[table] = utils.NewIdCursor(cursor, cursorOverlap)
+	}
+}
+
+func (idx *Indexer) Start() {
+	logs.Info("start nft indexer.")
+	go idx.run()
+}
+
+func (idx *Indexer) Stop() {
+	idx.exit <- true
+	logs.Info("stop nft indexer.")
+}
+
+// SetAssets replaces the assets indexed after they are reloaded.
+func (idx *Indexer) SetAssets(assets []*models.Token) {
+	idx.assetsLock.Lock()
+	defer idx.assetsLock.Unlock()
+	idx.assets = assets
+}
+
+func (idx *Indexer) indexedAssets() []*models.Token {
+	idx.assetsLock.RLock()
+	defer idx.assetsLock.RUnlock()
+	return idx.assets
+}
+
+func (idx *Indexer) run() {
+	ticker := time.NewTicker(time.Second * time.Duration(idx.indexSlot))
+	defer ticker.Stop()
+	for {
+		idx.round()
+		select {
+		case <-ticker.C:
+		case <-idx.exit:
+			return
+		}
+	}
+}
+
+func (idx *Indexer) round() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("nft indexer recover info: %s", string(debug.Stack()))
+		}
+	}()
+	for table := range idx.cursors {
+		if err := idx.discover(table); err != nil {
+			logs.Error("nft indexer discover %s err: %v", table, err)
+		}
+	}
+	if err := idx.refresh(); err != nil {
+		logs.Error("nft indexer refresh err: %v", err)
+	}
+}
+
+// discover indexes the tokens of the ERC721 and ERC1155 transfers after the cursor of the table which are not
+// stored yet, the amount of an NFT transfer is its token id. The overlap behind the cursor is read again for the
+// transfers committed after the ones of higher ids.
+func (idx *Indexer) discover(table string) error {
+	cursor := idx.cursors[table]
+	from := cursor.Rewind()
+	for {
+		transfers := make([]*transferredToken, 0)
+		res := idx.db.Table(table).Select("id, chain_id, asset, amount").
+			Where("standard in ? and id > ?", models.NFTStandards, from).
+			Order("id asc").Limit(idx.batchSize).Find(&transfers)
+		if res.Error != nil {
+			return res.Error
+		}
+		if len(transfers) == 0 {
+			return nil
+		}
+
+		tokenIds := make(map[string][]string)
+		assets := make(map[string]*models.Token)
+		for _, transfer := range transfers {
+			if !cursor.Read(transfer.Id) {
+				continue
+			}
+			asset := idx.selectAsset(transfer.ChainId, transfer.Asset)
+			if asset == nil {
+				continue
+			}
+			assets[asset.TokenBasicName] = asset
+			tokenIds[asset.TokenBasicName] = append(tokenIds[asset.TokenBasicName], transfer.Amount)
+		}
+		for name, ids := range tokenIds {
+			stored := make([]string, 0)
+			idx.db.Model(&models.NFTProfile{}).Where("token_basic_name = ? and nft_token_id in ?", name, ids).Pluck("nft_token_id", &stored)
+			indexed := make(map[string]bool, len(stored))
+			for _, id := range stored {
+				indexed[id] = true
+			}
+			for _, id := range ids {
+				if indexed[id] {
+					continue
+				}
+				indexed[id] = true
+				if err := idx.index(assets[name], id); err != nil {
+					logs.Warn("nft indexer index %s token %s err: %v", name, id, err)
+				}
+			}
+		}
+		from = transfers[len(transfers)-1].Id
+		if res := idx.db.Save(&models.NFTIndexCursor{Name: table, Cursor: cursor.Last}); res.Error != nil {
+			logs.Error("nft indexer save cursor of %s err: %v", table, res.Error)
+		}
+		if len(transfers) < idx.batchSize {
+			return nil
+		}
+	}
+}
+
+// refresh fetches the profiles which are not fetched in the refresh slot again.
+func (idx *Indexer) refresh() error {
+	profiles := make([]*models.NFTProfile, 0)
+	res := idx.db.Where("time < ?", time.Now().Unix()-idx.refreshSlot).
+		Order("time asc").Limit(idx.batchSize).Find(&profiles)
+	if res.Error != nil {
+		return res.Error
+	}
+	for _, profile := range profiles {
+		err := ErrFetcherNotExist
+		for _, asset := range idx.indexedAssets() {
+			if asset.TokenBasicName != profile.TokenBasicName {
+				continue
+			}
+			if err = idx.index(asset, profile.NftTokenId); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			logs.Warn("nft indexer refresh %s token %s err: %v", profile.TokenBasicName, profile.NftTokenId, err)
+			// try it again in the next refresh slot
+			idx.db.Model(profile).Update("time", time.Now().Unix())
+		}
+	}
+	return nil
+}
+
+func (idx *Indexer) index(asset *models.Token, tokenId string) error {
+	id, ok := new(big.Int).SetString(tokenId, 10)
+	if !ok {
+		return ErrInvalidTokenId
+	}
+	uri, err := idx.reader(asset.ChainId, asset.Hash, id)
+	if err != nil {
+		return err
+	}
+	_, err = idx.fetcher.Refresh(asset.TokenBasicName, &FetchRequestParams{TokenId: tokenId, Url: uri})
+	return err
+}
+
+func (idx *Indexer) selectAsset(chainId uint64, hash string) *models.Token {
+	addr := common.HexToAddress(hash)
+	for _, asset := range idx.indexedAssets() {
+		if asset.ChainId == chainId && bytes.Equal(common.HexToAddress(asset.Hash).Bytes(), addr.Bytes()) {
+			return asset
+		}
+	}
+	return nil
+}
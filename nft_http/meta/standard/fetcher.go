@@ -0,0 +1,54 @@
+package standard
+
+import (
+	"encoding/json"
+	"math/big"
+	"poly-bridge/models"
+	. "poly-bridge/nft_http/meta/common"
+)
+
+// Fetcher reads the ERC721 metadata json from the token uri of the NFT, BaseUri is the ipfs gateway.
+type Fetcher struct {
+	Asset   string
+	BaseUri string
+}
+
+func NewFetcher(asset, baseUri string) *Fetcher {
+	return &Fetcher{
+		Asset:   asset,
+		BaseUri: baseUri,
+	}
+}
+
+func (f *Fetcher) Fetch(req *FetchRequestParams) (*models.NFTProfile, error) {
+	raw, err := Load(req.Url, f.BaseUri)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := new(Profile)
+	if err = json.Unmarshal(raw, origin); err != nil {
+		return nil, err
+	}
+	profile, err := origin.Convert(f.Asset, req.TokenId, req.Url, f.BaseUri)
+	if err != nil {
+		return nil, err
+	}
+	profile.ContentHash = ContentHash(raw)
+	return profile, nil
+}
+
+func (f *Fetcher) BatchFetch(reqs []*FetchRequestParams) ([]*models.NFTProfile, error) {
+	list := make([]*models.NFTProfile, 0)
+	for _, v := range reqs {
+		if data, err := f.Fetch(v); err == nil {
+			list = append(list, data)
+		}
+	}
+	return list, nil
+}
+
+// FullUrl is empty, the url of every token is its token uri on chain.
+func (f *Fetcher) FullUrl(tokenId *big.Int) string {
+	return ""
+}
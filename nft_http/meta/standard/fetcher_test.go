@@ -0,0 +1,94 @@
+package standard
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	. "poly-bridge/nft_http/meta/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveURI(t *testing.T) {
+	var testdata = []struct {
+		uri     string
+		gateway string
+		expect  string
+	}{
+		{uri: "https://api.example.com/nft/1", expect: "https://api.example.com/nft/1"},
+		{uri: "ipfs://QmHash/1.json", expect: "https://ipfs.io/ipfs/QmHash/1.json"},
+		{uri: "ipfs://ipfs/QmHash/1.json", expect: "https://ipfs.io/ipfs/QmHash/1.json"},
+		{uri: "ipfs://QmHash", gateway: "https://gateway.pinata.cloud/ipfs/", expect: "https://gateway.pinata.cloud/ipfs/QmHash"},
+	}
+	for _, v := range testdata {
+		assert.Equal(t, v.expect, ResolveURI(v.uri, v.gateway))
+	}
+}
+
+func TestLoadDataURI(t *testing.T) {
+	data, err := Load("data:application/json;base64,eyJuYW1lIjoiYSJ9", "")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"a"}`, string(data))
+
+	data, err = Load("data:application/json,%7B%22name%22%3A%22a%22%7D", "")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"a"}`, string(data))
+
+	_, err = Load("ar://abc", "")
+	assert.Error(t, err)
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipfs/QmHash/7" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"name":"Seal #7","description":"a seal","image":"ipfs://QmImage/7.png","animation_url":"ipfs://QmImage/7.mp4",`+
+			`"attributes":[{"trait_type":"eyes","value":"blue"}]}`)
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher("seal", server.URL+"/ipfs/")
+	profile, err := fetcher.Fetch(&FetchRequestParams{TokenId: "7", Url: "ipfs://QmHash/7"})
+	assert.NoError(t, err)
+	assert.Equal(t, "seal", profile.TokenBasicName)
+	assert.Equal(t, "7", profile.NftTokenId)
+	assert.Equal(t, "Seal #7", profile.Name)
+	assert.Equal(t, server.URL+"/ipfs/QmImage/7.png", profile.Image)
+	assert.Equal(t, server.URL+"/ipfs/QmHash/7", profile.Url)
+	assert.Contains(t, profile.Text, "trait_type")
+	assert.Equal(t, server.URL+"/ipfs/QmImage/7.mp4", profile.AnimationUrl)
+	assert.Equal(t, 1, len(profile.Attributes))
+	assert.Equal(t, "eyes", profile.Attributes[0].TraitType)
+	assert.Equal(t, 64, len(profile.ContentHash))
+
+	_, err = fetcher.Fetch(&FetchRequestParams{TokenId: "8", Url: "ipfs://QmHash/8"})
+	assert.Error(t, err)
+
+	profiles, err := fetcher.BatchFetch([]*FetchRequestParams{{TokenId: "7", Url: "ipfs://QmHash/7"}, {TokenId: "8", Url: "ipfs://QmHash/8"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(profiles))
+}
+
+func TestConvertAttributes(t *testing.T) {
+	attributes := ConvertAttributes([]byte(`[{"trait_type":"level","value":5,"display_type":"number"},` +
+		`{"trait_type":"rare","value":true},{"value":"plain"},null]`))
+	assert.Equal(t, 3, len(attributes))
+	assert.Equal(t, "level", attributes[0].TraitType)
+	assert.Equal(t, "5", attributes[0].Value)
+	assert.Equal(t, "number", attributes[0].DisplayType)
+	assert.Equal(t, "true", attributes[1].Value)
+	assert.Equal(t, "", attributes[2].TraitType)
+	assert.Equal(t, "plain", attributes[2].Value)
+
+	attributes = ConvertAttributes([]byte(`{"hat":"red","eyes":{"left":"blue"}}`))
+	assert.Equal(t, 2, len(attributes))
+	assert.Equal(t, "eyes", attributes[0].TraitType)
+	assert.Equal(t, `{"left":"blue"}`, attributes[0].Value)
+	assert.Equal(t, "hat", attributes[1].TraitType)
+
+	assert.Equal(t, 0, len(ConvertAttributes([]byte(`"unknown"`))))
+	assert.Equal(t, 0, len(ConvertAttributes(nil)))
+}
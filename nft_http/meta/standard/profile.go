@@ -0,0 +1,124 @@
+package standard
+
+import (
+	"encoding/json"
+	"fmt"
+	"poly-bridge/models"
+	"sort"
+	"strconv"
+)
+
+// Profile is the ERC721 metadata json schema, with the fields commonly used by the marketplaces.
+type Profile struct {
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	Image        string          `json:"image"`
+	ImageUrl     string          `json:"image_url,omitempty"`
+	AnimationUrl string          `json:"animation_url,omitempty"`
+	ExternalUrl  string          `json:"external_url,omitempty"`
+	Attributes   json.RawMessage `json:"attributes,omitempty"`
+}
+
+// Attribute is a trait of the opensea metadata standard, the value may be a string, a number or a bool.
+type Attribute struct {
+	TraitType   string      `json:"trait_type"`
+	Value       interface{} `json:"value"`
+	DisplayType string      `json:"display_type,omitempty"`
+}
+
+const urlSize = 1024
+
+func (p *Profile) Convert(assetName string, tokenId string, tokenUri string, gateway string) (*models.NFTProfile, error) {
+	np := new(models.NFTProfile)
+	np.TokenBasicName = assetName
+	np.NftTokenId = tokenId
+	np.Name = truncate(p.Name, 128)
+	np.Description = truncate(p.Description, 256)
+	image := p.Image
+	if image == "" {
+		image = p.ImageUrl
+	}
+	// urls too long for the columns, e.g. data uris of on chain images, are only kept in the text
+	np.Image = fit(ResolveURI(image, gateway), urlSize)
+	np.AnimationUrl = fit(ResolveURI(p.AnimationUrl, gateway), urlSize)
+	np.ExternalUrl = fit(p.ExternalUrl, urlSize)
+	np.Url = np.ExternalUrl
+	if np.Url == "" {
+		np.Url = fit(ResolveURI(tokenUri, gateway), urlSize)
+	}
+	np.Attributes = ConvertAttributes(p.Attributes)
+
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	np.Text = string(raw)
+	return np, nil
+}
+
+// ConvertAttributes reads the attributes as the list of the opensea traits, or as an object of the trait
+// types and values which some collections use. Attributes in other formats are only kept in the text.
+func ConvertAttributes(raw json.RawMessage) []*models.NFTProfileAttribute {
+	attributes := make([]*models.NFTProfileAttribute, 0)
+	if len(raw) == 0 {
+		return attributes
+	}
+	traits := make([]*Attribute, 0)
+	if err := json.Unmarshal(raw, &traits); err != nil {
+		object := make(map[string]interface{})
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return attributes
+		}
+		for traitType, value := range object {
+			traits = append(traits, &Attribute{TraitType: traitType, Value: value})
+		}
+		sort.Slice(traits, func(i, j int) bool {
+			return traits[i].TraitType < traits[j].TraitType
+		})
+	}
+	for _, trait := range traits {
+		if trait == nil {
+			continue
+		}
+		attributes = append(attributes, &models.NFTProfileAttribute{
+			TraitType:   truncate(trait.TraitType, 128),
+			Value:       truncate(formatValue(trait.Value), 256),
+			DisplayType: truncate(trait.DisplayType, 32),
+		})
+	}
+	return attributes
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(raw)
+	}
+}
+
+func truncate(s string, size int) string {
+	runes := []rune(s)
+	if len(runes) <= size {
+		return s
+	}
+	return string(runes[:size])
+}
+
+func fit(s string, size int) string {
+	if len(s) > size {
+		return ""
+	}
+	return s
+}
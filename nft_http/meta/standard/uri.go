@@ -0,0 +1,59 @@
+package standard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"poly-bridge/nft_http/meta/utils"
+	"strings"
+)
+
+const DefaultIpfsGateway = "https://ipfs.io/ipfs/"
+
+// ResolveURI converts the ipfs uri to the url of the gateway, http urls are returned as they are.
+func ResolveURI(uri, gateway string) string {
+	uri = strings.TrimSpace(uri)
+	if !strings.HasPrefix(uri, "ipfs://") {
+		return uri
+	}
+	if gateway == "" {
+		gateway = DefaultIpfsGateway
+	}
+	path := strings.TrimPrefix(uri, "ipfs://")
+	path = strings.TrimPrefix(path, "ipfs/")
+	return strings.TrimSuffix(gateway, "/") + "/" + path
+}
+
+// Load reads the content of the uri, which may be a http url, an ipfs uri or a data uri.
+// Only the public hosts are requested, except the gateway which is configured by the operator.
+func Load(uri, gateway string) ([]byte, error) {
+	uri = strings.TrimSpace(uri)
+	if strings.HasPrefix(uri, "data:") {
+		return decodeDataURI(uri)
+	}
+	resolved := ResolveURI(uri, gateway)
+	if !strings.HasPrefix(resolved, "http://") && !strings.HasPrefix(resolved, "https://") {
+		return nil, fmt.Errorf("uri %s is not supported", uri)
+	}
+	if gateway != "" && strings.HasPrefix(uri, "ipfs://") {
+		return utils.RequestTrusted(resolved)
+	}
+	return utils.Request(resolved)
+}
+
+// decodeDataURI decodes data:[<mediatype>][;base64],<data>
+func decodeDataURI(uri string) ([]byte, error) {
+	comma := strings.Index(uri, ",")
+	if comma < 0 {
+		return nil, fmt.Errorf("data uri has no data")
+	}
+	header, data := uri[len("data:"):comma], uri[comma+1:]
+	if strings.HasSuffix(header, ";base64") {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	decoded, err := url.PathUnescape(data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(decoded), nil
+}
@@ -6,7 +6,11 @@ import (
 	"poly-bridge/models"
 	. "poly-bridge/nft_http/meta/common"
 	"poly-bridge/nft_http/meta/seascape"
+	"poly-bridge/nft_http/meta/standard"
+	"sync"
+	"time"
 
+	"github.com/astaxie/beego/logs"
 	"gorm.io/gorm"
 )
 
@@ -28,33 +32,61 @@ const (
 	FetcherTypeUnknown = iota
 	FetcherTypeSeascape
 	FetcherTypeMockSeascape
+	FetcherTypeStandard
 )
 
-var ErrFetcherNotExist = errors.New("fetcher not exist")
+var (
+	ErrFetcherNotExist = errors.New("fetcher not exist")
+	ErrInvalidTokenId  = errors.New("invalid token id")
+)
+
+// FetcherCreator creates the fetcher of an asset, baseUri is the meta of the token basic.
+type FetcherCreator func(assetName, baseUri string) MetaFetcher
 
-func NewFetcher(fetcherTyp FetcherType, assetName, baseUri string) (fetcher MetaFetcher) {
-	switch fetcherTyp {
-	case FetcherTypeMockSeascape:
-		fetcher = seascape.NewMockFetcher(assetName, baseUri)
-	case FetcherTypeSeascape:
-		fetcher = seascape.NewFetcher(assetName, baseUri)
-	default:
-		fetcher = nil
+var (
+	creatorsMutex sync.RWMutex
+	creators      = make(map[FetcherType]FetcherCreator)
+)
+
+func init() {
+	RegisterFetcher(FetcherTypeSeascape, func(assetName, baseUri string) MetaFetcher {
+		return seascape.NewFetcher(assetName, baseUri)
+	})
+	RegisterFetcher(FetcherTypeMockSeascape, func(assetName, baseUri string) MetaFetcher {
+		return seascape.NewMockFetcher(assetName, baseUri)
+	})
+	RegisterFetcher(FetcherTypeStandard, func(assetName, baseUri string) MetaFetcher {
+		return standard.NewFetcher(assetName, baseUri)
+	})
+}
+
+// RegisterFetcher makes the fetcher type available to the assets, it replaces the creator registered before.
+func RegisterFetcher(fetcherTyp FetcherType, creator FetcherCreator) {
+	creatorsMutex.Lock()
+	defer creatorsMutex.Unlock()
+	creators[fetcherTyp] = creator
+}
+
+func NewFetcher(fetcherTyp FetcherType, assetName, baseUri string) MetaFetcher {
+	creatorsMutex.RLock()
+	creator, ok := creators[fetcherTyp]
+	creatorsMutex.RUnlock()
+	if !ok {
+		return nil
 	}
-	return
+	return creator(assetName, baseUri)
 }
 
 type StoreFetcher struct {
-	fetcher      map[FetcherType]MetaFetcher
-	assetFetcher map[string]FetcherType // mapping asset to fetcher type
-	db           *gorm.DB
+	mutex   sync.RWMutex
+	fetcher map[string]MetaFetcher // mapping asset to its fetcher
+	db      *gorm.DB
 }
 
 func NewStoreFetcher(orm *gorm.DB) *StoreFetcher {
 	sf := new(StoreFetcher)
 	sf.db = orm
-	sf.fetcher = make(map[FetcherType]MetaFetcher)
-	sf.assetFetcher = make(map[string]FetcherType)
+	sf.fetcher = make(map[string]MetaFetcher)
 	return sf
 }
 
@@ -63,16 +95,21 @@ func (s *StoreFetcher) Register(ft FetcherType, asset string, baseUri string) {
 	if fetcher == nil {
 		return
 	}
-	s.fetcher[ft] = fetcher
-	s.assetFetcher[asset] = ft
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.fetcher[asset] = fetcher
+}
+
+func (s *StoreFetcher) Unregister(asset string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.fetcher, asset)
 }
 
 func (s *StoreFetcher) selectFetcher(asset string) MetaFetcher {
-	typ, ok := s.assetFetcher[asset]
-	if !ok {
-		return nil
-	}
-	fetcher, ok := s.fetcher[typ]
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	fetcher, ok := s.fetcher[asset]
 	if !ok {
 		return nil
 	}
@@ -88,6 +125,7 @@ func (s *StoreFetcher) Fetch(asset string, req *FetchRequestParams) (profile *mo
 	profile = new(models.NFTProfile)
 	res := s.db.Model(&models.NFTProfile{}).
 		Where("token_basic_name = ? and nft_token_id = ?", asset, req.TokenId).
+		Preload("Attributes").
 		Find(profile)
 	if res.RowsAffected > 0 {
 		return profile, nil
@@ -97,10 +135,29 @@ func (s *StoreFetcher) Fetch(asset string, req *FetchRequestParams) (profile *mo
 		return nil, err
 	}
 
-	s.db.Save(profile)
+	profile.Time = time.Now().Unix()
+	s.saveProfiles(profile)
 	return
 }
 
+// Refresh fetches the profile from the origin even if it is stored, and replaces the stored one.
+func (s *StoreFetcher) Refresh(asset string, req *FetchRequestParams) (*models.NFTProfile, error) {
+	fetcher := s.selectFetcher(asset)
+	if fetcher == nil {
+		return nil, ErrFetcherNotExist
+	}
+
+	profile, err := fetcher.Fetch(req)
+	if err != nil {
+		return nil, err
+	}
+	profile.Time = time.Now().Unix()
+	if err := s.saveProfiles(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
 func (s *StoreFetcher) BatchFetch(asset string, reqs []*FetchRequestParams) ([]*models.NFTProfile, error) {
 	fetcher := s.selectFetcher(asset)
 	if fetcher == nil {
@@ -117,7 +174,7 @@ func (s *StoreFetcher) BatchFetch(asset string, reqs []*FetchRequestParams) ([]*
 	}
 
 	persisted := make([]*models.NFTProfile, 0)
-	s.db.Where("token_basic_name = ? and nft_token_id in (?)", asset, unCacheList).Find(&persisted)
+	s.db.Where("token_basic_name = ? and nft_token_id in (?)", asset, unCacheList).Preload("Attributes").Find(&persisted)
 	for _, v := range persisted {
 		finalList = append(finalList, v)
 		delete(needFetchMap, v.NftTokenId)
@@ -135,7 +192,13 @@ func (s *StoreFetcher) BatchFetch(asset string, reqs []*FetchRequestParams) ([]*
 	if err != nil {
 		return nil, err
 	}
-	s.db.Save(profiles)
+	now := time.Now().Unix()
+	for _, v := range profiles {
+		v.Time = now
+	}
+	if err = s.saveProfiles(profiles...); err != nil {
+		logs.Error("save nft profiles of %s err: %v", asset, err)
+	}
 
 	for _, v := range profiles {
 		finalList = append(finalList, v)
@@ -143,3 +206,34 @@ func (s *StoreFetcher) BatchFetch(asset string, reqs []*FetchRequestParams) ([]*
 
 	return finalList, nil
 }
+
+// saveProfiles replaces the stored profiles and their attributes.
+func (s *StoreFetcher) saveProfiles(profiles ...*models.NFTProfile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for _, profile := range profiles {
+			if err := tx.Omit("Attributes").Save(profile).Error; err != nil {
+				return err
+			}
+			err := tx.Where("token_basic_name = ? and nft_token_id = ?", profile.TokenBasicName, profile.NftTokenId).
+				Delete(&models.NFTProfileAttribute{}).Error
+			if err != nil {
+				return err
+			}
+			if len(profile.Attributes) == 0 {
+				continue
+			}
+			for _, attribute := range profile.Attributes {
+				attribute.Id = 0
+				attribute.TokenBasicName = profile.TokenBasicName
+				attribute.NftTokenId = profile.NftTokenId
+			}
+			if err = tx.Create(profile.Attributes).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package utils
+
+// IdCursor follows the rows of a table in the order of their ids. The ids are taken when the rows are inserted,
+// so a row of a longer transaction can be committed after the rows of higher ids are read. The cursor reads the
+// overlap behind the highest id again and only reports the rows which are not read before.
+type IdCursor struct {
+	Last    int64 // the highest id read
+	floor   int64
+	overlap int64
+	read    map[int64]bool
+}
+
+// NewIdCursor starts a cursor after last, the rows up to last are taken as read.
+func NewIdCursor(last, overlap int64) *IdCursor {
+	return &IdCursor{Last: last, floor: last, overlap: overlap, read: make(map[int64]bool)}
+}
+
+// Rewind returns the id to read the rows after in the next round, and forgets the ids read before it.
+func (c *IdCursor) Rewind() int64 {
+	from := c.Last - c.overlap
+	if from < c.floor {
+		from = c.floor
+	}
+	for id := range c.read {
+		if id <= from {
+			delete(c.read, id)
+		}
+	}
+	return from
+}
+
+// Read marks the row read, it returns false if the row has been read already.
+func (c *IdCursor) Read(id int64) bool {
+	if id <= c.floor || c.read[id] {
+		return false
+	}
+	c.read[id] = true
+	if id > c.Last {
+		c.Last = id
+	}
+	return true
+}
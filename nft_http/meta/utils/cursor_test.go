@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdCursor(t *testing.T) {
+	cursor := NewIdCursor(10, 5)
+	assert.Equal(t, int64(10), cursor.Rewind())
+	assert.False(t, cursor.Read(10))
+	for _, id := range []int64{11, 12, 14} {
+		assert.True(t, cursor.Read(id))
+	}
+	assert.Equal(t, int64(14), cursor.Last)
+
+	// 13 is committed after 14 is read
+	assert.Equal(t, int64(10), cursor.Rewind())
+	for _, v := range []struct {
+		id   int64
+		read bool
+	}{{11, false}, {12, false}, {13, true}, {14, false}, {20, true}} {
+		assert.Equal(t, v.read, cursor.Read(v.id), v.id)
+	}
+	assert.Equal(t, int64(20), cursor.Last)
+
+	assert.Equal(t, int64(15), cursor.Rewind())
+	assert.Equal(t, map[int64]bool{20: true}, cursor.read)
+	assert.False(t, cursor.Read(20))
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAddress(t *testing.T) {
+	for _, host := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1",
+		"0.0.0.0", "0.1.2.3", "::1", "::", "fd00::1", "fe80::1", "::ffff:127.0.0.1", "::ffff:8.8.8.8", "224.0.0.1"} {
+		assert.Equal(t, ErrForbiddenHost, CheckAddress(host), host)
+	}
+	for _, host := range []string{"8.8.8.8", "2001:4860:4860::8888"} {
+		assert.NoError(t, CheckAddress(host), host)
+	}
+	assert.Error(t, CheckAddress("localhost"))
+}
+
+func TestPublicClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	_, err := Request(server.URL)
+	assert.True(t, errors.Is(err, ErrForbiddenHost), err)
+	_, err = Request("http://localhost:" + server.URL[len("http://127.0.0.1:"):])
+	assert.True(t, errors.Is(err, ErrForbiddenHost), err)
+}
@@ -1,15 +1,35 @@
 package utils
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
+var (
+	client        = NewPublicClient(15 * time.Second)
+	trustedClient = &http.Client{Timeout: 15 * time.Second}
+)
+
+// Request reads the url from a public host.
 func Request(url string) ([]byte, error) {
-	r, err := http.Get(url)
+	return request(client, url)
+}
+
+// RequestTrusted reads the url from a host configured by the operator, which may be in the local network.
+func RequestTrusted(url string) ([]byte, error) {
+	return request(trustedClient, url)
+}
+
+func request(client *http.Client, url string) ([]byte, error) {
+	r, err := client.Get(url)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = r.Body.Close() }()
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request %s, status %d", url, r.StatusCode)
+	}
 	return ioutil.ReadAll(r.Body)
 }
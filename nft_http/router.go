@@ -29,6 +29,7 @@ func init() {
 		beego.NSRouter("/assetshow/", &controllers.InfoController{}, "post:Home"),
 		beego.NSRouter("/asset/", &controllers.AssetController{}, "post:Asset"),
 		beego.NSRouter("/assets/", &controllers.AssetController{}, "post:Assets"),
+		beego.NSRouter("/assets/reload/", &controllers.AdminController{}, "post:ReloadAssets"),
 
 		//beego.NSRouter("/assetbasics/", &controllers.AssetController{}, "post:AssetBasics"),
 		//beego.NSRouter("/assetmap/", &controllers.AssetMapController{}, "post:AssetMap"),
@@ -36,6 +37,11 @@ func init() {
 
 		beego.NSRouter("/items/", &controllers.ItemController{}, "post:Items"),
 		beego.NSRouter("/getfee/", &controllers.FeeController{}, "post:GetFee"),
+		beego.NSRouter("/checkfee/", &controllers.FeeController{}, "post:CheckFee"),
+		beego.NSRouter("/media/", &controllers.MediaController{}, "get:Media"),
+
+		beego.NSRouter("/collections/", &controllers.CollectionController{}, "get:Collections"),
+		beego.NSRouter("/collection/:asset/activity/", &controllers.CollectionController{}, "get:Activity"),
 
 		beego.NSRouter("/exp_transactions/", &controllers.ExplorerController{}, "post:Transactions"),
 		beego.NSRouter("/exp_transactionsofaddress/", &controllers.ExplorerController{}, "post:TransactionsOfAddress"),
@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepSlot = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  int64
+}
+
+// full returns whether the bucket is filled up to burst again at now.
+func (b *bucket) full(now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= float64(b.burst)
+}
+
+// Limiter keeps a token bucket for every key. A bucket is filled at rate tokens per second up to burst,
+// and a request takes one token.
+type Limiter struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token from the bucket of key. It returns whether the request is allowed, the tokens left,
+// and how long to wait for the next token if it is not allowed.
+func (limiter *Limiter) Allow(key string, rate float64, burst int64, now time.Time) (bool, int64, time.Duration) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	if now.Sub(limiter.lastSweep) > sweepSlot {
+		limiter.sweep(now)
+	}
+	b, ok := limiter.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		limiter.buckets[key] = b
+	}
+	b.rate, b.burst = rate, burst
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(burst), b.tokens+elapsed*rate)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		if rate <= 0 {
+			return false, 0, sweepSlot
+		}
+		wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, 0, wait
+	}
+	b.tokens--
+	return true, int64(b.tokens), 0
+}
+
+// sweep drops the buckets idle for a while which are full again, they would be made full anyway.
+// The buckets of a low rate are kept until they are refilled.
+func (limiter *Limiter) sweep(now time.Time) {
+	for key, b := range limiter.buckets {
+		if now.Sub(b.last) > sweepSlot && b.full(now) {
+			delete(limiter.buckets, key)
+		}
+	}
+	limiter.lastSweep = now
+}
+
+// Counter counts the requests of the keys in a store shared by the instances of the api.
+type Counter interface {
+	// IncrExpire increases the counter of key by one and returns the new value, the counter expires ttl after
+	// it is created.
+	IncrExpire(key string, ttl time.Duration) (int64, error)
+}
+
+// Quota counts the requests of every key in a day (UTC). The requests are counted by every instance of the api on
+// its own, unless the quota is shared through a counter. If the counter fails, the instance counts on its own.
+type Quota struct {
+	mutex   sync.Mutex
+	day     int64
+	used    map[string]int64
+	counter Counter
+}
+
+func NewQuota() *Quota {
+	return &Quota{
+		used: make(map[string]int64),
+	}
+}
+
+// NewSharedQuota counts the requests in counter, so that the daily limit is shared by the instances.
+func NewSharedQuota(counter Counter) *Quota {
+	quota := NewQuota()
+	quota.counter = counter
+	return quota
+}
+
+// Use counts a request of key against the daily limit. It returns whether the request is allowed,
+// the requests left today, and how long until the quota is reset if it is not allowed.
+func (quota *Quota) Use(key string, limit int64, now time.Time) (bool, int64, time.Duration) {
+	day := now.Unix() / 86400
+	reset := time.Unix((day+1)*86400, 0).Sub(now)
+	if quota.counter != nil {
+		used, err := quota.counter.IncrExpire(fmt.Sprintf("quota:%d:%s", day, key), reset)
+		if err == nil {
+			if used > limit {
+				return false, 0, reset
+			}
+			return true, limit - used, 0
+		}
+	}
+	quota.mutex.Lock()
+	defer quota.mutex.Unlock()
+	if day != quota.day {
+		quota.day = day
+		quota.used = make(map[string]int64)
+	}
+	if quota.used[key] >= limit {
+		return false, 0, reset
+	}
+	quota.used[key]++
+	return true, limit - quota.used[key], 0
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter()
+	now := time.Unix(1600000000, 0)
+	for i := 0; i < 3; i++ {
+		ok, remaining, _ := limiter.Allow("ip:1.2.3.4", 2, 3, now)
+		assert.True(t, ok)
+		assert.Equal(t, int64(2-i), remaining)
+	}
+	ok, _, wait := limiter.Allow("ip:1.2.3.4", 2, 3, now)
+	assert.False(t, ok)
+	assert.Equal(t, time.Millisecond*500, wait)
+
+	ok, _, _ = limiter.Allow("ip:5.6.7.8", 2, 3, now)
+	assert.True(t, ok)
+
+	ok, _, _ = limiter.Allow("ip:1.2.3.4", 2, 3, now.Add(time.Millisecond*500))
+	assert.True(t, ok)
+	ok, _, _ = limiter.Allow("ip:1.2.3.4", 2, 3, now.Add(time.Millisecond*500))
+	assert.False(t, ok)
+
+	ok, remaining, _ := limiter.Allow("ip:1.2.3.4", 2, 3, now.Add(time.Minute*2))
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), remaining)
+	assert.Equal(t, 1, len(limiter.buckets))
+}
+
+func TestLimiterSweepLowRate(t *testing.T) {
+	limiter := NewLimiter()
+	now := time.Unix(1600000000, 0)
+	// a token every 100 seconds
+	for i := 0; i < 3; i++ {
+		ok, _, _ := limiter.Allow("key:a", 0.01, 3, now)
+		assert.True(t, ok)
+	}
+	// the bucket idle for more than the sweep slot is not full yet
+	ok, _, wait := limiter.Allow("key:a", 0.01, 3, now.Add(time.Second*90))
+	assert.False(t, ok)
+	assert.InDelta(t, 10, wait.Seconds(), 1e-6)
+	assert.Equal(t, 1, len(limiter.buckets))
+
+	// it is full 300 seconds after it is empty
+	limiter.Allow("key:b", 0.01, 3, now.Add(time.Second*200))
+	assert.Equal(t, 2, len(limiter.buckets))
+	limiter.Allow("key:b", 0.01, 3, now.Add(time.Second*400))
+	assert.Equal(t, 1, len(limiter.buckets))
+}
+
+func TestQuota(t *testing.T) {
+	quota := NewQuota()
+	now := time.Unix(86400*18000+86400-60, 0)
+	for i := 0; i < 2; i++ {
+		ok, remaining, _ := quota.Use("key:a", 2, now)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1-i), remaining)
+	}
+	ok, _, reset := quota.Use("key:a", 2, now)
+	assert.False(t, ok)
+	assert.Equal(t, time.Minute, reset)
+
+	ok, _, _ = quota.Use("key:a", 2, now.Add(time.Minute))
+	assert.True(t, ok)
+}
+
+type testCounter struct {
+	counters map[string]int64
+	err      error
+}
+
+func (counter *testCounter) IncrExpire(key string, ttl time.Duration) (int64, error) {
+	if counter.err != nil {
+		return 0, counter.err
+	}
+	counter.counters[key]++
+	return counter.counters[key], nil
+}
+
+func TestSharedQuota(t *testing.T) {
+	counter := &testCounter{counters: make(map[string]int64)}
+	instances := []*Quota{NewSharedQuota(counter), NewSharedQuota(counter)}
+	now := time.Unix(86400*18000+86400-60, 0)
+	for i := 0; i < 2; i++ {
+		ok, remaining, _ := instances[i].Use("key:a", 2, now)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1-i), remaining)
+	}
+	ok, _, reset := instances[0].Use("key:a", 2, now)
+	assert.False(t, ok)
+	assert.Equal(t, time.Minute, reset)
+	assert.Equal(t, int64(3), counter.counters["quota:18000:key:a"])
+
+	ok, _, _ = instances[1].Use("key:a", 2, now.Add(time.Minute))
+	assert.True(t, ok)
+
+	// the instances count on their own when the counter fails
+	counter.err = fmt.Errorf("redis is down")
+	ok, remaining, _ := instances[0].Use("key:a", 2, now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), remaining)
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := ParseTrustedProxies("10.0.0.0/8, 127.0.0.1, bad")
+	assert.Equal(t, 2, len(trusted))
+
+	var testdata = []struct {
+		remoteAddr   string
+		forwardedFor string
+		expect       string
+	}{
+		{remoteAddr: "1.2.3.4:5678", expect: "1.2.3.4"},
+		{remoteAddr: "1.2.3.4:5678", forwardedFor: "5.6.7.8", expect: "1.2.3.4"},
+		{remoteAddr: "127.0.0.1:5678", forwardedFor: "5.6.7.8", expect: "5.6.7.8"},
+		{remoteAddr: "127.0.0.1:5678", forwardedFor: "9.9.9.9, 5.6.7.8, 10.1.1.1", expect: "5.6.7.8"},
+		{remoteAddr: "127.0.0.1:5678", forwardedFor: "10.1.1.2, 10.1.1.1", expect: "10.1.1.2"},
+		{remoteAddr: "127.0.0.1:5678", forwardedFor: "garbage", expect: "127.0.0.1"},
+		{remoteAddr: "127.0.0.1:5678", expect: "127.0.0.1"},
+		{remoteAddr: "[::1]:5678", forwardedFor: "5.6.7.8", expect: "::1"},
+	}
+	for _, v := range testdata {
+		assert.Equal(t, v.expect, ClientIP(v.remoteAddr, v.forwardedFor, trusted))
+	}
+}
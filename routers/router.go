@@ -31,13 +31,34 @@ func init() {
 		beego.NSRouter("/tokenmap/", &controllers.TokenMapController{}, "post:TokenMap"),
 		beego.NSRouter("/tokenmapreverse/", &controllers.TokenMapController{}, "post:TokenMapReverse"),
 		beego.NSRouter("/getfee/", &controllers.FeeController{}, "post:GetFee"),
+		beego.NSRouter("/getfees/", &controllers.FeeController{}, "post:GetFees"),
 		beego.NSRouter("/checkfee/", &controllers.FeeController{}, "post:CheckFee"),
 		beego.NSRouter("/checkswapfee/", &controllers.FeeController{}, "post:CheckSwapFee"),
+		beego.NSRouter("/precheck/", &controllers.PreCheckController{}, "post:PreCheck"),
 		beego.NSRouter("/transactions/", &controllers.TransactionController{}, "post:Transactions"),
 		beego.NSRouter("/transactionsofaddress/", &controllers.TransactionController{}, "post:TransactionsOfAddress"),
 		beego.NSRouter("/transactionofhash/", &controllers.TransactionController{}, "post:TransactionOfHash"),
 		beego.NSRouter("/transactionofcurve/", &controllers.TransactionController{}, "post:TransactionOfCurve"),
 		beego.NSRouter("/transactionsofstate/", &controllers.TransactionController{}, "post:TransactionsOfState"),
+		beego.NSRouter("/liquidity/", &controllers.LiquidityController{}, "post:Liquidity"),
+		beego.NSRouter("/liquidityhistory/", &controllers.LiquidityController{}, "post:LiquidityHistory"),
+		beego.NSRouter("/admin/tokenbasics/", &controllers.AdminController{}, "get:TokenBasics"),
+		beego.NSRouter("/admin/tokenbasic/", &controllers.AdminController{}, "post:AddTokenBasic;put:UpdateTokenBasic;delete:RemoveTokenBasic"),
+		beego.NSRouter("/admin/token/", &controllers.AdminController{}, "post:AddToken;put:UpdateToken;delete:RemoveToken"),
+		beego.NSRouter("/admin/tokenmaps/", &controllers.AdminController{}, "get:TokenMaps;post:AddTokenMaps"),
+		beego.NSRouter("/admin/tokenmap/", &controllers.AdminController{}, "put:UpdateTokenMap;delete:RemoveTokenMap"),
+		beego.NSRouter("/admin/pricemarket/", &controllers.AdminController{}, "post:AddPriceMarket;put:UpdatePriceMarket;delete:RemovePriceMarket"),
+		beego.NSRouter("/admin/chainfees/", &controllers.AdminController{}, "get:ChainFees"),
+		beego.NSRouter("/admin/chainfee/", &controllers.AdminController{}, "post:AddChainFee;put:UpdateChainFee;delete:RemoveChainFee"),
+		beego.NSRouter("/admin/feepolicies/", &controllers.AdminController{}, "get:FeePolicies"),
+		beego.NSRouter("/admin/feepolicy/", &controllers.AdminController{}, "post:AddFeePolicy"),
+		beego.NSRouter("/admin/property/", &controllers.AdminController{}, "put:SetProperty"),
+		beego.NSRouter("/admin/auditlogs/", &controllers.AdminController{}, "post:AuditLogs"),
+		beego.NSRouter("/admin/feereconciliation/", &controllers.AdminController{}, "post:FeeReconciliation"),
+		beego.NSRouter("/admin/verifytokenmaps/", &controllers.AdminController{}, "post:VerifyTokenMaps"),
+		beego.NSRouter("/admin/tokenmapverifications/", &controllers.AdminController{}, "post:TokenMapVerifications"),
+		beego.NSRouter("/admin/apikeys/", &controllers.AdminController{}, "get:ApiKeys"),
+		beego.NSRouter("/admin/apikey/", &controllers.AdminController{}, "post:AddApiKey;put:UpdateApiKey;delete:RemoveApiKey"),
 	)
 	beego.AddNamespace(ns)
 	beego.Router("/", &controllers.InfoController{}, "*:Get")
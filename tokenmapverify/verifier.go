@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package tokenmapverify
+
+import (
+	"fmt"
+	"github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"poly-bridge/basedef"
+	"poly-bridge/chainsdk"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"time"
+)
+
+// Verifier checks the enabled token maps against the assetHashMap of the lock proxy on the source chain.
+// Only ethereum like source chains are checked, the token maps of the other chains are saved as unverified.
+type Verifier struct {
+	sdks           map[uint64]*chainsdk.EthereumSdkPro
+	proxies        map[uint64]string
+	nftProxies     map[uint64]string
+	nft1155Proxies map[uint64]string
+}
+
+// NewVerifier makes a verifier for the chains having a sdk, the proxy contracts come from the chain listen configs.
+func NewVerifier(chainListenConfigs []*conf.ChainListenConfig, sdks map[uint64]*chainsdk.EthereumSdkPro) *Verifier {
+	verifier := &Verifier{
+		sdks:           sdks,
+		proxies:        make(map[uint64]string),
+		nftProxies:     make(map[uint64]string),
+		nft1155Proxies: make(map[uint64]string),
+	}
+	for _, chainListenConfig := range chainListenConfigs {
+		verifier.proxies[chainListenConfig.ChainId] = chainListenConfig.ProxyContract
+		verifier.nftProxies[chainListenConfig.ChainId] = chainListenConfig.NFTProxyContract
+		verifier.nft1155Proxies[chainListenConfig.ChainId] = chainListenConfig.NFT1155ProxyContract
+	}
+	return verifier
+}
+
+// NewEthereumSdks makes the sdks of the ethereum like chains in the chain listen configs.
+func NewEthereumSdks(chainListenConfigs []*conf.ChainListenConfig) map[uint64]*chainsdk.EthereumSdkPro {
+	sdks := make(map[uint64]*chainsdk.EthereumSdkPro)
+	for _, chainListenConfig := range chainListenConfigs {
+		switch chainListenConfig.ChainId {
+		case basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID, basedef.HECO_CROSSCHAIN_ID:
+			sdks[chainListenConfig.ChainId] = chainsdk.NewEthereumSdkPro(chainListenConfig.GetNodesUrl(), chainListenConfig.ListenSlot, chainListenConfig.ChainId)
+		}
+	}
+	return sdks
+}
+
+// Verify returns the verification of every token map, unverified if its source chain can not be checked.
+func (verifier *Verifier) Verify(tokenMaps []*models.TokenMap) []*models.TokenMapVerification {
+	now := time.Now().Unix()
+	verifications := make([]*models.TokenMapVerification, 0)
+	for _, tokenMap := range tokenMaps {
+		sdk, ok := verifier.sdks[tokenMap.SrcChainId]
+		if !ok {
+			verifications = append(verifications, models.MakeUnverifiedTokenMapVerification(tokenMap,
+				fmt.Sprintf("chain %d can not be verified", tokenMap.SrcChainId), now))
+			continue
+		}
+		var bound []byte
+		var err error
+		if models.IsNFTStandard(tokenMap.Standard) {
+			proxy := verifier.nftProxies[tokenMap.SrcChainId]
+			if tokenMap.Standard == models.TokenTypeErc1155 {
+				proxy = verifier.nft1155Proxies[tokenMap.SrcChainId]
+			}
+			if proxy == "" {
+				verifications = append(verifications, models.MakeUnverifiedTokenMapVerification(tokenMap,
+					fmt.Sprintf("chain %d does not have nft proxy of standard %d", tokenMap.SrcChainId, tokenMap.Standard), now))
+				continue
+			}
+			var asset common.Address
+			asset, err = sdk.GetBoundNFTAsset(common.HexToAddress(proxy), common.HexToAddress(tokenMap.SrcTokenHash), tokenMap.DstChainId)
+			bound = asset.Bytes()
+		} else {
+			proxy := verifier.proxies[tokenMap.SrcChainId]
+			if proxy == "" {
+				verifications = append(verifications, models.MakeUnverifiedTokenMapVerification(tokenMap,
+					fmt.Sprintf("chain %d does not have lock proxy", tokenMap.SrcChainId), now))
+				continue
+			}
+			bound, err = sdk.GetBoundAssetHash(proxy, tokenMap.SrcTokenHash, tokenMap.DstChainId)
+		}
+		verification := models.MakeTokenMapVerification(tokenMap, bound, err, now)
+		if verification.Status != models.TokenMapVerifyMatch {
+			logs.Error("token map %d:%s->%d:%s is not bound in lock proxy, bound: %s, err: %s", tokenMap.SrcChainId, tokenMap.SrcTokenHash,
+				tokenMap.DstChainId, tokenMap.DstTokenHash, verification.BoundHash, verification.Error)
+		}
+		verifications = append(verifications, verification)
+	}
+	return verifications
+}
+
+// VerifyAndSave verifies the enabled token maps and saves the results, results of removed or disabled token maps are deleted.
+func (verifier *Verifier) VerifyAndSave(db *gorm.DB) ([]*models.TokenMapVerification, error) {
+	tokenMaps := make([]*models.TokenMap, 0)
+	if err := db.Where("property = ?", 1).Find(&tokenMaps).Error; err != nil {
+		return nil, err
+	}
+	verifications := verifier.Verify(tokenMaps)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.TokenMapVerification{}).Error; err != nil {
+			return err
+		}
+		if len(verifications) == 0 {
+			return nil
+		}
+		return tx.Create(verifications).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return verifications, nil
+}
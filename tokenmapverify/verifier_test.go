@@ -0,0 +1,79 @@
+package tokenmapverify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"poly-bridge/basedef"
+	"poly-bridge/chainsdk"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testProxy = "0x3333333333333333333333333333333333333333"
+	testBound = "b9478391eec218defa96f7b9a7938cf44e7a2fd5"
+)
+
+// newEthereumNode binds every asset to testBound in the lock proxy testProxy, the calls of other contracts revert.
+func newEthereumNode() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage
+			Method string
+			Params []json.RawMessage
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		result := "0x10"
+		if req.Method == "eth_call" {
+			var msg struct{ To string }
+			_ = json.Unmarshal(req.Params[0], &msg)
+			if strings.ToLower(msg.To) != testProxy {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"execution reverted"}}`, req.Id)
+				return
+			}
+			// the abi encoded bytes of testBound
+			result = fmt.Sprintf("0x%064x%064x%s%s", 32, 20, testBound, strings.Repeat("0", 24))
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"%s"}`, req.Id, result)
+	}))
+}
+
+func TestVerify(t *testing.T) {
+	node := newEthereumNode()
+	defer node.Close()
+	sdk := chainsdk.NewEthereumSdkPro([]string{node.URL}, 3600, basedef.ETHEREUM_CROSSCHAIN_ID)
+	verifier := NewVerifier([]*conf.ChainListenConfig{
+		{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, ProxyContract: testProxy},
+		{ChainId: basedef.BSC_CROSSCHAIN_ID, ProxyContract: "0x4444444444444444444444444444444444444444"},
+		{ChainId: basedef.NEO_CROSSCHAIN_ID, ProxyContract: "edd2862dceb90b945210372d229f453f2b705f4f"},
+	}, map[uint64]*chainsdk.EthereumSdkPro{
+		basedef.ETHEREUM_CROSSCHAIN_ID: sdk,
+		basedef.BSC_CROSSCHAIN_ID:      sdk,
+	})
+
+	tokenMaps := []*models.TokenMap{
+		{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcTokenHash: "dac17f958d2ee523a2206206994597c13d831ec7", DstChainId: basedef.BSC_CROSSCHAIN_ID, DstTokenHash: testBound},
+		{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcTokenHash: "dac17f958d2ee523a2206206994597c13d831ec7", DstChainId: basedef.HECO_CROSSCHAIN_ID, DstTokenHash: "1111111111111111111111111111111111111111"},
+		{SrcChainId: basedef.BSC_CROSSCHAIN_ID, SrcTokenHash: testBound, DstChainId: basedef.ETHEREUM_CROSSCHAIN_ID, DstTokenHash: "dac17f958d2ee523a2206206994597c13d831ec7"},
+		{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcTokenHash: "dac17f958d2ee523a2206206994597c13d831ec7", DstChainId: basedef.BSC_CROSSCHAIN_ID, DstTokenHash: testBound, Standard: models.TokenTypeErc721},
+		{SrcChainId: basedef.NEO_CROSSCHAIN_ID, SrcTokenHash: "17da3881ab2d050fea414c80b3fa8324d756f60e", DstChainId: basedef.ETHEREUM_CROSSCHAIN_ID, DstTokenHash: "dac17f958d2ee523a2206206994597c13d831ec7"},
+	}
+	expects := []uint8{models.TokenMapVerifyMatch, models.TokenMapVerifyMismatch, models.TokenMapVerifyFailed,
+		models.TokenMapVerifyUnverified, models.TokenMapVerifyUnverified}
+
+	verifications := verifier.Verify(tokenMaps)
+	assert.Equal(t, len(tokenMaps), len(verifications))
+	for i, verification := range verifications {
+		assert.Equal(t, expects[i], verification.Status, "%d: %s", i, verification.Error)
+	}
+	// the revert of the bsc proxy does not fail over the node
+	assert.NotNil(t, sdk.GetLatest())
+}